@@ -0,0 +1,293 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"time"
+)
+
+// actionUserStateTransitions lists the user states an action can move to from each user state. A locked action can
+// not be modified.
+var actionUserStateTransitions = map[string][]string{
+	UserState_State_Draft:   {UserState_State_Live, UserState_State_Disable},
+	UserState_State_Live:    {UserState_State_Draft, UserState_State_Disable},
+	UserState_State_Disable: {UserState_State_Draft, UserState_State_Live},
+	UserState_State_Locked:  {},
+}
+
+// ActionStateTransitionError is returned by PublishAction, DisableAction and DraftAction when the action can not move
+// to the requested user state.
+type ActionStateTransitionError struct {
+	// The action ID.
+	ActionID string
+
+	// The current user state of the action.
+	From string
+
+	// The requested user state.
+	To string
+
+	// Why the transition is not allowed.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ActionStateTransitionError) Error() string {
+	return fmt.Sprintf("action %s can not move from %s to %s: %s", e.ActionID, e.From, e.To, e.Reason)
+}
+
+// ActionNotRunnableError is returned when jobs can not run against an action because it is disabled, or because its
+// status is critical, disabled or pending.
+type ActionNotRunnableError struct {
+	// The action ID.
+	ActionID string
+
+	// The user state of the action.
+	UserState string
+
+	// The status code of the action.
+	Status string
+
+	// The status message of the action.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ActionNotRunnableError) Error() string {
+	msg := fmt.Sprintf("action %s can not run jobs (user state %s, status %s)", e.ActionID, e.UserState, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// actionUserState returns the user state of an action. Actions without user state are live.
+func actionUserState(action *Action) string {
+	if action.UserState == nil || action.UserState.State == nil || *action.UserState.State == "" {
+		return UserState_State_Live
+	}
+	return *action.UserState.State
+}
+
+// actionStatus returns the status code of an action, or an empty string.
+func actionStatus(action *Action) string {
+	if action.State == nil {
+		return ""
+	}
+	return stringValue(action.State.StatusCode)
+}
+
+// CheckActionRunnable returns an *ActionNotRunnableError if jobs can not run against the action: its user state is
+// disable, or its status is critical, disabled or pending.
+func CheckActionRunnable(action *Action) error {
+	userState, status := actionUserState(action), actionStatus(action)
+	if userState != UserState_State_Disable && status != ActionState_StatusCode_Critical &&
+		status != ActionState_StatusCode_Disabled && status != ActionState_StatusCode_Pending {
+		return nil
+	}
+	notRunnable := &ActionNotRunnableError{
+		ActionID:  stringValue(action.ID),
+		UserState: userState,
+		Status:    status,
+	}
+	if action.State != nil {
+		notRunnable.Message = stringValue(action.State.StatusMessage)
+	}
+	return notRunnable
+}
+
+// checkActionUserStateTransition returns an *ActionStateTransitionError if the action can not move to the user state.
+func checkActionUserStateTransition(action *Action, to string) error {
+	from := actionUserState(action)
+	transitionErr := &ActionStateTransitionError{ActionID: stringValue(action.ID), From: from, To: to}
+	allowed, known := actionUserStateTransitions[from]
+	switch {
+	case !known:
+		transitionErr.Reason = "unknown user state"
+	case from == UserState_State_Locked:
+		transitionErr.Reason = "the action is locked"
+	case !containsString(allowed, to):
+		transitionErr.Reason = "transition not allowed"
+	case to == UserState_State_Live && actionStatus(action) == ActionState_StatusCode_Critical:
+		transitionErr.Reason = "the action status is critical"
+	case to == UserState_State_Live && actionStatus(action) == ActionState_StatusCode_Pending:
+		transitionErr.Reason = "the action status is pending"
+	default:
+		return nil
+	}
+	return transitionErr
+}
+
+// ChangeActionStateOptions : The PublishAction, DisableAction and DraftAction options.
+type ChangeActionStateOptions struct {
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewChangeActionStateOptions : Instantiate ChangeActionStateOptions
+func (*SchematicsV1) NewChangeActionStateOptions(actionID string) *ChangeActionStateOptions {
+	return &ChangeActionStateOptions{
+		ActionID: core.StringPtr(actionID),
+	}
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *ChangeActionStateOptions) SetActionID(actionID string) *ChangeActionStateOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ChangeActionStateOptions) SetHeaders(param map[string]string) *ChangeActionStateOptions {
+	options.Headers = param
+	return options
+}
+
+// PublishAction : Make an action live
+// Move a draft or disabled action to the live user state so that all jobs can use it. Publishing is refused while the
+// action status is pending or critical.
+func (schematics *SchematicsV1) PublishAction(changeActionStateOptions *ChangeActionStateOptions) (result *Action, response *core.DetailedResponse, err error) {
+	return schematics.changeActionUserState(changeActionStateOptions, UserState_State_Live)
+}
+
+// DisableAction : Disable an action
+// Move a draft or live action to the disable user state so that jobs can not use it.
+func (schematics *SchematicsV1) DisableAction(changeActionStateOptions *ChangeActionStateOptions) (result *Action, response *core.DetailedResponse, err error) {
+	return schematics.changeActionUserState(changeActionStateOptions, UserState_State_Disable)
+}
+
+// DraftAction : Move an action back to draft
+// Move a live or disabled action to the draft user state so that only jobs run by its author can use it.
+func (schematics *SchematicsV1) DraftAction(changeActionStateOptions *ChangeActionStateOptions) (result *Action, response *core.DetailedResponse, err error) {
+	return schematics.changeActionUserState(changeActionStateOptions, UserState_State_Draft)
+}
+
+// changeActionUserState checks the transition of the action to the user state and updates it. An action that is
+// already in the user state is returned without update.
+func (schematics *SchematicsV1) changeActionUserState(changeActionStateOptions *ChangeActionStateOptions, state string) (result *Action, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(changeActionStateOptions, "changeActionStateOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(changeActionStateOptions, "changeActionStateOptions")
+	if err != nil {
+		return
+	}
+
+	action, response, err := schematics.GetAction(&GetActionOptions{
+		ActionID: changeActionStateOptions.ActionID,
+		Headers:  changeActionStateOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if actionUserState(action) == state {
+		result = action
+		return
+	}
+	err = checkActionUserStateTransition(action, state)
+	if err != nil {
+		return
+	}
+	return schematics.UpdateAction(&UpdateActionOptions{
+		ActionID:  changeActionStateOptions.ActionID,
+		UserState: &UserState{State: core.StringPtr(state)},
+		Headers:   changeActionStateOptions.Headers,
+	})
+}
+
+// WaitForActionReadyOptions : The WaitForActionReady options.
+type WaitForActionReadyOptions struct {
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewWaitForActionReadyOptions : Instantiate WaitForActionReadyOptions
+func (*SchematicsV1) NewWaitForActionReadyOptions(actionID string) *WaitForActionReadyOptions {
+	return &WaitForActionReadyOptions{
+		ActionID: core.StringPtr(actionID),
+	}
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *WaitForActionReadyOptions) SetActionID(actionID string) *WaitForActionReadyOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *WaitForActionReadyOptions) SetPollInterval(pollInterval time.Duration) *WaitForActionReadyOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *WaitForActionReadyOptions) SetTimeout(timeout time.Duration) *WaitForActionReadyOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *WaitForActionReadyOptions) SetHeaders(param map[string]string) *WaitForActionReadyOptions {
+	options.Headers = param
+	return options
+}
+
+// WaitForActionReady : Wait for an action to be ready to run jobs
+// Poll GetAction until the action status is no longer pending. The final action is returned; an
+// *ActionNotRunnableError is returned with it when jobs can not run against the action.
+func (schematics *SchematicsV1) WaitForActionReady(waitForActionReadyOptions *WaitForActionReadyOptions) (result *Action, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(waitForActionReadyOptions, "waitForActionReadyOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(waitForActionReadyOptions, "waitForActionReadyOptions")
+	if err != nil {
+		return
+	}
+
+	err = pollUntil(waitForActionReadyOptions.PollInterval, waitForActionReadyOptions.Timeout, func() (bool, error) {
+		var pollErr error
+		result, response, pollErr = schematics.GetAction(&GetActionOptions{
+			ActionID: waitForActionReadyOptions.ActionID,
+			Headers:  waitForActionReadyOptions.Headers,
+		})
+		if pollErr != nil {
+			return false, pollErr
+		}
+		return actionStatus(result) != ActionState_StatusCode_Pending, nil
+	})
+	if err != nil {
+		return
+	}
+	err = CheckActionRunnable(result)
+	return
+}
@@ -0,0 +1,203 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Action lifecycle`, func() {
+	Describe(`CheckActionRunnable(action *Action)`, func() {
+		It(`Accept live and draft actions with a normal status`, func() {
+			Expect(schematicsv1.CheckActionRunnable(&schematicsv1.Action{ID: core.StringPtr("act-1")})).To(Succeed())
+			Expect(schematicsv1.CheckActionRunnable(&schematicsv1.Action{
+				ID:        core.StringPtr("act-1"),
+				UserState: &schematicsv1.UserState{State: core.StringPtr(schematicsv1.UserState_State_Draft)},
+				State:     &schematicsv1.ActionState{StatusCode: core.StringPtr(schematicsv1.ActionState_StatusCode_Normal)},
+			})).To(Succeed())
+		})
+		It(`Reject disabled and critical actions`, func() {
+			err := schematicsv1.CheckActionRunnable(&schematicsv1.Action{
+				ID:        core.StringPtr("act-1"),
+				UserState: &schematicsv1.UserState{State: core.StringPtr(schematicsv1.UserState_State_Disable)},
+			})
+			Expect(err).To(MatchError("action act-1 can not run jobs (user state disable, status )"))
+
+			err = schematicsv1.CheckActionRunnable(&schematicsv1.Action{
+				ID: core.StringPtr("act-1"),
+				State: &schematicsv1.ActionState{
+					StatusCode:    core.StringPtr(schematicsv1.ActionState_StatusCode_Critical),
+					StatusMessage: core.StringPtr("playbook scan failed"),
+				},
+			})
+			notRunnable, ok := err.(*schematicsv1.ActionNotRunnableError)
+			Expect(ok).To(BeTrue())
+			Expect(notRunnable.Status).To(Equal("critical"))
+			Expect(notRunnable.Error()).To(Equal("action act-1 can not run jobs (user state live, status critical): playbook scan failed"))
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		var actions []string
+		var polls int
+		var updates []map[string]interface{}
+
+		BeforeEach(func() {
+			polls = 0
+			updates = nil
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "GET" && path == "/v2/actions/act-1":
+					action := actions[polls]
+					if polls < len(actions)-1 {
+						polls++
+					}
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", action)
+				case req.Method == "PATCH" && path == "/v2/actions/act-1":
+					var body map[string]interface{}
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					updates = append(updates, body)
+					state := body["user_state"].(map[string]interface{})["state"]
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"id": "act-1", "user_state": {"state": "%s"}, "state": {"status_code": "normal"}}`, state)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		newService := func() *schematicsv1.SchematicsV1 {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+			return schematicsService
+		}
+		It(`Invoke PublishAction successfully`, func() {
+			actions = []string{`{"id": "act-1", "user_state": {"state": "draft"}, "state": {"status_code": "normal"}}`}
+			schematicsService := newService()
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.PublishAction(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			result, response, operationErr = schematicsService.PublishAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(*result.UserState.State).To(Equal("live"))
+			Expect(updates).To(Equal([]map[string]interface{}{{"user_state": map[string]interface{}{"state": "live"}}}))
+		})
+		It(`Invoke DisableAction and DraftAction successfully`, func() {
+			actions = []string{`{"id": "act-1", "user_state": {"state": "live"}, "state": {"status_code": "normal"}}`}
+			schematicsService := newService()
+
+			result, _, operationErr := schematicsService.DisableAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(*result.UserState.State).To(Equal("disable"))
+			result, _, operationErr = schematicsService.DraftAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(*result.UserState.State).To(Equal("draft"))
+			Expect(updates).To(HaveLen(2))
+		})
+		It(`Skip the update when the action is already in the user state`, func() {
+			actions = []string{`{"id": "act-1", "state": {"status_code": "normal"}}`}
+			schematicsService := newService()
+
+			result, _, operationErr := schematicsService.PublishAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(*result.ID).To(Equal("act-1"))
+			Expect(updates).To(BeEmpty())
+		})
+		It(`Refuse transitions that are not allowed`, func() {
+			actions = []string{`{"id": "act-1", "user_state": {"state": "locked"}, "state": {"status_code": "normal"}}`}
+			schematicsService := newService()
+
+			_, _, operationErr := schematicsService.DraftAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			transitionErr, ok := operationErr.(*schematicsv1.ActionStateTransitionError)
+			Expect(ok).To(BeTrue())
+			Expect(transitionErr.From).To(Equal("locked"))
+			Expect(transitionErr.Error()).To(Equal("action act-1 can not move from locked to draft: the action is locked"))
+
+			actions = []string{`{"id": "act-1", "user_state": {"state": "draft"}, "state": {"status_code": "critical"}}`}
+			polls = 0
+			_, _, operationErr = schematicsService.PublishAction(schematicsService.NewChangeActionStateOptions("act-1"))
+			Expect(operationErr).To(MatchError("action act-1 can not move from draft to live: the action status is critical"))
+			Expect(updates).To(BeEmpty())
+		})
+		It(`Invoke WaitForActionReady successfully`, func() {
+			actions = []string{
+				`{"id": "act-1", "state": {"status_code": "pending"}}`,
+				`{"id": "act-1", "state": {"status_code": "normal"}}`,
+			}
+			schematicsService := newService()
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.WaitForActionReady(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			options := schematicsService.NewWaitForActionReadyOptions("act-1").SetPollInterval(time.Millisecond)
+			result, response, operationErr = schematicsService.WaitForActionReady(options)
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(*result.State.StatusCode).To(Equal("normal"))
+			Expect(polls).To(Equal(1))
+		})
+		It(`Invoke WaitForActionReady on a critical action`, func() {
+			actions = []string{
+				`{"id": "act-1", "state": {"status_code": "pending"}}`,
+				`{"id": "act-1", "state": {"status_code": "critical", "status_message": "playbook scan failed"}}`,
+			}
+			schematicsService := newService()
+
+			options := schematicsService.NewWaitForActionReadyOptions("act-1").SetPollInterval(time.Millisecond)
+			result, _, operationErr := schematicsService.WaitForActionReady(options)
+			Expect(operationErr).To(MatchError("action act-1 can not run jobs (user state live, status critical): playbook scan failed"))
+			Expect(result).ToNot(BeNil())
+		})
+		It(`Refuse RunActionAndWait on a disabled action`, func() {
+			actions = []string{`{"id": "act-1", "user_state": {"state": "disable"}, "state": {"status_code": "normal"}}`}
+			schematicsService := newService()
+
+			options := schematicsService.NewRunActionAndWaitOptions("testString", "act-1")
+			result, _, operationErr := schematicsService.RunActionAndWait(options)
+			_, ok := operationErr.(*schematicsv1.ActionNotRunnableError)
+			Expect(ok).To(BeTrue())
+			Expect(result).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,204 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var hostnameRegexp = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// actionSourceTypes lists the supported source types, and whether each one requires Git connection details.
+var actionSourceTypes = map[string]bool{
+	ExternalSource_SourceType_ExternalScm:      false,
+	ExternalSource_SourceType_GitHub:           true,
+	ExternalSource_SourceType_GitHubEnterprise: true,
+	ExternalSource_SourceType_GitLab:           true,
+	ExternalSource_SourceType_IbmCloudCatalog:  false,
+	ExternalSource_SourceType_IbmGitLab:        true,
+	ExternalSource_SourceType_Local:            false,
+}
+
+var actionLocations = []string{
+	CreateActionOptions_Location_EuDe,
+	CreateActionOptions_Location_EuGb,
+	CreateActionOptions_Location_UsEast,
+	CreateActionOptions_Location_UsSouth,
+}
+
+// ActionValidationProblem : A problem found in an action definition.
+type ActionValidationProblem struct {
+	// The field with the problem, such as 'source.git.git_repo_url' or 'credentials[1].name'.
+	Field string `json:"field"`
+
+	// The description of the problem.
+	Message string `json:"message"`
+}
+
+// ActionValidationError is returned by ValidateCreateActionOptions and ValidateUpdateActionOptions with all the
+// problems found in an action definition.
+type ActionValidationError struct {
+	// The problems, in field order.
+	Problems []ActionValidationProblem `json:"problems"`
+}
+
+// Error implements the error interface.
+func (e *ActionValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = problem.Field + ": " + problem.Message
+	}
+	return "invalid action definition: " + strings.Join(problems, "; ")
+}
+
+// actionDefinition holds the fields that CreateActionOptions and UpdateActionOptions share.
+type actionDefinition struct {
+	location          *string
+	source            *ExternalSource
+	sourceType        *string
+	commandParameter  *string
+	bastion           *BastionResourceDefinition
+	bastionCredential *VariableData
+	credentials       []VariableData
+	inputs            []VariableData
+	settings          []VariableData
+
+	// Whether the definition is a partial update, whose unset fields keep the values of the current action.
+	update bool
+}
+
+// ValidateCreateActionOptions checks the cross-field rules of an action definition before it is created: the source
+// type is supported and Git source types have Git connection details, the command parameter is one of playbookNames
+// when they are known, the bastion host is an IP address or host name, and credential, input and setting names are
+// set and unique. All problems are returned in a single *ActionValidationError.
+func ValidateCreateActionOptions(createActionOptions *CreateActionOptions, playbookNames []string) error {
+	return validateActionDefinition(&actionDefinition{
+		location:          createActionOptions.Location,
+		source:            createActionOptions.Source,
+		sourceType:        createActionOptions.SourceType,
+		commandParameter:  createActionOptions.CommandParameter,
+		bastion:           createActionOptions.Bastion,
+		bastionCredential: createActionOptions.BastionCredential,
+		credentials:       createActionOptions.Credentials,
+		inputs:            createActionOptions.Inputs,
+		settings:          createActionOptions.Settings,
+	}, playbookNames)
+}
+
+// ValidateUpdateActionOptions checks the fields of an action update with the rules of ValidateCreateActionOptions.
+// Use the PlaybookNames of the current action for playbookNames. A bastion credential can be set without the bastion,
+// which the current action keeps.
+func ValidateUpdateActionOptions(updateActionOptions *UpdateActionOptions, playbookNames []string) error {
+	return validateActionDefinition(&actionDefinition{
+		location:          updateActionOptions.Location,
+		source:            updateActionOptions.Source,
+		sourceType:        updateActionOptions.SourceType,
+		commandParameter:  updateActionOptions.CommandParameter,
+		bastion:           updateActionOptions.Bastion,
+		bastionCredential: updateActionOptions.BastionCredential,
+		credentials:       updateActionOptions.Credentials,
+		inputs:            updateActionOptions.Inputs,
+		settings:          updateActionOptions.Settings,
+		update:            true,
+	}, playbookNames)
+}
+
+func validateActionDefinition(definition *actionDefinition, playbookNames []string) error {
+	var problems []ActionValidationProblem
+	problem := func(field string, format string, args ...interface{}) {
+		problems = append(problems, ActionValidationProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if definition.location != nil && !containsString(actionLocations, *definition.location) {
+		problem("location", "unsupported location %q, expected one of %s", *definition.location, strings.Join(actionLocations, ", "))
+	}
+
+	sourceType := stringValue(definition.sourceType)
+	if definition.source != nil {
+		if definition.source.SourceType != nil {
+			if sourceType != "" && *definition.source.SourceType != sourceType {
+				problem("source.source_type", "%q does not match source_type %q", *definition.source.SourceType, sourceType)
+			}
+			sourceType = *definition.source.SourceType
+		}
+	}
+	if sourceType != "" {
+		requiresGit, supported := actionSourceTypes[sourceType]
+		switch {
+		case !supported:
+			problem("source_type", "unsupported source type %q", sourceType)
+		case requiresGit && (definition.source == nil || definition.source.Git == nil):
+			problem("source.git", "is required for source type %s", sourceType)
+		case requiresGit:
+			if repoURL := stringValue(definition.source.Git.GitRepoURL); repoURL == "" {
+				problem("source.git.git_repo_url", "is required for source type %s", sourceType)
+			} else if parsed, err := url.Parse(repoURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+				problem("source.git.git_repo_url", "%q is not an http or https URL", repoURL)
+			}
+			if definition.source.Git.GitBranch != nil && definition.source.Git.GitRelease != nil {
+				problem("source.git", "git_branch and git_release are mutually exclusive")
+			}
+		}
+	}
+
+	if definition.commandParameter != nil {
+		playbook := *definition.commandParameter
+		switch {
+		case playbook == "":
+			problem("command_parameter", "must not be empty")
+		case len(playbookNames) > 0 && !containsString(playbookNames, playbook):
+			problem("command_parameter", "%q is not a playbook of the action, expected one of %s", playbook, strings.Join(playbookNames, ", "))
+		}
+	}
+
+	if definition.bastion != nil {
+		host := stringValue(definition.bastion.Host)
+		if host == "" {
+			problem("bastion.host", "is required")
+		} else if net.ParseIP(host) == nil && !hostnameRegexp.MatchString(host) {
+			problem("bastion.host", "%q is not an IP address or host name", host)
+		}
+	} else if definition.bastionCredential != nil && !definition.update {
+		problem("bastion_credential", "is set without bastion")
+	}
+
+	validateVariableNames := func(field string, variables []VariableData) {
+		seen := map[string]bool{}
+		for i, variable := range variables {
+			name := stringValue(variable.Name)
+			switch {
+			case name == "":
+				problem(fmt.Sprintf("%s[%d].name", field, i), "is required")
+			case seen[name]:
+				problem(fmt.Sprintf("%s[%d].name", field, i), "duplicate name %q", name)
+			}
+			seen[name] = true
+		}
+	}
+	validateVariableNames("credentials", definition.credentials)
+	validateVariableNames("inputs", definition.inputs)
+	validateVariableNames("settings", definition.settings)
+
+	if len(problems) > 0 {
+		return &ActionValidationError{Problems: problems}
+	}
+	return nil
+}
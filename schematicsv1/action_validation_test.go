@@ -0,0 +1,114 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Action validation`, func() {
+	schematicsService, _ := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+		URL:           "http://schematicsv1modelgenerator.com",
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+	variable := func(name string) schematicsv1.VariableData {
+		return schematicsv1.VariableData{Name: core.StringPtr(name), Value: core.StringPtr("v")}
+	}
+
+	Describe(`ValidateCreateActionOptions(createActionOptions *CreateActionOptions, playbookNames []string)`, func() {
+		It(`Accept a valid definition`, func() {
+			options := schematicsService.NewCreateActionOptions().
+				SetName("web").
+				SetLocation("us-south").
+				SetSourceType("git_hub").
+				SetSource(&schematicsv1.ExternalSource{
+					SourceType: core.StringPtr("git_hub"),
+					Git:        &schematicsv1.ExternalSourceGit{GitRepoURL: core.StringPtr("https://github.com/org/playbooks")},
+				}).
+				SetCommandParameter("site.yml").
+				SetBastion(&schematicsv1.BastionResourceDefinition{Name: core.StringPtr("bastion"), Host: core.StringPtr("169.45.1.2")}).
+				SetBastionCredential(&schematicsv1.VariableData{Name: core.StringPtr("ssh_key")}).
+				SetCredentials([]schematicsv1.VariableData{variable("ssh_key"), variable("api_key")}).
+				SetInputs([]schematicsv1.VariableData{variable("port")})
+			Expect(schematicsv1.ValidateCreateActionOptions(options, []string{"site.yml", "db.yml"})).To(Succeed())
+			Expect(schematicsv1.ValidateCreateActionOptions(options, nil)).To(Succeed())
+		})
+		It(`Report all problems`, func() {
+			options := schematicsService.NewCreateActionOptions().
+				SetLocation("mars").
+				SetSourceType("git_hub").
+				SetSource(&schematicsv1.ExternalSource{SourceType: core.StringPtr("git_hub")}).
+				SetCommandParameter("deploy.yml").
+				SetBastion(&schematicsv1.BastionResourceDefinition{Host: core.StringPtr("bad host!")}).
+				SetCredentials([]schematicsv1.VariableData{variable("ssh_key"), variable("ssh_key"), {}})
+			err := schematicsv1.ValidateCreateActionOptions(options, []string{"site.yml"})
+			Expect(err).ToNot(BeNil())
+			validationErr, ok := err.(*schematicsv1.ActionValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationErr.Problems).To(Equal([]schematicsv1.ActionValidationProblem{
+				{Field: "location", Message: `unsupported location "mars", expected one of eu-de, eu-gb, us-east, us-south`},
+				{Field: "source.git", Message: "is required for source type git_hub"},
+				{Field: "command_parameter", Message: `"deploy.yml" is not a playbook of the action, expected one of site.yml`},
+				{Field: "bastion.host", Message: `"bad host!" is not an IP address or host name`},
+				{Field: "credentials[1].name", Message: `duplicate name "ssh_key"`},
+				{Field: "credentials[2].name", Message: "is required"},
+			}))
+			Expect(err.Error()).To(HavePrefix("invalid action definition: location: unsupported location"))
+		})
+		It(`Check Git connection details`, func() {
+			options := schematicsService.NewCreateActionOptions().
+				SetSourceType("git_lab").
+				SetSource(&schematicsv1.ExternalSource{
+					SourceType: core.StringPtr("git_hub"),
+					Git: &schematicsv1.ExternalSourceGit{
+						GitRepoURL: core.StringPtr("git@github.com:org/playbooks.git"),
+						GitBranch:  core.StringPtr("main"),
+						GitRelease: core.StringPtr("v1"),
+					},
+				}).
+				SetBastionCredential(&schematicsv1.VariableData{Name: core.StringPtr("ssh_key")})
+			err := schematicsv1.ValidateCreateActionOptions(options, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.(*schematicsv1.ActionValidationError).Problems).To(Equal([]schematicsv1.ActionValidationProblem{
+				{Field: "source.source_type", Message: `"git_hub" does not match source_type "git_lab"`},
+				{Field: "source.git.git_repo_url", Message: `"git@github.com:org/playbooks.git" is not an http or https URL`},
+				{Field: "source.git", Message: "git_branch and git_release are mutually exclusive"},
+				{Field: "bastion_credential", Message: "is set without bastion"},
+			}))
+		})
+	})
+
+	Describe(`ValidateUpdateActionOptions(updateActionOptions *UpdateActionOptions, playbookNames []string)`, func() {
+		It(`Validate only the fields that are set`, func() {
+			options := schematicsService.NewUpdateActionOptions("action-1")
+			Expect(schematicsv1.ValidateUpdateActionOptions(options, []string{"site.yml"})).To(Succeed())
+
+			options.SetCommandParameter("db.yml").SetSettings([]schematicsv1.VariableData{variable("a"), variable("a")})
+			err := schematicsv1.ValidateUpdateActionOptions(options, []string{"site.yml"})
+			Expect(err).ToNot(BeNil())
+			Expect(err.(*schematicsv1.ActionValidationError).Problems).To(HaveLen(2))
+		})
+		It(`Accept a bastion credential without the bastion`, func() {
+			options := schematicsService.NewUpdateActionOptions("action-1").
+				SetBastionCredential(&schematicsv1.VariableData{Name: core.StringPtr("ssh_key"), Value: core.StringPtr("key")})
+			Expect(schematicsv1.ValidateUpdateActionOptions(options, nil)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,740 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Names of the groups that every Ansible inventory implicitly contains.
+const (
+	AnsibleInventory_Group_All       = "all"
+	AnsibleInventory_Group_Ungrouped = "ungrouped"
+)
+
+var (
+	ansibleGroupRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_\-]*$`)
+	ansibleHostRegexp  = regexp.MustCompile(`^[A-Za-z0-9_\[]([A-Za-z0-9_.:\-]|\[[A-Za-z0-9]+:[A-Za-z0-9]+(:[0-9]+)?\])*$`)
+)
+
+// AnsibleInventory : An Ansible inventory of hosts and groups.
+// The 'all' group is implicit: its vars may be set, but it has no hosts or children of its own. Hosts that belong to no
+// group are the members of the implicit 'ungrouped' group.
+type AnsibleInventory struct {
+	// The variables of each host, by host name. Every host of the inventory has an entry.
+	Hosts map[string]map[string]interface{} `json:"hosts"`
+
+	// The groups, by group name.
+	Groups map[string]*AnsibleInventoryGroup `json:"groups"`
+}
+
+// AnsibleInventoryGroup : A group of an Ansible inventory.
+type AnsibleInventoryGroup struct {
+	// The names of the hosts of the group.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// The names of the child groups.
+	Children []string `json:"children,omitempty"`
+
+	// The group variables.
+	Vars map[string]interface{} `json:"vars,omitempty"`
+}
+
+// NewAnsibleInventory : Instantiate an empty AnsibleInventory
+func NewAnsibleInventory() *AnsibleInventory {
+	return &AnsibleInventory{
+		Hosts:  map[string]map[string]interface{}{},
+		Groups: map[string]*AnsibleInventoryGroup{},
+	}
+}
+
+// AddHost adds a host to a group, creating both if needed, and merges vars into the host variables. An empty group, or
+// the 'all' or 'ungrouped' group, adds the host without group.
+func (inventory *AnsibleInventory) AddHost(group string, host string, vars map[string]interface{}) error {
+	if !ansibleHostRegexp.MatchString(host) {
+		return fmt.Errorf("invalid host name %q", host)
+	}
+	if inventory.Hosts[host] == nil {
+		inventory.Hosts[host] = map[string]interface{}{}
+	}
+	for key, value := range vars {
+		inventory.Hosts[host][key] = value
+	}
+	if hostGroup(group) == "" {
+		return nil
+	}
+	target, err := inventory.group(group)
+	if err != nil {
+		return err
+	}
+	if !containsString(target.Hosts, host) {
+		target.Hosts = append(target.Hosts, host)
+	}
+	return nil
+}
+
+// AddChild makes child a child group of parent, creating both if needed.
+func (inventory *AnsibleInventory) AddChild(parent string, child string) error {
+	if parent == AnsibleInventory_Group_All && child == AnsibleInventory_Group_Ungrouped {
+		return nil
+	}
+	if child == AnsibleInventory_Group_All || child == AnsibleInventory_Group_Ungrouped {
+		return fmt.Errorf("group %q can not be a child group", child)
+	}
+	if _, err := inventory.group(child); err != nil {
+		return err
+	}
+	if parent == AnsibleInventory_Group_All {
+		return nil
+	}
+	target, err := inventory.group(parent)
+	if err != nil {
+		return err
+	}
+	if !containsString(target.Children, child) {
+		target.Children = append(target.Children, child)
+	}
+	return nil
+}
+
+// SetGroupVars merges vars into the variables of a group, creating the group if needed.
+func (inventory *AnsibleInventory) SetGroupVars(group string, vars map[string]interface{}) error {
+	target, err := inventory.group(group)
+	if err != nil {
+		return err
+	}
+	if target.Vars == nil {
+		target.Vars = map[string]interface{}{}
+	}
+	for key, value := range vars {
+		target.Vars[key] = value
+	}
+	return nil
+}
+
+// group returns the named group, creating it if needed.
+func (inventory *AnsibleInventory) group(name string) (*AnsibleInventoryGroup, error) {
+	if !ansibleGroupRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid group name %q", name)
+	}
+	if inventory.Groups[name] == nil {
+		inventory.Groups[name] = &AnsibleInventoryGroup{}
+	}
+	return inventory.Groups[name], nil
+}
+
+// Validate checks host and group names, that the hosts and child groups of every group exist and that child groups do
+// not form a cycle. All problems found are reported in a single error.
+func (inventory *AnsibleInventory) Validate() error {
+	var problems []string
+	for _, host := range sortedKeys(inventory.Hosts) {
+		if !ansibleHostRegexp.MatchString(host) {
+			problems = append(problems, fmt.Sprintf("invalid host name %q", host))
+		}
+	}
+	for _, name := range inventory.groupNames() {
+		group := inventory.Groups[name]
+		if !ansibleGroupRegexp.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("invalid group name %q", name))
+		}
+		if name == AnsibleInventory_Group_All && (len(group.Hosts) > 0 || len(group.Children) > 0) {
+			problems = append(problems, fmt.Sprintf("group %q can only have vars", name))
+		}
+		for _, host := range group.Hosts {
+			if _, found := inventory.Hosts[host]; !found {
+				problems = append(problems, fmt.Sprintf("group %s: unknown host %q", name, host))
+			}
+		}
+		for _, child := range group.Children {
+			if inventory.Groups[child] == nil {
+				problems = append(problems, fmt.Sprintf("group %s: unknown child group %q", name, child))
+			}
+		}
+	}
+	if cycle := inventory.childCycle(); cycle != nil {
+		problems = append(problems, fmt.Sprintf("child groups form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid ansible inventory: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// childCycle returns a cycle of child groups, or nil.
+func (inventory *AnsibleInventory) childCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, step := range path {
+				if step == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		if group := inventory.Groups[name]; group != nil {
+			for _, child := range group.Children {
+				if cycle := visit(child); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, name := range inventory.groupNames() {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// groupNames returns the sorted names of the groups.
+func (inventory *AnsibleInventory) groupNames() []string {
+	names := make([]string, 0, len(inventory.Groups))
+	for name := range inventory.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ungroupedHosts returns the sorted hosts that belong to no group.
+func (inventory *AnsibleInventory) ungroupedHosts() []string {
+	grouped := map[string]bool{}
+	for _, group := range inventory.Groups {
+		for _, host := range group.Hosts {
+			grouped[host] = true
+		}
+	}
+	var hosts []string
+	for _, host := range sortedKeys(inventory.Hosts) {
+		if !grouped[host] {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// topLevelGroups returns the sorted groups, other than 'all', that are not the child of another group.
+func (inventory *AnsibleInventory) topLevelGroups() []string {
+	children := map[string]bool{}
+	for _, group := range inventory.Groups {
+		for _, child := range group.Children {
+			children[child] = true
+		}
+	}
+	var names []string
+	for _, name := range inventory.groupNames() {
+		if name != AnsibleInventory_Group_All && !children[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ParseAnsibleInventoryINI parses an inventory in the Ansible INI format.
+func ParseAnsibleInventoryINI(ini string) (*AnsibleInventory, error) {
+	inventory := NewAnsibleInventory()
+	group, kind := "", "hosts"
+	for number, line := range strings.Split(ini, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				err = fmt.Errorf("unterminated section header")
+				break
+			}
+			group, kind = line[1:len(line)-1], "hosts"
+			if separator := strings.Index(group, ":"); separator >= 0 {
+				group, kind = group[:separator], group[separator+1:]
+			}
+			switch kind {
+			case "hosts":
+				if group != AnsibleInventory_Group_All && group != AnsibleInventory_Group_Ungrouped {
+					_, err = inventory.group(group)
+				}
+			case "vars":
+				_, err = inventory.group(group)
+			case "children":
+				if group != AnsibleInventory_Group_All {
+					_, err = inventory.group(group)
+				}
+			default:
+				err = fmt.Errorf("unknown section type %q", kind)
+			}
+		case kind == "vars":
+			separator := strings.Index(line, "=")
+			if separator <= 0 {
+				err = fmt.Errorf("expected NAME=VALUE")
+				break
+			}
+			err = inventory.SetGroupVars(group, map[string]interface{}{
+				strings.TrimSpace(line[:separator]): unquoteINIValue(strings.TrimSpace(line[separator+1:])),
+			})
+		case kind == "children":
+			err = inventory.AddChild(group, line)
+		default:
+			var fields []string
+			fields, err = splitINIFields(line)
+			if err != nil {
+				break
+			}
+			vars := map[string]interface{}{}
+			for _, field := range fields[1:] {
+				separator := strings.Index(field, "=")
+				if separator <= 0 {
+					err = fmt.Errorf("expected NAME=VALUE, found %q", field)
+					break
+				}
+				vars[field[:separator]] = field[separator+1:]
+			}
+			if err == nil {
+				err = inventory.AddHost(group, fields[0], vars)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", number+1, err.Error())
+		}
+	}
+
+	if err := inventory.Validate(); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// splitINIFields splits an inventory host line into whitespace separated fields. Single and double quotes group
+// characters, backslashes escape characters within double quotes, and a field starting with '#' starts a comment.
+func splitINIFields(line string) (fields []string, err error) {
+	var field strings.Builder
+	inField := false
+	var quote rune
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == '"' && r == '\\' && i+1 < len(runes):
+			i++
+			field.WriteRune(runes[i])
+		case quote != 0:
+			field.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inField = r, true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		case r == '#' && !inField:
+			i = len(runes)
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty host line")
+	}
+	return
+}
+
+// unquoteINIValue removes the quotes around a group variable value.
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if value[0] == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// INI serializes the inventory in the Ansible INI format. Hosts without group come first, followed by the sections of
+// each group in name order. The variables of a host are written on its first line.
+func (inventory *AnsibleInventory) INI() string {
+	var ini strings.Builder
+	written := map[string]bool{}
+	writeHost := func(host string) {
+		ini.WriteString(host)
+		if !written[host] {
+			for _, key := range sortedKeys(inventory.Hosts[host]) {
+				ini.WriteString(" " + key + "=" + formatINIValue(inventory.Hosts[host][key], true))
+			}
+			written[host] = true
+		}
+		ini.WriteString("\n")
+	}
+
+	for _, host := range inventory.ungroupedHosts() {
+		writeHost(host)
+	}
+	for _, name := range inventory.groupNames() {
+		group := inventory.Groups[name]
+		// Groups with vars or children only are declared by those sections.
+		if name != AnsibleInventory_Group_All && (len(group.Hosts) > 0 || len(group.Vars) == 0 && len(group.Children) == 0) {
+			writeINISection(&ini, "["+name+"]")
+			for _, host := range group.Hosts {
+				writeHost(host)
+			}
+		}
+		if len(group.Vars) > 0 {
+			writeINISection(&ini, "["+name+":vars]")
+			for _, key := range sortedKeys(group.Vars) {
+				ini.WriteString(key + "=" + formatINIValue(group.Vars[key], false) + "\n")
+			}
+		}
+		if len(group.Children) > 0 {
+			writeINISection(&ini, "["+name+":children]")
+			for _, child := range group.Children {
+				ini.WriteString(child + "\n")
+			}
+		}
+	}
+	return ini.String()
+}
+
+func writeINISection(ini *strings.Builder, header string) {
+	if ini.Len() > 0 {
+		ini.WriteString("\n")
+	}
+	ini.WriteString(header + "\n")
+}
+
+// formatINIValue formats a variable value for the INI format. Values that are not strings are written as JSON. Host
+// variable values with spaces or quotes are quoted.
+func formatINIValue(value interface{}, quote bool) string {
+	text, ok := value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		text = string(encoded)
+	}
+	if quote && (text == "" || strings.ContainsAny(text, " \t\"'#\\")) {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// ansibleYAMLGroup is a group of the Ansible YAML inventory format.
+type ansibleYAMLGroup struct {
+	Hosts    map[string]map[string]interface{} `yaml:"hosts,omitempty"`
+	Vars     map[string]interface{}            `yaml:"vars,omitempty"`
+	Children map[string]*ansibleYAMLGroup      `yaml:"children,omitempty"`
+}
+
+// ParseAnsibleInventoryYAML parses an inventory in the Ansible YAML format, rooted at the 'all' group.
+func ParseAnsibleInventoryYAML(data []byte) (*AnsibleInventory, error) {
+	var root map[string]*ansibleYAMLGroup
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("decoding ansible inventory: %s", err.Error())
+	}
+
+	inventory := NewAnsibleInventory()
+	var add func(name string, group *ansibleYAMLGroup) error
+	add = func(name string, group *ansibleYAMLGroup) error {
+		if name != AnsibleInventory_Group_All && name != AnsibleInventory_Group_Ungrouped {
+			if _, err := inventory.group(name); err != nil {
+				return err
+			}
+		}
+		if group == nil {
+			return nil
+		}
+		for _, host := range sortedKeys(group.Hosts) {
+			if err := inventory.AddHost(hostGroup(name), host, normalizeYAMLMap(group.Hosts[host])); err != nil {
+				return err
+			}
+		}
+		if len(group.Vars) > 0 {
+			if err := inventory.SetGroupVars(name, normalizeYAMLMap(group.Vars)); err != nil {
+				return err
+			}
+		}
+		for _, child := range sortedKeys(group.Children) {
+			if err := inventory.AddChild(name, child); err != nil {
+				return err
+			}
+			if err := add(child, group.Children[child]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, name := range sortedKeys(root) {
+		if err := add(name, root[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := inventory.Validate(); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// YAML serializes the inventory in the Ansible YAML format. A host's variables are written at its first occurrence and
+// a group's content under its first parent.
+func (inventory *AnsibleInventory) YAML() ([]byte, error) {
+	hostsWritten := map[string]bool{}
+	groupsWritten := map[string]bool{}
+	hosts := func(names []string) map[string]map[string]interface{} {
+		if len(names) == 0 {
+			return nil
+		}
+		hosts := map[string]map[string]interface{}{}
+		for _, host := range names {
+			if !hostsWritten[host] && len(inventory.Hosts[host]) > 0 {
+				hosts[host] = inventory.Hosts[host]
+			} else {
+				hosts[host] = nil
+			}
+			hostsWritten[host] = true
+		}
+		return hosts
+	}
+	var build func(name string) *ansibleYAMLGroup
+	build = func(name string) *ansibleYAMLGroup {
+		if groupsWritten[name] {
+			return nil
+		}
+		groupsWritten[name] = true
+		group := inventory.Groups[name]
+		node := &ansibleYAMLGroup{Hosts: hosts(group.Hosts), Vars: group.Vars}
+		children := append([]string{}, group.Children...)
+		sort.Strings(children)
+		for _, child := range children {
+			if node.Children == nil {
+				node.Children = map[string]*ansibleYAMLGroup{}
+			}
+			node.Children[child] = build(child)
+		}
+		return node
+	}
+
+	all := &ansibleYAMLGroup{Hosts: hosts(inventory.ungroupedHosts())}
+	if group := inventory.Groups[AnsibleInventory_Group_All]; group != nil {
+		all.Vars = group.Vars
+	}
+	for _, name := range inventory.topLevelGroups() {
+		if all.Children == nil {
+			all.Children = map[string]*ansibleYAMLGroup{}
+		}
+		all.Children[name] = build(name)
+	}
+	return yaml.Marshal(map[string]*ansibleYAMLGroup{AnsibleInventory_Group_All: all})
+}
+
+// ParseAnsibleInventoryJSON parses an inventory in the JSON format of Ansible dynamic inventory scripts: an object of
+// groups, where a group is either a list of hosts or an object with hosts, vars and children, and the host variables
+// are under '_meta.hostvars'.
+func ParseAnsibleInventoryJSON(data []byte) (*AnsibleInventory, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("decoding ansible inventory: %s", err.Error())
+	}
+
+	inventory := NewAnsibleInventory()
+	for _, name := range sortedKeys(root) {
+		if name == "_meta" {
+			continue
+		}
+		var group struct {
+			Hosts    []string               `json:"hosts"`
+			Vars     map[string]interface{} `json:"vars"`
+			Children []string               `json:"children"`
+		}
+		if err := json.Unmarshal(root[name], &group.Hosts); err != nil {
+			if err := json.Unmarshal(root[name], &group); err != nil {
+				return nil, fmt.Errorf("decoding group %s: %s", name, err.Error())
+			}
+		}
+		for _, host := range group.Hosts {
+			if err := inventory.AddHost(hostGroup(name), host, nil); err != nil {
+				return nil, err
+			}
+		}
+		if len(group.Vars) > 0 {
+			if err := inventory.SetGroupVars(name, group.Vars); err != nil {
+				return nil, err
+			}
+		}
+		for _, child := range group.Children {
+			if err := inventory.AddChild(name, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if meta, found := root["_meta"]; found {
+		var decoded struct {
+			HostVars map[string]map[string]interface{} `json:"hostvars"`
+		}
+		if err := json.Unmarshal(meta, &decoded); err != nil {
+			return nil, fmt.Errorf("decoding _meta: %s", err.Error())
+		}
+		for _, host := range sortedKeys(decoded.HostVars) {
+			if err := inventory.AddHost("", host, decoded.HostVars[host]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := inventory.Validate(); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// JSON serializes the inventory in the JSON format of Ansible dynamic inventory scripts.
+func (inventory *AnsibleInventory) JSON() ([]byte, error) {
+	type jsonGroup struct {
+		Hosts    []string               `json:"hosts,omitempty"`
+		Vars     map[string]interface{} `json:"vars,omitempty"`
+		Children []string               `json:"children,omitempty"`
+	}
+	root := map[string]interface{}{}
+
+	hostVars := map[string]map[string]interface{}{}
+	for host, vars := range inventory.Hosts {
+		if len(vars) > 0 {
+			hostVars[host] = vars
+		}
+	}
+	root["_meta"] = map[string]interface{}{"hostvars": hostVars}
+
+	all := jsonGroup{Children: inventory.topLevelGroups()}
+	if ungrouped := inventory.ungroupedHosts(); len(ungrouped) > 0 {
+		all.Children = append(all.Children, AnsibleInventory_Group_Ungrouped)
+		root[AnsibleInventory_Group_Ungrouped] = jsonGroup{Hosts: ungrouped}
+	}
+	if group := inventory.Groups[AnsibleInventory_Group_All]; group != nil {
+		all.Vars = group.Vars
+	}
+	root[AnsibleInventory_Group_All] = all
+
+	for name, group := range inventory.Groups {
+		if name != AnsibleInventory_Group_All {
+			root[name] = jsonGroup{Hosts: group.Hosts, Vars: group.Vars, Children: group.Children}
+		}
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// AnsibleInventory parses the INI inventory of the record.
+func (record *InventoryResourceRecord) AnsibleInventory() (*AnsibleInventory, error) {
+	if record.InventoriesIni == nil {
+		return NewAnsibleInventory(), nil
+	}
+	return ParseAnsibleInventoryINI(*record.InventoriesIni)
+}
+
+// AnsibleInventory parses the materialized INI inventory of the job.
+func (action *JobDataAction) AnsibleInventory() (*AnsibleInventory, error) {
+	if action.MaterializedInventory == nil {
+		return NewAnsibleInventory(), nil
+	}
+	return ParseAnsibleInventoryINI(*action.MaterializedInventory)
+}
+
+// hostGroup returns the group that hosts listed under name are added to.
+func hostGroup(name string) string {
+	if name == AnsibleInventory_Group_All || name == AnsibleInventory_Group_Ungrouped {
+		return ""
+	}
+	return name
+}
+
+// normalizeYAMLMap converts the nested maps decoded by the YAML decoder to maps with string keys, as decoded from
+// JSON.
+func normalizeYAMLMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	normalized := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		normalized[key] = normalizeYAMLValue(value)
+	}
+	return normalized
+}
+
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			normalized[fmt.Sprint(key)] = normalizeYAMLValue(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(typed))
+		for i, item := range typed {
+			normalized[i] = normalizeYAMLValue(item)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+// sortedKeys returns the sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
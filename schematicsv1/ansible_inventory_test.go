@@ -0,0 +1,204 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Ansible inventories`, func() {
+	inventoryINI := `# Generated inventory
+bastion.example.com ansible_user=root
+
+[web]
+web1.example.com ansible_host=10.0.0.1 motd="hello world"
+web[02:04].example.com
+
+[db]
+db1.example.com   # primary
+web1.example.com
+
+[web:vars]
+http_port=80
+greeting = "hi there"
+
+[prod:children]
+web
+db
+
+[all:vars]
+ansible_python_interpreter=/usr/bin/python3
+`
+
+	Describe(`ParseAnsibleInventoryINI(ini string)`, func() {
+		It(`Parse an INI inventory`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI(inventoryINI)
+			Expect(err).To(BeNil())
+			Expect(inventory.Hosts).To(HaveLen(4))
+			Expect(inventory.Hosts["bastion.example.com"]).To(Equal(map[string]interface{}{"ansible_user": "root"}))
+			Expect(inventory.Hosts["web1.example.com"]).To(Equal(map[string]interface{}{"ansible_host": "10.0.0.1", "motd": "hello world"}))
+			Expect(inventory.Groups["web"].Hosts).To(Equal([]string{"web1.example.com", "web[02:04].example.com"}))
+			Expect(inventory.Groups["web"].Vars).To(Equal(map[string]interface{}{"http_port": "80", "greeting": "hi there"}))
+			Expect(inventory.Groups["db"].Hosts).To(Equal([]string{"db1.example.com", "web1.example.com"}))
+			Expect(inventory.Groups["prod"].Children).To(Equal([]string{"web", "db"}))
+			Expect(inventory.Groups["all"].Vars["ansible_python_interpreter"]).To(Equal("/usr/bin/python3"))
+		})
+		It(`Serialize and parse again`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI(inventoryINI)
+			Expect(err).To(BeNil())
+			ini := inventory.INI()
+			Expect(ini).To(HavePrefix("bastion.example.com ansible_user=root\n\n[all:vars]\n"))
+			Expect(ini).To(ContainSubstring(`web1.example.com ansible_host=10.0.0.1 motd="hello world"`))
+
+			parsed, err := schematicsv1.ParseAnsibleInventoryINI(ini)
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(inventory))
+		})
+		It(`Parse hosts listed under the all group`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI("[all]\nbastion ansible_user=root\n\n[web]\nweb1\n")
+			Expect(err).To(BeNil())
+			Expect(inventory.Hosts["bastion"]).To(Equal(map[string]interface{}{"ansible_user": "root"}))
+			Expect(inventory.Groups).ToNot(HaveKey("all"))
+			Expect(inventory.Groups["web"].Hosts).To(Equal([]string{"web1"}))
+			Expect(inventory.INI()).To(HavePrefix("bastion ansible_user=root\n"))
+		})
+		It(`Accept group names with dashes`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI("[web-servers]\nweb1\n\n[prod:children]\nweb-servers\n")
+			Expect(err).To(BeNil())
+			Expect(inventory.Groups["web-servers"].Hosts).To(Equal([]string{"web1"}))
+			Expect(inventory.Groups["prod"].Children).To(Equal([]string{"web-servers"}))
+			Expect(inventory.Validate()).To(Succeed())
+		})
+		It(`Reject invalid inventories`, func() {
+			for ini, message := range map[string]string{
+				"[web servers]\nweb1":              `line 1: invalid group name "web servers"`,
+				"[web]\nweb1 bad":                  `line 2: expected NAME=VALUE, found "bad"`,
+				"[web]\nweb/1":                     `line 2: invalid host name "web/1"`,
+				"[web]\nweb1 motd=\"hi":            `line 2: unterminated quote`,
+				"[web:meta]\nweb1":                 `line 1: unknown section type "meta"`,
+				"[web:vars]\nhttp_port":            `line 2: expected NAME=VALUE`,
+				"[a:children]\nb\n[b:children]\na": `child groups form a cycle: a -> b -> a`,
+				"[web":                             `line 1: unterminated section header`,
+			} {
+				_, err := schematicsv1.ParseAnsibleInventoryINI(ini)
+				Expect(err).ToNot(BeNil(), ini)
+				Expect(err.Error()).To(ContainSubstring(message))
+			}
+		})
+	})
+	Describe(`Ansible YAML and JSON inventory formats`, func() {
+		It(`Convert an inventory to YAML and back`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI(inventoryINI)
+			Expect(err).To(BeNil())
+
+			data, err := inventory.YAML()
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(HavePrefix("all:\n  hosts:\n    bastion.example.com:\n      ansible_user: root\n"))
+
+			parsed, err := schematicsv1.ParseAnsibleInventoryYAML(data)
+			Expect(err).To(BeNil())
+			Expect(parsed.Hosts).To(Equal(inventory.Hosts))
+			Expect(parsed.Groups["prod"].Children).To(ConsistOf("web", "db"))
+			Expect(parsed.Groups["web"].Vars).To(Equal(inventory.Groups["web"].Vars))
+			Expect(parsed.Groups["db"].Hosts).To(ConsistOf("db1.example.com", "web1.example.com"))
+		})
+		It(`Parse a YAML inventory with nested values`, func() {
+			parsed, err := schematicsv1.ParseAnsibleInventoryYAML([]byte(`
+all:
+  children:
+    web:
+      hosts:
+        web1:
+          ports: [80, 443]
+          labels: {tier: front}
+        web2:
+`))
+			Expect(err).To(BeNil())
+			Expect(parsed.Groups["web"].Hosts).To(Equal([]string{"web1", "web2"}))
+			Expect(parsed.Hosts["web1"]["labels"]).To(Equal(map[string]interface{}{"tier": "front"}))
+			Expect(parsed.INI()).To(ContainSubstring(`web1 labels="{\"tier\":\"front\"}" ports=[80,443]`))
+		})
+		It(`Parse a YAML inventory with hosts under the all group`, func() {
+			parsed, err := schematicsv1.ParseAnsibleInventoryYAML([]byte(`
+all:
+  hosts:
+    bastion:
+      ansible_user: root
+  children:
+    web:
+      hosts:
+        web1:
+`))
+			Expect(err).To(BeNil())
+			Expect(parsed.Hosts["bastion"]).To(Equal(map[string]interface{}{"ansible_user": "root"}))
+			Expect(parsed.Groups).ToNot(HaveKey("all"))
+			Expect(parsed.Groups["web"].Hosts).To(Equal([]string{"web1"}))
+		})
+		It(`Convert an inventory to JSON and back`, func() {
+			inventory, err := schematicsv1.ParseAnsibleInventoryINI(inventoryINI)
+			Expect(err).To(BeNil())
+
+			data, err := inventory.JSON()
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(ContainSubstring(`"ungrouped": {`))
+
+			parsed, err := schematicsv1.ParseAnsibleInventoryJSON(data)
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(inventory))
+		})
+		It(`Parse a dynamic inventory script output`, func() {
+			parsed, err := schematicsv1.ParseAnsibleInventoryJSON([]byte(`{
+				"web": ["web1", "web2"],
+				"prod": {"children": ["web"], "vars": {"env": "prod"}},
+				"_meta": {"hostvars": {"web1": {"ansible_host": "10.0.0.1"}}}
+			}`))
+			Expect(err).To(BeNil())
+			Expect(parsed.Groups["web"].Hosts).To(Equal([]string{"web1", "web2"}))
+			Expect(parsed.Hosts["web1"]["ansible_host"]).To(Equal("10.0.0.1"))
+
+			_, err = schematicsv1.ParseAnsibleInventoryJSON([]byte(`{"web": 1}`))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+	Describe(`Inventories of records`, func() {
+		It(`Parse the inventory of an inventory record and a job`, func() {
+			record := &schematicsv1.InventoryResourceRecord{InventoriesIni: core.StringPtr(inventoryINI)}
+			inventory, err := record.AnsibleInventory()
+			Expect(err).To(BeNil())
+			Expect(inventory.Groups).To(HaveKey("prod"))
+
+			action := &schematicsv1.JobDataAction{}
+			inventory, err = action.AnsibleInventory()
+			Expect(err).To(BeNil())
+			Expect(inventory.Hosts).To(BeEmpty())
+		})
+		It(`Build an inventory`, func() {
+			inventory := schematicsv1.NewAnsibleInventory()
+			Expect(inventory.AddHost("web", "web1", map[string]interface{}{"ansible_port": 2222})).To(Succeed())
+			Expect(inventory.AddChild("prod", "web")).To(Succeed())
+			Expect(inventory.SetGroupVars("prod", map[string]interface{}{"env": "prod"})).To(Succeed())
+			Expect(inventory.AddHost("web servers", "web1", nil)).ToNot(Succeed())
+			Expect(inventory.AddHost("all", "bastion", nil)).To(Succeed())
+			Expect(inventory.Groups).ToNot(HaveKey("all"))
+			Expect(inventory.Validate()).To(Succeed())
+			Expect(inventory.INI()).To(Equal("bastion\n\n[prod:vars]\nenv=prod\n\n[prod:children]\nweb\n\n[web]\nweb1 ansible_port=2222\n"))
+		})
+	})
+})
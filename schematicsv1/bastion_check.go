@@ -0,0 +1,494 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBastionUser is the user that CheckBastion authenticates as.
+	DefaultBastionUser = "root"
+
+	// DefaultBastionPort is the SSH port of a bastion host.
+	DefaultBastionPort = 22
+
+	// DefaultBastionConnectTimeout is the maximum time spent connecting to a bastion host and completing the SSH
+	// handshake.
+	DefaultBastionConnectTimeout = 10 * time.Second
+
+	// MinBastionRSAKeyBits is the minimum size of an RSA bastion key.
+	MinBastionRSAKeyBits = 2048
+)
+
+// Constants associated with the BastionKey.Type property.
+// The type of the private key.
+const (
+	BastionKey_Type_Dsa     = "dsa"
+	BastionKey_Type_Ecdsa   = "ecdsa"
+	BastionKey_Type_Ed25519 = "ed25519"
+	BastionKey_Type_Rsa     = "rsa"
+)
+
+// Constants associated with the BastionDiagnostic.Check property.
+// The check that produced the diagnostic.
+const (
+	BastionDiagnostic_Check_Authentication = "authentication"
+	BastionDiagnostic_Check_Connection     = "connection"
+	BastionDiagnostic_Check_Credential     = "credential"
+	BastionDiagnostic_Check_Handshake      = "handshake"
+	BastionDiagnostic_Check_Host           = "host"
+	BastionDiagnostic_Check_KeySize        = "key_size"
+	BastionDiagnostic_Check_KeyType        = "key_type"
+)
+
+// Constants associated with the BastionDiagnostic.Severity property.
+// The severity of the diagnostic.
+const (
+	BastionDiagnostic_Severity_Error   = "error"
+	BastionDiagnostic_Severity_Info    = "info"
+	BastionDiagnostic_Severity_Warning = "warning"
+)
+
+// BastionKey : A parsed bastion private key.
+type BastionKey struct {
+	// The type of the key.
+	Type string `json:"type"`
+
+	// The size of the key in bits.
+	Bits int `json:"bits"`
+
+	// The SHA256 fingerprint of the public key, as printed by ssh-keygen -l.
+	Fingerprint string `json:"fingerprint"`
+
+	signer ssh.Signer
+}
+
+// ParseBastionKey parses the PEM encoded private key of a bastion credential. Keys in PKCS#1, PKCS#8, SEC 1 and
+// OpenSSH format are accepted, also when they are base64 encoded as a whole. Keys protected by a passphrase are
+// rejected because a bastion credential has no passphrase.
+func ParseBastionKey(privateKey []byte) (*BastionKey, error) {
+	content := []byte(strings.TrimSpace(string(privateKey)))
+	if !strings.HasPrefix(string(content), "-----BEGIN") {
+		if decoded, err := base64.StdEncoding.DecodeString(string(content)); err == nil {
+			content = decoded
+		}
+	}
+
+	raw, err := ssh.ParseRawPrivateKey(content)
+	if err != nil {
+		if _, ok := err.(*ssh.PassphraseMissingError); ok {
+			return nil, fmt.Errorf("the private key is protected by a passphrase")
+		}
+		return nil, fmt.Errorf("the private key can not be parsed: %s", strings.TrimPrefix(err.Error(), "ssh: "))
+	}
+
+	key := &BastionKey{}
+	switch typed := raw.(type) {
+	case *rsa.PrivateKey:
+		key.Type, key.Bits = BastionKey_Type_Rsa, typed.N.BitLen()
+	case *ecdsa.PrivateKey:
+		key.Type, key.Bits = BastionKey_Type_Ecdsa, typed.Curve.Params().BitSize
+	case ed25519.PrivateKey:
+		key.Type, key.Bits = BastionKey_Type_Ed25519, 256
+	case *ed25519.PrivateKey:
+		key.Type, key.Bits = BastionKey_Type_Ed25519, 256
+		raw = *typed
+	case *dsa.PrivateKey:
+		key.Type, key.Bits = BastionKey_Type_Dsa, typed.P.BitLen()
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", raw)
+	}
+	key.signer, err = ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("the private key can not be used for SSH: %s", strings.TrimPrefix(err.Error(), "ssh: "))
+	}
+	key.Fingerprint = ssh.FingerprintSHA256(key.signer.PublicKey())
+	return key, nil
+}
+
+// BastionDiagnostic : The outcome of one bastion check.
+type BastionDiagnostic struct {
+	// The check that produced the diagnostic.
+	Check string `json:"check"`
+
+	// The severity of the diagnostic.
+	Severity string `json:"severity"`
+
+	// The description of the outcome.
+	Message string `json:"message"`
+}
+
+// BastionCheckReport : The result of CheckBastion.
+type BastionCheckReport struct {
+	// The bastion host.
+	Host string `json:"host"`
+
+	// The parsed bastion key, when the credential could be parsed.
+	Key *BastionKey `json:"key,omitempty"`
+
+	// The SHA256 fingerprint of the host key presented by the bastion, when a handshake was attempted.
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"`
+
+	// The diagnostics, in the order the checks ran.
+	Diagnostics []BastionDiagnostic `json:"diagnostics"`
+}
+
+// HasErrors reports whether any check failed.
+func (report *BastionCheckReport) HasErrors() bool {
+	for _, diagnostic := range report.Diagnostics {
+		if diagnostic.Severity == BastionDiagnostic_Severity_Error {
+			return true
+		}
+	}
+	return false
+}
+
+func (report *BastionCheckReport) add(check string, severity string, format string, args ...interface{}) {
+	report.Diagnostics = append(report.Diagnostics, BastionDiagnostic{Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// BastionCheckError is returned by CheckBastion and CheckActionBastion with the failed checks.
+type BastionCheckError struct {
+	// The diagnostics with the error severity.
+	Diagnostics []BastionDiagnostic
+}
+
+// Error implements the error interface.
+func (e *BastionCheckError) Error() string {
+	messages := make([]string, len(e.Diagnostics))
+	for i, diagnostic := range e.Diagnostics {
+		messages[i] = diagnostic.Check + ": " + diagnostic.Message
+	}
+	return "bastion check failed: " + strings.Join(messages, "; ")
+}
+
+// CheckBastionOptions : The CheckBastion options.
+type CheckBastionOptions struct {
+	// Describes a bastion resource.
+	Bastion *BastionResourceDefinition `json:"bastion" validate:"required"`
+
+	// User editable variable data & system generated reference to value.
+	BastionCredential *VariableData `json:"bastion_credential,omitempty"`
+
+	// Attempt an SSH handshake with the bastion host from this machine.
+	Connect *bool `json:"connect,omitempty"`
+
+	// The user to authenticate as. Defaults to DefaultBastionUser.
+	User *string `json:"user,omitempty"`
+
+	// The SSH port. Defaults to DefaultBastionPort.
+	Port *int64 `json:"port,omitempty"`
+
+	// The maximum time spent connecting. Defaults to DefaultBastionConnectTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Verifies the host key of the bastion. By default any host key is accepted and its fingerprint is reported.
+	HostKeyCallback ssh.HostKeyCallback `json:"-"`
+}
+
+// NewCheckBastionOptions : Instantiate CheckBastionOptions
+func (*SchematicsV1) NewCheckBastionOptions(bastion *BastionResourceDefinition, bastionCredential *VariableData) *CheckBastionOptions {
+	return &CheckBastionOptions{
+		Bastion:           bastion,
+		BastionCredential: bastionCredential,
+	}
+}
+
+// SetBastion : Allow user to set Bastion
+func (options *CheckBastionOptions) SetBastion(bastion *BastionResourceDefinition) *CheckBastionOptions {
+	options.Bastion = bastion
+	return options
+}
+
+// SetBastionCredential : Allow user to set BastionCredential
+func (options *CheckBastionOptions) SetBastionCredential(bastionCredential *VariableData) *CheckBastionOptions {
+	options.BastionCredential = bastionCredential
+	return options
+}
+
+// SetConnect : Allow user to set Connect
+func (options *CheckBastionOptions) SetConnect(connect bool) *CheckBastionOptions {
+	options.Connect = core.BoolPtr(connect)
+	return options
+}
+
+// SetUser : Allow user to set User
+func (options *CheckBastionOptions) SetUser(user string) *CheckBastionOptions {
+	options.User = core.StringPtr(user)
+	return options
+}
+
+// SetPort : Allow user to set Port
+func (options *CheckBastionOptions) SetPort(port int64) *CheckBastionOptions {
+	options.Port = core.Int64Ptr(port)
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *CheckBastionOptions) SetTimeout(timeout time.Duration) *CheckBastionOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHostKeyCallback : Allow user to set HostKeyCallback
+func (options *CheckBastionOptions) SetHostKeyCallback(hostKeyCallback ssh.HostKeyCallback) *CheckBastionOptions {
+	options.HostKeyCallback = hostKeyCallback
+	return options
+}
+
+// CheckBastion : Check a bastion host configuration before running jobs
+// Check that the bastion host is an IP address or host name and that the bastion credential holds a usable private
+// key: it parses, it is not protected by a passphrase, it is not a DSA key and RSA keys have at least
+// MinBastionRSAKeyBits bits. With Connect set, an SSH handshake is attempted from this machine. The report is always
+// returned; a *BastionCheckError is returned with it when a check failed.
+func (schematics *SchematicsV1) CheckBastion(checkBastionOptions *CheckBastionOptions) (result *BastionCheckReport, err error) {
+	err = core.ValidateNotNil(checkBastionOptions, "checkBastionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(checkBastionOptions, "checkBastionOptions")
+	if err != nil {
+		return
+	}
+
+	result = &BastionCheckReport{Host: stringValue(checkBastionOptions.Bastion.Host)}
+	if result.Host == "" {
+		result.add(BastionDiagnostic_Check_Host, BastionDiagnostic_Severity_Error, "the bastion host is not set")
+	} else if net.ParseIP(result.Host) == nil && !hostnameRegexp.MatchString(result.Host) {
+		result.add(BastionDiagnostic_Check_Host, BastionDiagnostic_Severity_Error, "%q is not an IP address or host name", result.Host)
+	}
+
+	credential := checkBastionOptions.BastionCredential
+	if credential == nil || stringValue(credential.Value) == "" {
+		result.add(BastionDiagnostic_Check_Credential, BastionDiagnostic_Severity_Error, "the bastion credential has no private key")
+	} else if key, keyErr := ParseBastionKey([]byte(*credential.Value)); keyErr != nil {
+		result.add(BastionDiagnostic_Check_Credential, BastionDiagnostic_Severity_Error, "%s", keyErr.Error())
+	} else {
+		result.Key = key
+		result.add(BastionDiagnostic_Check_Credential, BastionDiagnostic_Severity_Info, "%d bit %s key %s", key.Bits, key.Type, key.Fingerprint)
+		switch {
+		case key.Type == BastionKey_Type_Dsa:
+			result.add(BastionDiagnostic_Check_KeyType, BastionDiagnostic_Severity_Error, "DSA keys are not accepted by current SSH servers, use an RSA, ECDSA or Ed25519 key")
+		case key.Type == BastionKey_Type_Rsa && key.Bits < MinBastionRSAKeyBits:
+			result.add(BastionDiagnostic_Check_KeySize, BastionDiagnostic_Severity_Error, "the RSA key has %d bits, at least %d are required", key.Bits, MinBastionRSAKeyBits)
+		}
+	}
+
+	if checkBastionOptions.Connect != nil && *checkBastionOptions.Connect {
+		if result.HasErrors() {
+			result.add(BastionDiagnostic_Check_Connection, BastionDiagnostic_Severity_Warning, "the SSH handshake was skipped because the configuration is invalid")
+		} else {
+			checkBastionConnection(result, checkBastionOptions)
+		}
+	}
+
+	if result.HasErrors() {
+		failed := &BastionCheckError{}
+		for _, diagnostic := range result.Diagnostics {
+			if diagnostic.Severity == BastionDiagnostic_Severity_Error {
+				failed.Diagnostics = append(failed.Diagnostics, diagnostic)
+			}
+		}
+		err = failed
+	}
+	return
+}
+
+// checkBastionConnection attempts an SSH handshake with the bastion host and adds its outcome to the report.
+func checkBastionConnection(report *BastionCheckReport, checkBastionOptions *CheckBastionOptions) {
+	user := DefaultBastionUser
+	if checkBastionOptions.User != nil {
+		user = *checkBastionOptions.User
+	}
+	port := int64(DefaultBastionPort)
+	if checkBastionOptions.Port != nil {
+		port = *checkBastionOptions.Port
+	}
+	timeout := checkBastionOptions.Timeout
+	if timeout <= 0 {
+		timeout = DefaultBastionConnectTimeout
+	}
+	address := net.JoinHostPort(report.Host, strconv.FormatInt(port, 10))
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		report.add(BastionDiagnostic_Check_Connection, BastionDiagnostic_Severity_Error, "can not connect to %s: %s", address, err.Error())
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	config := &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(report.Key.signer)},
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			report.HostKeyFingerprint = ssh.FingerprintSHA256(key)
+			if checkBastionOptions.HostKeyCallback != nil {
+				return checkBastionOptions.HostKeyCallback(hostname, remote, key)
+			}
+			return nil
+		},
+		Timeout: timeout,
+	}
+	client, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		message := strings.TrimPrefix(err.Error(), "ssh: handshake failed: ")
+		if strings.Contains(message, "unable to authenticate") {
+			report.add(BastionDiagnostic_Check_Authentication, BastionDiagnostic_Severity_Error, "%s did not accept the key for user %s", address, user)
+			return
+		}
+		report.add(BastionDiagnostic_Check_Handshake, BastionDiagnostic_Severity_Error, "SSH handshake with %s failed: %s", address, message)
+		return
+	}
+	ssh.NewClient(client, channels, requests).Close()
+	report.add(BastionDiagnostic_Check_Authentication, BastionDiagnostic_Severity_Info, "authenticated as %s on %s, host key %s", user, address, report.HostKeyFingerprint)
+}
+
+// CheckActionBastionOptions : The CheckActionBastion options.
+type CheckActionBastionOptions struct {
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// The bastion credential to check instead of the one of the action, whose value the service may not return.
+	BastionCredential *VariableData `json:"bastion_credential,omitempty"`
+
+	// Attempt an SSH handshake with the bastion host from this machine.
+	Connect *bool `json:"connect,omitempty"`
+
+	// The user to authenticate as. Defaults to DefaultBastionUser.
+	User *string `json:"user,omitempty"`
+
+	// The SSH port. Defaults to DefaultBastionPort.
+	Port *int64 `json:"port,omitempty"`
+
+	// The maximum time spent connecting. Defaults to DefaultBastionConnectTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Verifies the host key of the bastion. By default any host key is accepted and its fingerprint is reported.
+	HostKeyCallback ssh.HostKeyCallback `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCheckActionBastionOptions : Instantiate CheckActionBastionOptions
+func (*SchematicsV1) NewCheckActionBastionOptions(actionID string) *CheckActionBastionOptions {
+	return &CheckActionBastionOptions{
+		ActionID: core.StringPtr(actionID),
+	}
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *CheckActionBastionOptions) SetActionID(actionID string) *CheckActionBastionOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetBastionCredential : Allow user to set BastionCredential
+func (options *CheckActionBastionOptions) SetBastionCredential(bastionCredential *VariableData) *CheckActionBastionOptions {
+	options.BastionCredential = bastionCredential
+	return options
+}
+
+// SetConnect : Allow user to set Connect
+func (options *CheckActionBastionOptions) SetConnect(connect bool) *CheckActionBastionOptions {
+	options.Connect = core.BoolPtr(connect)
+	return options
+}
+
+// SetUser : Allow user to set User
+func (options *CheckActionBastionOptions) SetUser(user string) *CheckActionBastionOptions {
+	options.User = core.StringPtr(user)
+	return options
+}
+
+// SetPort : Allow user to set Port
+func (options *CheckActionBastionOptions) SetPort(port int64) *CheckActionBastionOptions {
+	options.Port = core.Int64Ptr(port)
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *CheckActionBastionOptions) SetTimeout(timeout time.Duration) *CheckActionBastionOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHostKeyCallback : Allow user to set HostKeyCallback
+func (options *CheckActionBastionOptions) SetHostKeyCallback(hostKeyCallback ssh.HostKeyCallback) *CheckActionBastionOptions {
+	options.HostKeyCallback = hostKeyCallback
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CheckActionBastionOptions) SetHeaders(param map[string]string) *CheckActionBastionOptions {
+	options.Headers = param
+	return options
+}
+
+// CheckActionBastion : Check the bastion host configuration of an action
+// Get the action and check its bastion and bastion credential with CheckBastion. An action without a bastion has
+// nothing to check and returns an empty report.
+func (schematics *SchematicsV1) CheckActionBastion(checkActionBastionOptions *CheckActionBastionOptions) (result *BastionCheckReport, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(checkActionBastionOptions, "checkActionBastionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(checkActionBastionOptions, "checkActionBastionOptions")
+	if err != nil {
+		return
+	}
+
+	action, response, err := schematics.GetAction(&GetActionOptions{
+		ActionID: checkActionBastionOptions.ActionID,
+		Headers:  checkActionBastionOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if action.Bastion == nil {
+		result = &BastionCheckReport{}
+		return
+	}
+
+	credential := checkActionBastionOptions.BastionCredential
+	if credential == nil {
+		credential = action.BastionCredential
+	}
+	result, err = schematics.CheckBastion(&CheckBastionOptions{
+		Bastion:           action.Bastion,
+		BastionCredential: credential,
+		Connect:           checkActionBastionOptions.Connect,
+		User:              checkActionBastionOptions.User,
+		Port:              checkActionBastionOptions.Port,
+		Timeout:           checkActionBastionOptions.Timeout,
+		HostKeyCallback:   checkActionBastionOptions.HostKeyCallback,
+	})
+	return
+}
@@ -0,0 +1,272 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+)
+
+// startSSHServer starts a local SSH server that accepts the authorized key and returns its listener.
+func startSSHServer(authorized ssh.PublicKey) net.Listener {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).To(BeNil())
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	Expect(err).To(BeNil())
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "root" && bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for channel := range channels {
+					channel.Reject(ssh.Prohibited, "no channels")
+				}
+				serverConn.Close()
+			}()
+		}
+	}()
+	return listener
+}
+
+func listenerPort(listener net.Listener) int64 {
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	Expect(err).To(BeNil())
+	number, err := strconv.ParseInt(port, 10, 64)
+	Expect(err).To(BeNil())
+	return number
+}
+
+var _ = Describe(`Bastion check`, func() {
+	var ecdsaKey *ecdsa.PrivateKey
+	var ecdsaPEM string
+
+	BeforeEach(func() {
+		var err error
+		ecdsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		der, err := x509.MarshalECPrivateKey(ecdsaKey)
+		Expect(err).To(BeNil())
+		ecdsaPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	})
+
+	Describe(`ParseBastionKey(privateKey []byte)`, func() {
+		It(`Parse PEM and base64 encoded keys`, func() {
+			key, err := schematicsv1.ParseBastionKey([]byte(ecdsaPEM))
+			Expect(err).To(BeNil())
+			Expect(key.Type).To(Equal(schematicsv1.BastionKey_Type_Ecdsa))
+			Expect(key.Bits).To(Equal(256))
+			Expect(key.Fingerprint).To(HavePrefix("SHA256:"))
+
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).To(BeNil())
+			der, err := x509.MarshalPKCS8PrivateKey(edKey)
+			Expect(err).To(BeNil())
+			encoded := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			key, err = schematicsv1.ParseBastionKey([]byte(encoded))
+			Expect(err).To(BeNil())
+			Expect(key.Type).To(Equal(schematicsv1.BastionKey_Type_Ed25519))
+		})
+		It(`Reject invalid and encrypted keys`, func() {
+			_, err := schematicsv1.ParseBastionKey([]byte("ssh-rsa AAAA"))
+			Expect(err).To(MatchError("the private key can not be parsed: no key found"))
+
+			encrypted := pem.EncodeToMemory(&pem.Block{
+				Type:    "RSA PRIVATE KEY",
+				Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-128-CBC,00000000000000000000000000000000"},
+				Bytes:   []byte("secret"),
+			})
+			_, err = schematicsv1.ParseBastionKey(encrypted)
+			Expect(err).To(MatchError("the private key is protected by a passphrase"))
+		})
+	})
+
+	Describe(`CheckBastion(checkBastionOptions *CheckBastionOptions)`, func() {
+		var schematicsService *schematicsv1.SchematicsV1
+
+		BeforeEach(func() {
+			var serviceErr error
+			schematicsService, serviceErr = schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           "http://schematicsv1/api",
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+		})
+		It(`Report configuration problems`, func() {
+			// Invoke operation with nil options model (negative test)
+			result, err := schematicsService.CheckBastion(nil)
+			Expect(err).ToNot(BeNil())
+			Expect(result).To(BeNil())
+
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).To(BeNil())
+			rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+			options := schematicsService.NewCheckBastionOptions(
+				&schematicsv1.BastionResourceDefinition{Host: core.StringPtr("bad_host!")},
+				&schematicsv1.VariableData{Name: core.StringPtr("ssh_key"), Value: core.StringPtr(string(rsaPEM))},
+			).SetConnect(true)
+			result, err = schematicsService.CheckBastion(options)
+			Expect(err).To(MatchError(`bastion check failed: host: "bad_host!" is not an IP address or host name; key_size: the RSA key has 1024 bits, at least 2048 are required`))
+			Expect(result.Key.Type).To(Equal(schematicsv1.BastionKey_Type_Rsa))
+			Expect(result.Diagnostics[len(result.Diagnostics)-1].Check).To(Equal(schematicsv1.BastionDiagnostic_Check_Connection))
+			Expect(result.Diagnostics[len(result.Diagnostics)-1].Severity).To(Equal(schematicsv1.BastionDiagnostic_Severity_Warning))
+
+			options = schematicsService.NewCheckBastionOptions(&schematicsv1.BastionResourceDefinition{Host: core.StringPtr("10.0.0.1")}, nil)
+			_, err = schematicsService.CheckBastion(options)
+			Expect(err).To(MatchError("bastion check failed: credential: the bastion credential has no private key"))
+		})
+		It(`Authenticate against a local SSH server`, func() {
+			signer, err := ssh.NewSignerFromKey(ecdsaKey)
+			Expect(err).To(BeNil())
+			listener := startSSHServer(signer.PublicKey())
+			defer listener.Close()
+
+			options := schematicsService.NewCheckBastionOptions(
+				&schematicsv1.BastionResourceDefinition{Name: core.StringPtr("bastion"), Host: core.StringPtr("127.0.0.1")},
+				&schematicsv1.VariableData{Name: core.StringPtr("ssh_key"), Value: core.StringPtr(ecdsaPEM)},
+			).SetConnect(true).SetPort(listenerPort(listener))
+			result, err := schematicsService.CheckBastion(options)
+			Expect(err).To(BeNil())
+			Expect(result.HasErrors()).To(BeFalse())
+			Expect(result.HostKeyFingerprint).To(HavePrefix("SHA256:"))
+			last := result.Diagnostics[len(result.Diagnostics)-1]
+			Expect(last.Check).To(Equal(schematicsv1.BastionDiagnostic_Check_Authentication))
+			Expect(last.Message).To(HavePrefix("authenticated as root on 127.0.0.1:"))
+
+			_, err = schematicsService.CheckBastion(options.SetUser("admin"))
+			Expect(err).To(MatchError(fmt.Sprintf("bastion check failed: authentication: 127.0.0.1:%d did not accept the key for user admin", listenerPort(listener))))
+
+			options.SetUser("root").SetHostKeyCallback(func(string, net.Addr, ssh.PublicKey) error {
+				return errors.New("host key mismatch")
+			})
+			_, err = schematicsService.CheckBastion(options)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("handshake: SSH handshake with 127.0.0.1"))
+			Expect(err.Error()).To(ContainSubstring("host key mismatch"))
+		})
+		It(`Report unreachable hosts`, func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(BeNil())
+			port := listenerPort(listener)
+			listener.Close()
+
+			options := schematicsService.NewCheckBastionOptions(
+				&schematicsv1.BastionResourceDefinition{Host: core.StringPtr("127.0.0.1")},
+				&schematicsv1.VariableData{Value: core.StringPtr(ecdsaPEM)},
+			).SetConnect(true).SetPort(port)
+			result, err := schematicsService.CheckBastion(options)
+			Expect(err).ToNot(BeNil())
+			Expect(result.Diagnostics[len(result.Diagnostics)-1].Check).To(Equal(schematicsv1.BastionDiagnostic_Check_Connection))
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		var actionBody string
+
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				if req.Method == "GET" && req.URL.EscapedPath() == "/v2/actions/act-1" {
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", actionBody)
+					return
+				}
+				res.WriteHeader(404)
+			}))
+		})
+		It(`Invoke CheckActionBastion successfully`, func() {
+			actionBody = `{"id": "act-1", "bastion": {"name": "bastion", "host": "10.0.0.5"}, "bastion_credential": {"name": "ssh_key"}}`
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.CheckActionBastion(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			options := schematicsService.NewCheckActionBastionOptions("act-1")
+			result, response, operationErr = schematicsService.CheckActionBastion(options)
+			Expect(operationErr).To(MatchError("bastion check failed: credential: the bastion credential has no private key"))
+			Expect(response).ToNot(BeNil())
+			Expect(result.Host).To(Equal("10.0.0.5"))
+
+			options.SetBastionCredential(&schematicsv1.VariableData{Name: core.StringPtr("ssh_key"), Value: core.StringPtr(ecdsaPEM)})
+			result, _, operationErr = schematicsService.CheckActionBastion(options)
+			Expect(operationErr).To(BeNil())
+			Expect(result.Key.Type).To(Equal(schematicsv1.BastionKey_Type_Ecdsa))
+		})
+		It(`Invoke CheckActionBastion without bastion`, func() {
+			actionBody = `{"id": "act-1"}`
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			result, _, operationErr := schematicsService.CheckActionBastion(schematicsService.NewCheckActionBastionOptions("act-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(result.Diagnostics).To(BeEmpty())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,450 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDriftConcurrency is the number of workspaces scanned at the same time by DetectDriftBatch.
+const DefaultDriftConcurrency = 4
+
+// Constants associated with the ResourceDrift.PlannedAction property.
+// The action that the plan will take on the resource.
+const (
+	ResourceDrift_PlannedAction_Create  = "create"
+	ResourceDrift_PlannedAction_Delete  = "delete"
+	ResourceDrift_PlannedAction_Read    = "read"
+	ResourceDrift_PlannedAction_Replace = "replace"
+	ResourceDrift_PlannedAction_Update  = "update"
+)
+
+// Constants associated with the ResourceDrift.OutsideChange property.
+// The change that Terraform detected outside of Terraform.
+const (
+	ResourceDrift_OutsideChange_Changed = "changed"
+	ResourceDrift_OutsideChange_Deleted = "deleted"
+)
+
+// ResourceDrift : A resource with a planned change.
+type ResourceDrift struct {
+	// The ID of the template the resource belongs to.
+	TemplateID *string `json:"template_id,omitempty"`
+
+	// The Terraform resource address.
+	Address *string `json:"address,omitempty"`
+
+	// The action that the plan will take on the resource, if any.
+	PlannedAction *string `json:"planned_action,omitempty"`
+
+	// The change that Terraform reported as made outside of Terraform, if any.
+	OutsideChange *string `json:"outside_change,omitempty"`
+
+	// True if the resource drifted from the Terraform state, false if its planned change comes from the template.
+	Drifted *bool `json:"drifted,omitempty"`
+
+	// True if the plan does not report changes made outside of Terraform, so whether the planned change is drift or
+	// comes from the template can not be told from the plan. Drifted is then false and the judgement is left to the
+	// caller, see InferredResources.
+	Inferred *bool `json:"inferred,omitempty"`
+}
+
+// DriftReport : The drift of the resources of a workspace.
+type DriftReport struct {
+	// The workspace ID.
+	WID *string `json:"w_id,omitempty"`
+
+	// The ID of the refresh activity.
+	RefreshActivityID *string `json:"refresh_activity_id,omitempty"`
+
+	// The ID of the plan activity.
+	PlanActivityID *string `json:"plan_activity_id,omitempty"`
+
+	// The resources with a planned change or a change made outside of Terraform.
+	Resources []ResourceDrift `json:"resources,omitempty"`
+}
+
+// HasDrift returns true if any resource of the report drifted.
+func (report *DriftReport) HasDrift() bool {
+	return len(report.DriftedResources()) > 0
+}
+
+// InferredResources returns the resources with a planned change that may or may not be drift, because the plan does
+// not report changes made outside of Terraform.
+func (report *DriftReport) InferredResources() (inferred []ResourceDrift) {
+	for _, resource := range report.Resources {
+		if resource.Inferred != nil && *resource.Inferred {
+			inferred = append(inferred, resource)
+		}
+	}
+	return
+}
+
+// DriftedResources returns the resources that drifted.
+func (report *DriftReport) DriftedResources() (drifted []ResourceDrift) {
+	for _, resource := range report.Resources {
+		if resource.Drifted != nil && *resource.Drifted {
+			drifted = append(drifted, resource)
+		}
+	}
+	return
+}
+
+// DetectDriftOptions : The DetectDrift options.
+type DetectDriftOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting for each activity. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDetectDriftOptions : Instantiate DetectDriftOptions
+func (*SchematicsV1) NewDetectDriftOptions(wID string, refreshToken string) *DetectDriftOptions {
+	return &DetectDriftOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *DetectDriftOptions) SetWID(wID string) *DetectDriftOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *DetectDriftOptions) SetRefreshToken(refreshToken string) *DetectDriftOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *DetectDriftOptions) SetPollInterval(pollInterval time.Duration) *DetectDriftOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *DetectDriftOptions) SetTimeout(timeout time.Duration) *DetectDriftOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DetectDriftOptions) SetHeaders(param map[string]string) *DetectDriftOptions {
+	options.Headers = param
+	return options
+}
+
+// DetectDrift : Detect drift between the Terraform state and the real infrastructure
+// Run a refresh and then a plan on the workspace, wait for both to finish and parse the plan logs of every template.
+// Resources that Terraform reports as changed outside of Terraform are marked as drifted.
+func (schematics *SchematicsV1) DetectDrift(detectDriftOptions *DetectDriftOptions) (result *DriftReport, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(detectDriftOptions, "detectDriftOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(detectDriftOptions, "detectDriftOptions")
+	if err != nil {
+		return
+	}
+
+	refresh, response, err := schematics.RefreshWorkspaceCommand(&RefreshWorkspaceCommandOptions{
+		WID:          detectDriftOptions.WID,
+		RefreshToken: detectDriftOptions.RefreshToken,
+		Headers:      detectDriftOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if refresh.Activityid == nil {
+		err = fmt.Errorf("refresh of workspace %s did not return an activity ID", *detectDriftOptions.WID)
+		return
+	}
+	_, response, err = schematics.WaitForWorkspaceActivity(&WaitForWorkspaceActivityOptions{
+		WID:          detectDriftOptions.WID,
+		ActivityID:   refresh.Activityid,
+		PollInterval: detectDriftOptions.PollInterval,
+		Timeout:      detectDriftOptions.Timeout,
+		Headers:      detectDriftOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	plan, response, err := schematics.PlanWorkspaceCommand(&PlanWorkspaceCommandOptions{
+		WID:          detectDriftOptions.WID,
+		RefreshToken: detectDriftOptions.RefreshToken,
+		Headers:      detectDriftOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if plan.Activityid == nil {
+		err = fmt.Errorf("plan of workspace %s did not return an activity ID", *detectDriftOptions.WID)
+		return
+	}
+	planActivity, response, err := schematics.WaitForWorkspaceActivity(&WaitForWorkspaceActivityOptions{
+		WID:          detectDriftOptions.WID,
+		ActivityID:   plan.Activityid,
+		PollInterval: detectDriftOptions.PollInterval,
+		Timeout:      detectDriftOptions.Timeout,
+		Headers:      detectDriftOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	report := &DriftReport{
+		WID:               detectDriftOptions.WID,
+		RefreshActivityID: refresh.Activityid,
+		PlanActivityID:    plan.Activityid,
+	}
+	for _, template := range planActivity.Templates {
+		if template.TemplateID == nil {
+			continue
+		}
+		var log *string
+		log, response, err = schematics.GetTemplateActivityLog(&GetTemplateActivityLogOptions{
+			WID:        detectDriftOptions.WID,
+			TID:        template.TemplateID,
+			ActivityID: plan.Activityid,
+			Headers:    detectDriftOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		if log == nil {
+			continue
+		}
+		for _, resource := range ParsePlanDrift(*log) {
+			resource.TemplateID = template.TemplateID
+			report.Resources = append(report.Resources, resource)
+		}
+	}
+
+	result = report
+	return
+}
+
+var (
+	planResourceRegexp = regexp.MustCompile(`#\s+(\S+)\s+(will be created|will be destroyed|will be updated in-place|will be read during apply|must be replaced|is tainted, so must be replaced|will be replaced, as requested|has been changed|has changed|has been deleted)`)
+	planOutsideMarkers = []string{"changed outside of Terraform", "Objects have changed outside of Terraform"}
+)
+
+// ParsePlanDrift parses the output of a Terraform plan and returns the resources with a planned change or a change
+// made outside of Terraform, sorted by address. Only the resources that the output reports as changed outside of
+// Terraform are marked as drifted. When the output reports no such change, the planned changes other than reads are
+// marked as inferred, since they may as well come from a template change that was not applied.
+func ParsePlanDrift(planOutput string) []ResourceDrift {
+	planned := map[string]string{}
+	outside := map[string]string{}
+	for _, match := range planResourceRegexp.FindAllStringSubmatch(planOutput, -1) {
+		address := match[1]
+		switch match[2] {
+		case "will be created":
+			planned[address] = ResourceDrift_PlannedAction_Create
+		case "will be destroyed":
+			planned[address] = ResourceDrift_PlannedAction_Delete
+		case "will be updated in-place":
+			planned[address] = ResourceDrift_PlannedAction_Update
+		case "will be read during apply":
+			planned[address] = ResourceDrift_PlannedAction_Read
+		case "must be replaced", "is tainted, so must be replaced", "will be replaced, as requested":
+			planned[address] = ResourceDrift_PlannedAction_Replace
+		case "has been changed", "has changed":
+			outside[address] = ResourceDrift_OutsideChange_Changed
+		case "has been deleted":
+			outside[address] = ResourceDrift_OutsideChange_Deleted
+		}
+	}
+
+	reportsOutside := len(outside) > 0
+	for _, marker := range planOutsideMarkers {
+		if strings.Contains(planOutput, marker) {
+			reportsOutside = true
+		}
+	}
+
+	addresses := map[string]bool{}
+	for address := range planned {
+		addresses[address] = true
+	}
+	for address := range outside {
+		addresses[address] = true
+	}
+
+	resources := []ResourceDrift{}
+	for address := range addresses {
+		resource := ResourceDrift{
+			Address: core.StringPtr(address),
+		}
+		if action, ok := planned[address]; ok {
+			resource.PlannedAction = core.StringPtr(action)
+		}
+		if change, ok := outside[address]; ok {
+			resource.OutsideChange = core.StringPtr(change)
+		}
+		// Data sources are read on every plan, a read is never drift on its own.
+		isRead := resource.PlannedAction != nil && *resource.PlannedAction == ResourceDrift_PlannedAction_Read
+		resource.Drifted = core.BoolPtr(resource.OutsideChange != nil)
+		resource.Inferred = core.BoolPtr(!reportsOutside && !isRead)
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return *resources[i].Address < *resources[j].Address
+	})
+	return resources
+}
+
+// DetectDriftBatchOptions : The DetectDriftBatch options.
+type DetectDriftBatchOptions struct {
+	// The IDs of the workspaces to scan.
+	WIDs []string `json:"w_ids" validate:"required,min=1"`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The number of workspaces scanned at the same time. Defaults to DefaultDriftConcurrency.
+	Concurrency *int64 `json:"concurrency,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting for each activity. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDetectDriftBatchOptions : Instantiate DetectDriftBatchOptions
+func (*SchematicsV1) NewDetectDriftBatchOptions(wIDs []string, refreshToken string) *DetectDriftBatchOptions {
+	return &DetectDriftBatchOptions{
+		WIDs:         wIDs,
+		RefreshToken: core.StringPtr(refreshToken),
+	}
+}
+
+// SetWIDs : Allow user to set WIDs
+func (options *DetectDriftBatchOptions) SetWIDs(wIDs []string) *DetectDriftBatchOptions {
+	options.WIDs = wIDs
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *DetectDriftBatchOptions) SetRefreshToken(refreshToken string) *DetectDriftBatchOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetConcurrency : Allow user to set Concurrency
+func (options *DetectDriftBatchOptions) SetConcurrency(concurrency int64) *DetectDriftBatchOptions {
+	options.Concurrency = core.Int64Ptr(concurrency)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *DetectDriftBatchOptions) SetPollInterval(pollInterval time.Duration) *DetectDriftBatchOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *DetectDriftBatchOptions) SetTimeout(timeout time.Duration) *DetectDriftBatchOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DetectDriftBatchOptions) SetHeaders(param map[string]string) *DetectDriftBatchOptions {
+	options.Headers = param
+	return options
+}
+
+// DriftResult : The result of the drift detection of one workspace in a batch.
+type DriftResult struct {
+	// The workspace ID.
+	WID string `json:"w_id"`
+
+	// The drift report, if the detection succeeded.
+	Report *DriftReport `json:"report,omitempty"`
+
+	// The error that stopped the detection, if any.
+	Error error `json:"-"`
+}
+
+// DetectDriftBatch : Detect drift on many workspaces
+// Run DetectDrift on every workspace, scanning at most Concurrency workspaces at the same time. The results are
+// returned in the order of the workspace IDs; a failure on one workspace does not stop the others.
+func (schematics *SchematicsV1) DetectDriftBatch(detectDriftBatchOptions *DetectDriftBatchOptions) (result []DriftResult, err error) {
+	err = core.ValidateNotNil(detectDriftBatchOptions, "detectDriftBatchOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(detectDriftBatchOptions, "detectDriftBatchOptions")
+	if err != nil {
+		return
+	}
+	concurrency := int64(DefaultDriftConcurrency)
+	if detectDriftBatchOptions.Concurrency != nil {
+		concurrency = *detectDriftBatchOptions.Concurrency
+		if concurrency <= 0 {
+			err = fmt.Errorf("Concurrency must be greater than zero")
+			return
+		}
+	}
+
+	result = make([]DriftResult, len(detectDriftBatchOptions.WIDs))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, wID := range detectDriftBatchOptions.WIDs {
+		wg.Add(1)
+		go func(i int, wID string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			report, _, detectErr := schematics.DetectDrift(&DetectDriftOptions{
+				WID:          core.StringPtr(wID),
+				RefreshToken: detectDriftBatchOptions.RefreshToken,
+				PollInterval: detectDriftBatchOptions.PollInterval,
+				Timeout:      detectDriftBatchOptions.Timeout,
+				Headers:      detectDriftBatchOptions.Headers,
+			})
+			result[i] = DriftResult{WID: wID, Report: report, Error: detectErr}
+		}(i, wID)
+	}
+	wg.Wait()
+	return
+}
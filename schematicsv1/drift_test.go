@@ -0,0 +1,207 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ = Describe(`Drift detection`, func() {
+	var testServer *httptest.Server
+	planWithOutsideChanges := `
+2021/03/01 10:00:00 Terraform plan | Note: Objects have changed outside of Terraform
+2021/03/01 10:00:00 Terraform plan |   # ibm_is_vpc.vpc has been changed
+2021/03/01 10:00:00 Terraform plan |   # module.network.ibm_is_subnet.subnet[0] has been deleted
+2021/03/01 10:00:00 Terraform plan |   # ibm_is_vpc.vpc will be updated in-place
+2021/03/01 10:00:00 Terraform plan |   # module.network.ibm_is_subnet.subnet[0] will be created
+2021/03/01 10:00:00 Terraform plan |   # ibm_is_instance.vsi must be replaced
+`
+	planWithoutOutsideChanges := `
+2021/03/01 10:00:00 Terraform plan |   # ibm_is_vpc.vpc will be updated in-place
+2021/03/01 10:00:00 Terraform plan |   # data.ibm_is_image.image will be read during apply
+`
+
+	Describe(`ParsePlanDrift(planOutput string)`, func() {
+		It(`Parse a plan that reports changes made outside of Terraform`, func() {
+			resources := schematicsv1.ParsePlanDrift(planWithOutsideChanges)
+			Expect(resources).To(HaveLen(3))
+
+			Expect(*resources[0].Address).To(Equal("ibm_is_instance.vsi"))
+			Expect(*resources[0].PlannedAction).To(Equal(schematicsv1.ResourceDrift_PlannedAction_Replace))
+			Expect(*resources[0].Drifted).To(BeFalse())
+
+			Expect(*resources[1].Address).To(Equal("ibm_is_vpc.vpc"))
+			Expect(*resources[1].OutsideChange).To(Equal(schematicsv1.ResourceDrift_OutsideChange_Changed))
+			Expect(*resources[1].Drifted).To(BeTrue())
+			Expect(*resources[1].Inferred).To(BeFalse())
+
+			Expect(*resources[2].Address).To(Equal("module.network.ibm_is_subnet.subnet[0]"))
+			Expect(*resources[2].PlannedAction).To(Equal(schematicsv1.ResourceDrift_PlannedAction_Create))
+			Expect(*resources[2].OutsideChange).To(Equal(schematicsv1.ResourceDrift_OutsideChange_Deleted))
+		})
+		It(`Parse a plan that does not report changes made outside of Terraform`, func() {
+			resources := schematicsv1.ParsePlanDrift(planWithoutOutsideChanges)
+			Expect(resources).To(HaveLen(2))
+			Expect(*resources[0].Address).To(Equal("data.ibm_is_image.image"))
+			Expect(*resources[0].Drifted).To(BeFalse())
+			Expect(*resources[0].Inferred).To(BeFalse())
+			Expect(*resources[1].Drifted).To(BeFalse())
+			Expect(*resources[1].Inferred).To(BeTrue())
+
+			report := &schematicsv1.DriftReport{Resources: resources}
+			Expect(report.HasDrift()).To(BeFalse())
+			Expect(report.InferredResources()).To(HaveLen(1))
+			Expect(*report.InferredResources()[0].Address).To(Equal("ibm_is_vpc.vpc"))
+		})
+		It(`Parse a plan without changes`, func() {
+			Expect(schematicsv1.ParsePlanDrift("No changes. Infrastructure is up-to-date.")).To(BeEmpty())
+		})
+	})
+	Describe(`DetectDrift(detectDriftOptions *DetectDriftOptions)`, func() {
+		var mutex sync.Mutex
+		var calls []string
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				calls = nil
+				polls := map[string]int{}
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					mutex.Lock()
+					defer mutex.Unlock()
+					calls = append(calls, req.Method+" "+req.URL.EscapedPath())
+					res.Header().Set("Content-type", "application/json")
+					parts := strings.Split(req.URL.EscapedPath(), "/")
+					if len(parts) < 4 || parts[3] == "missing" {
+						res.WriteHeader(404)
+						return
+					}
+					wID := parts[3]
+					switch {
+					case req.Method == "PUT" && strings.HasSuffix(req.URL.EscapedPath(), "/refresh"):
+						Expect(req.Header.Get("refresh_token")).To(Equal("testString"))
+						res.WriteHeader(202)
+						fmt.Fprintf(res, "%s", `{"activityid": "refresh-1"}`)
+					case req.Method == "POST" && strings.HasSuffix(req.URL.EscapedPath(), "/plan"):
+						res.WriteHeader(202)
+						fmt.Fprintf(res, "%s", `{"activityid": "plan-1"}`)
+					case req.Method == "GET" && strings.HasSuffix(req.URL.EscapedPath(), "/runtime_data/t-1/log_store/actions/plan-1"):
+						log, _ := json.Marshal(planWithOutsideChanges)
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", log)
+					case req.Method == "GET" && strings.Contains(req.URL.EscapedPath(), "/actions/"):
+						activityID := parts[len(parts)-1]
+						polls[wID+activityID]++
+						status := "INPROGRESS"
+						if polls[wID+activityID] > 1 {
+							status = "COMPLETED"
+							if wID == "failing" {
+								status = "FAILED"
+							}
+						}
+						res.WriteHeader(200)
+						fmt.Fprintf(res, `{"action_id": "%s", "status": "%s", "message": ["done"], "templates": [{"template_id": "t-1"}]}`, activityID, status)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke DetectDrift successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.DetectDrift(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				detectDriftOptionsModel := schematicsService.NewDetectDriftOptions("ws-1", "testString").SetPollInterval(time.Millisecond)
+				result, response, operationErr = schematicsService.DetectDrift(detectDriftOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(*result.RefreshActivityID).To(Equal("refresh-1"))
+				Expect(*result.PlanActivityID).To(Equal("plan-1"))
+				Expect(result.HasDrift()).To(BeTrue())
+				Expect(result.DriftedResources()).To(HaveLen(2))
+				Expect(*result.Resources[0].TemplateID).To(Equal("t-1"))
+
+				// The plan must only be submitted once the refresh completed.
+				Expect(calls[:4]).To(Equal([]string{
+					"PUT /v1/workspaces/ws-1/refresh",
+					"GET /v1/workspaces/ws-1/actions/refresh-1",
+					"GET /v1/workspaces/ws-1/actions/refresh-1",
+					"POST /v1/workspaces/ws-1/plan",
+				}))
+			})
+			It(`Invoke DetectDrift with error: Activity failed`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				result, _, operationErr := schematicsService.DetectDrift(schematicsService.NewDetectDriftOptions("failing", "testString").SetPollInterval(time.Millisecond))
+				Expect(result).To(BeNil())
+				activityErr, ok := operationErr.(*schematicsv1.WorkspaceActivityFailedError)
+				Expect(ok).To(BeTrue())
+				Expect(activityErr.ActivityID).To(Equal("refresh-1"))
+				Expect(activityErr.Status).To(Equal("FAILED"))
+				Expect(activityErr.Error()).To(ContainSubstring("done"))
+			})
+			It(`Invoke DetectDriftBatch successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				_, operationErr := schematicsService.DetectDriftBatch(nil)
+				Expect(operationErr).ToNot(BeNil())
+
+				detectDriftBatchOptionsModel := schematicsService.NewDetectDriftBatchOptions([]string{"ws-1", "missing", "ws-2", "failing"}, "testString").
+					SetConcurrency(2).
+					SetPollInterval(time.Millisecond)
+				results, operationErr := schematicsService.DetectDriftBatch(detectDriftBatchOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(results).To(HaveLen(4))
+				Expect(results[0].WID).To(Equal("ws-1"))
+				Expect(results[0].Error).To(BeNil())
+				Expect(results[0].Report.HasDrift()).To(BeTrue())
+				Expect(results[1].Error).ToNot(BeNil())
+				Expect(results[2].Report).ToNot(BeNil())
+				Expect(results[3].Error).ToNot(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
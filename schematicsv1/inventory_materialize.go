@@ -0,0 +1,190 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var ansibleGroupInvalidCharRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// MaterializeInventoryOptions : The MaterializeInventory options.
+type MaterializeInventoryOptions struct {
+	// Resource Inventory Id.  Use GET /inventories API to look up the Resource Inventory definition Ids  in your IBM Cloud
+	// account.
+	InventoryID *string `json:"inventory_id" validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewMaterializeInventoryOptions : Instantiate MaterializeInventoryOptions
+func (*SchematicsV1) NewMaterializeInventoryOptions(inventoryID string) *MaterializeInventoryOptions {
+	return &MaterializeInventoryOptions{
+		InventoryID: core.StringPtr(inventoryID),
+	}
+}
+
+// SetInventoryID : Allow user to set InventoryID
+func (options *MaterializeInventoryOptions) SetInventoryID(inventoryID string) *MaterializeInventoryOptions {
+	options.InventoryID = core.StringPtr(inventoryID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *MaterializeInventoryOptions) SetHeaders(param map[string]string) *MaterializeInventoryOptions {
+	options.Headers = param
+	return options
+}
+
+// MaterializeInventory : Build the inventory that an action job would get
+// Get the inventory definition and, for every referenced resource query, run ExecuteResourceQuery and add the hosts of
+// the query output to a group named after the query. Hosts listed in the INI inventory of the definition are kept. Use
+// INI() on the result to review the inventory before running playbooks.
+func (schematics *SchematicsV1) MaterializeInventory(materializeInventoryOptions *MaterializeInventoryOptions) (result *AnsibleInventory, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(materializeInventoryOptions, "materializeInventoryOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(materializeInventoryOptions, "materializeInventoryOptions")
+	if err != nil {
+		return
+	}
+
+	record, response, err := schematics.GetInventory(&GetInventoryOptions{
+		InventoryID: materializeInventoryOptions.InventoryID,
+		Headers:     materializeInventoryOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	inventory, err := record.AnsibleInventory()
+	if err != nil {
+		return
+	}
+
+	for _, queryID := range record.ResourceQueries {
+		var query *ResourceQueryRecord
+		query, response, err = schematics.GetResourcesQuery(&GetResourcesQueryOptions{
+			QueryID: core.StringPtr(queryID),
+			Headers: materializeInventoryOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		var output *ResourceQueryResponseRecord
+		output, response, err = schematics.ExecuteResourceQuery(&ExecuteResourceQueryOptions{
+			QueryID: core.StringPtr(queryID),
+			Headers: materializeInventoryOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+
+		group := queryID
+		if query.Name != nil && *query.Name != "" {
+			group = *query.Name
+		}
+		for _, item := range output.Response {
+			err = inventory.AddResourceQueryOutput(ResourceQueryInventoryGroup(group), item.QueryOutput)
+			if err != nil {
+				err = fmt.Errorf("resource query %s: %s", queryID, err.Error())
+				return
+			}
+		}
+	}
+	result = inventory
+	return
+}
+
+// ResourceQueryInventoryGroup returns the inventory group name for a resource query name: characters that are not
+// valid in a group name are replaced by '_'.
+func ResourceQueryInventoryGroup(name string) string {
+	group := ansibleGroupInvalidCharRegexp.ReplaceAllString(name, "_")
+	if group == "" || (group[0] >= '0' && group[0] <= '9') {
+		group = "_" + group
+	}
+	return group
+}
+
+// AddResourceQueryOutput adds the hosts of a resource query output to a group. The output lists the selected fields
+// of every matching resource in turn; a new resource starts when a field repeats. The host of a resource is its first
+// field whose name contains 'ip' and whose value is an IP address, or else its 'name' field. The other fields become
+// host variables. An empty group adds the hosts without group.
+func (inventory *AnsibleInventory) AddResourceQueryOutput(group string, output []ResourceQueryResponseRecordResponseItemQueryOutputItem) error {
+	var resources [][]ResourceQueryResponseRecordResponseItemQueryOutputItem
+	var current []ResourceQueryResponseRecordResponseItemQueryOutputItem
+	seen := map[string]bool{}
+	for _, item := range output {
+		if item.Name == nil || item.Value == nil {
+			continue
+		}
+		if seen[*item.Name] {
+			resources = append(resources, current)
+			current, seen = nil, map[string]bool{}
+		}
+		seen[*item.Name] = true
+		current = append(current, item)
+	}
+	if len(current) > 0 {
+		resources = append(resources, current)
+	}
+
+	for _, fields := range resources {
+		host := resourceQueryHost(fields)
+		if host < 0 {
+			return fmt.Errorf("resource without IP address or name: %s", formatQueryOutput(fields))
+		}
+		vars := map[string]interface{}{}
+		for i, field := range fields {
+			if i != host {
+				vars[*field.Name] = *field.Value
+			}
+		}
+		if err := inventory.AddHost(group, *fields[host].Value, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceQueryHost returns the index of the field that identifies the host of a resource, or -1.
+func resourceQueryHost(fields []ResourceQueryResponseRecordResponseItemQueryOutputItem) int {
+	for i, field := range fields {
+		if strings.Contains(strings.ToLower(*field.Name), "ip") && net.ParseIP(*field.Value) != nil {
+			return i
+		}
+	}
+	for i, field := range fields {
+		if *field.Name == ResourceQuery_QuerySelect_Name && *field.Value != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func formatQueryOutput(fields []ResourceQueryResponseRecordResponseItemQueryOutputItem) string {
+	var pairs []string
+	for _, field := range fields {
+		pairs = append(pairs, *field.Name+"="+*field.Value)
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,116 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Inventory materialization`, func() {
+	var testServer *httptest.Server
+
+	Context(`Using mock server endpoint`, func() {
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "GET" && path == "/v2/inventories/inv-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "inv-1", "inventories_ini": "[bastion]\n10.0.0.1\n", "resource_queries": ["q-1", "q-2"]}`)
+				case req.Method == "GET" && path == "/v2/resources_query/q-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "q-1", "name": "prod-web"}`)
+				case req.Method == "GET" && path == "/v2/resources_query/q-2":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "q-2"}`)
+				case req.Method == "POST" && path == "/v2/resources_query/q-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"response": [{"query_type": "workspaces", "query_output": [
+						{"name": "name", "value": "web-0"}, {"name": "ipv4_address", "value": "10.10.10.4"},
+						{"name": "name", "value": "web-1"}, {"name": "ipv4_address", "value": "10.10.10.5"}
+					]}]}`)
+				case req.Method == "POST" && path == "/v2/resources_query/q-2":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"response": [{"query_type": "workspaces", "query_output": [{"name": "name", "value": "db-0"}]}]}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke MaterializeInventory successfully`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.MaterializeInventory(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			result, response, operationErr = schematicsService.MaterializeInventory(schematicsService.NewMaterializeInventoryOptions("inv-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(result.Groups["bastion"].Hosts).To(Equal([]string{"10.0.0.1"}))
+			Expect(result.Groups["prod_web"].Hosts).To(Equal([]string{"10.10.10.4", "10.10.10.5"}))
+			Expect(result.Groups["q_2"].Hosts).To(Equal([]string{"db-0"}))
+			Expect(result.Hosts["10.10.10.5"]).To(Equal(map[string]interface{}{"name": "web-1"}))
+			Expect(result.INI()).To(ContainSubstring("[prod_web]\n10.10.10.4 name=web-0\n10.10.10.5 name=web-1\n"))
+		})
+		It(`Invoke MaterializeInventory with an unknown inventory`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			result, response, operationErr := schematicsService.MaterializeInventory(schematicsService.NewMaterializeInventoryOptions("inv-2"))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(response.StatusCode).To(Equal(404))
+			Expect(result).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+
+	Describe(`AddResourceQueryOutput(group string, output []ResourceQueryResponseRecordResponseItemQueryOutputItem)`, func() {
+		It(`Reject resources without IP address or name`, func() {
+			inventory := schematicsv1.NewAnsibleInventory()
+			err := inventory.AddResourceQueryOutput("web", []schematicsv1.ResourceQueryResponseRecordResponseItemQueryOutputItem{
+				{Name: core.StringPtr("id"), Value: core.StringPtr("vsi-1")},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("resource without IP address or name: id=vsi-1"))
+		})
+		It(`Name groups after resource queries`, func() {
+			Expect(schematicsv1.ResourceQueryInventoryGroup("prod-web")).To(Equal("prod_web"))
+			Expect(schematicsv1.ResourceQueryInventoryGroup("1st")).To(Equal("_1st"))
+		})
+	})
+})
@@ -0,0 +1,653 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Constants associated with the AnsibleHostResult.Status property.
+// The outcome of a task on a host.
+const (
+	AnsibleHostResult_Status_Changed     = "changed"
+	AnsibleHostResult_Status_Failed      = "failed"
+	AnsibleHostResult_Status_Ok          = "ok"
+	AnsibleHostResult_Status_Skipped     = "skipped"
+	AnsibleHostResult_Status_Unreachable = "unreachable"
+)
+
+var (
+	ansibleBannerRegexp = regexp.MustCompile(`^(PLAY|TASK|RUNNING HANDLER) \[(.*)\] \**$`)
+	ansibleRecapRegexp  = regexp.MustCompile(`^PLAY RECAP \**$`)
+	ansibleResultRegexp = regexp.MustCompile(`^(ok|changed|skipping|failed|fatal): \[([^\]]+)\]( \(item=.*\))?(: (FAILED|UNREACHABLE)!)?( => (.*))?$`)
+	ansibleStatsRegexp  = regexp.MustCompile(`^(\S+)\s*:\s*((?:[a-z]+=\d+\s*)+)$`)
+
+	htmlDropRegexp  = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|pre)>`)
+	htmlTagRegexp   = regexp.MustCompile(`<[^>]*>`)
+
+	markdownHeadingRegexp  = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownEmphasisRegexp = regexp.MustCompile(`\*\*|__|` + "`")
+)
+
+// AnsibleLog : The plays, tasks and host results of an Ansible playbook log.
+type AnsibleLog struct {
+	// The plays in the order they ran.
+	Plays []AnsiblePlay `json:"plays"`
+
+	// The play recap by host.
+	Stats map[string]AnsibleHostStats `json:"stats"`
+}
+
+// AnsiblePlay : A play of an Ansible playbook log.
+type AnsiblePlay struct {
+	// The play name.
+	Name string `json:"name"`
+
+	// The tasks in the order they ran.
+	Tasks []AnsibleTask `json:"tasks"`
+}
+
+// AnsibleTask : A task of an Ansible play.
+type AnsibleTask struct {
+	// The task name.
+	Name string `json:"name"`
+
+	// The results of the task on each host.
+	Results []AnsibleHostResult `json:"results"`
+}
+
+// AnsibleHostResult : The result of an Ansible task on a host.
+type AnsibleHostResult struct {
+	// The host.
+	Host string `json:"host"`
+
+	// The outcome of the task.
+	Status string `json:"status"`
+
+	// The message of a failed or unreachable result.
+	Message string `json:"message,omitempty"`
+}
+
+// AnsibleHostStats : The play recap of a host.
+type AnsibleHostStats struct {
+	Ok          int64 `json:"ok"`
+	Changed     int64 `json:"changed"`
+	Unreachable int64 `json:"unreachable"`
+	Failed      int64 `json:"failures"`
+	Skipped     int64 `json:"skipped"`
+	Rescued     int64 `json:"rescued"`
+	Ignored     int64 `json:"ignored"`
+}
+
+// Changed reports whether the task changed the host.
+func (result *AnsibleHostResult) Changed() bool {
+	return result.Status == AnsibleHostResult_Status_Changed
+}
+
+// Failed reports whether the task failed or the host was unreachable.
+func (result *AnsibleHostResult) Failed() bool {
+	return result.Status == AnsibleHostResult_Status_Failed || result.Status == AnsibleHostResult_Status_Unreachable
+}
+
+// Changed reports whether the task changed any host.
+func (task *AnsibleTask) Changed() bool {
+	for i := range task.Results {
+		if task.Results[i].Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed reports whether the task failed on any host.
+func (task *AnsibleTask) Failed() bool {
+	for i := range task.Results {
+		if task.Results[i].Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// FailedTasks returns the tasks that failed on at least one host.
+func (log *AnsibleLog) FailedTasks() (tasks []AnsibleTask) {
+	for _, play := range log.Plays {
+		for _, task := range play.Tasks {
+			if task.Failed() {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return
+}
+
+// DecodeAnsibleLog decodes the details of a job log in the given format. JSON details use the output of the Ansible
+// json callback; the other formats are converted to plain text with JobLogPlainText and parsed as the output of the
+// default callback.
+func DecodeAnsibleLog(format string, details []byte) (*AnsibleLog, error) {
+	if format == JobLog_Format_JSON {
+		return decodeAnsibleJSONLog(details)
+	}
+	text, err := JobLogPlainText(format, details)
+	if err != nil {
+		return nil, err
+	}
+	return parseAnsibleTextLog(text), nil
+}
+
+// ansibleJSONLog is the output of the Ansible json callback.
+type ansibleJSONLog struct {
+	Plays []struct {
+		Play struct {
+			Name string `json:"name"`
+		} `json:"play"`
+		Tasks []struct {
+			Task struct {
+				Name string `json:"name"`
+			} `json:"task"`
+			Hosts map[string]ansibleJSONHostResult `json:"hosts"`
+		} `json:"tasks"`
+	} `json:"plays"`
+	Stats map[string]AnsibleHostStats `json:"stats"`
+}
+
+type ansibleJSONHostResult struct {
+	Changed     bool   `json:"changed,omitempty"`
+	Failed      bool   `json:"failed,omitempty"`
+	Skipped     bool   `json:"skipped,omitempty"`
+	Unreachable bool   `json:"unreachable,omitempty"`
+	Msg         string `json:"msg,omitempty"`
+}
+
+func decodeAnsibleJSONLog(details []byte) (*AnsibleLog, error) {
+	var raw ansibleJSONLog
+	if err := json.Unmarshal(details, &raw); err != nil {
+		return nil, fmt.Errorf("decoding ansible json log: %s", err.Error())
+	}
+	log := &AnsibleLog{Stats: raw.Stats}
+	if log.Stats == nil {
+		log.Stats = map[string]AnsibleHostStats{}
+	}
+	for _, rawPlay := range raw.Plays {
+		play := AnsiblePlay{Name: rawPlay.Play.Name}
+		for _, rawTask := range rawPlay.Tasks {
+			task := AnsibleTask{Name: rawTask.Task.Name}
+			for _, host := range sortedKeys(rawTask.Hosts) {
+				hostResult := rawTask.Hosts[host]
+				result := AnsibleHostResult{Host: host, Status: AnsibleHostResult_Status_Ok}
+				switch {
+				case hostResult.Unreachable:
+					result.Status = AnsibleHostResult_Status_Unreachable
+				case hostResult.Failed:
+					result.Status = AnsibleHostResult_Status_Failed
+				case hostResult.Skipped:
+					result.Status = AnsibleHostResult_Status_Skipped
+				case hostResult.Changed:
+					result.Status = AnsibleHostResult_Status_Changed
+				}
+				if result.Failed() {
+					result.Message = hostResult.Msg
+				}
+				task.Results = append(task.Results, result)
+			}
+			play.Tasks = append(play.Tasks, task)
+		}
+		log.Plays = append(log.Plays, play)
+	}
+	return log, nil
+}
+
+// parseAnsibleTextLog parses the output of the Ansible default callback. Lines that are not banners, host results or
+// recap lines are ignored.
+func parseAnsibleTextLog(text string) *AnsibleLog {
+	log := &AnsibleLog{Stats: map[string]AnsibleHostStats{}}
+	var play *AnsiblePlay
+	var task *AnsibleTask
+	inRecap := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \r\t")
+		if ansibleRecapRegexp.MatchString(line) {
+			inRecap = true
+			continue
+		}
+		if match := ansibleBannerRegexp.FindStringSubmatch(line); match != nil {
+			inRecap = false
+			if match[1] == "PLAY" {
+				log.Plays = append(log.Plays, AnsiblePlay{Name: match[2]})
+				play, task = &log.Plays[len(log.Plays)-1], nil
+				continue
+			}
+			if play == nil {
+				log.Plays = append(log.Plays, AnsiblePlay{})
+				play = &log.Plays[len(log.Plays)-1]
+			}
+			play.Tasks = append(play.Tasks, AnsibleTask{Name: match[2]})
+			task = &play.Tasks[len(play.Tasks)-1]
+			continue
+		}
+		if inRecap {
+			if match := ansibleStatsRegexp.FindStringSubmatch(line); match != nil {
+				log.Stats[match[1]] = parseAnsibleHostStats(match[2])
+			}
+			continue
+		}
+		if match := ansibleResultRegexp.FindStringSubmatch(line); match != nil && task != nil {
+			task.Results = append(task.Results, parseAnsibleHostResult(match))
+		}
+	}
+	return log
+}
+
+func parseAnsibleHostResult(match []string) AnsibleHostResult {
+	result := AnsibleHostResult{Host: match[2]}
+	switch {
+	case match[5] == "UNREACHABLE":
+		result.Status = AnsibleHostResult_Status_Unreachable
+	case match[1] == "fatal" || match[1] == "failed":
+		result.Status = AnsibleHostResult_Status_Failed
+	case match[1] == "skipping":
+		result.Status = AnsibleHostResult_Status_Skipped
+	default:
+		result.Status = match[1]
+	}
+	if result.Failed() && match[7] != "" {
+		var body struct {
+			Msg string `json:"msg"`
+		}
+		if err := json.Unmarshal([]byte(match[7]), &body); err == nil && body.Msg != "" {
+			result.Message = body.Msg
+		} else {
+			result.Message = match[7]
+		}
+	}
+	return result
+}
+
+func parseAnsibleHostStats(counters string) (stats AnsibleHostStats) {
+	for _, counter := range strings.Fields(counters) {
+		separator := strings.Index(counter, "=")
+		value, _ := strconv.ParseInt(counter[separator+1:], 10, 64)
+		switch counter[:separator] {
+		case "ok":
+			stats.Ok = value
+		case "changed":
+			stats.Changed = value
+		case "unreachable":
+			stats.Unreachable = value
+		case "failed":
+			stats.Failed = value
+		case "skipped":
+			stats.Skipped = value
+		case "rescued":
+			stats.Rescued = value
+		case "ignored":
+			stats.Ignored = value
+		}
+	}
+	return
+}
+
+// Text returns the log as the Ansible default callback writes it.
+func (log *AnsibleLog) Text() string {
+	var text strings.Builder
+	banner := func(title string) {
+		stars := 80 - len(title) - 1
+		if stars < 3 {
+			stars = 3
+		}
+		text.WriteString(title + " " + strings.Repeat("*", stars) + "\n")
+	}
+	for _, play := range log.Plays {
+		banner("PLAY [" + play.Name + "]")
+		text.WriteString("\n")
+		for _, task := range play.Tasks {
+			banner("TASK [" + task.Name + "]")
+			for _, result := range task.Results {
+				text.WriteString(formatAnsibleHostResult(result) + "\n")
+			}
+			text.WriteString("\n")
+		}
+	}
+	if len(log.Stats) > 0 {
+		banner("PLAY RECAP")
+		for _, host := range sortedKeys(log.Stats) {
+			stats := log.Stats[host]
+			fmt.Fprintf(&text, "%-26s : ok=%-4d changed=%-4d unreachable=%-4d failed=%-4d skipped=%-4d rescued=%-4d ignored=%d\n",
+				host, stats.Ok, stats.Changed, stats.Unreachable, stats.Failed, stats.Skipped, stats.Rescued, stats.Ignored)
+		}
+	}
+	return text.String()
+}
+
+func formatAnsibleHostResult(result AnsibleHostResult) string {
+	var line string
+	switch result.Status {
+	case AnsibleHostResult_Status_Failed:
+		line = "fatal: [" + result.Host + "]: FAILED!"
+	case AnsibleHostResult_Status_Unreachable:
+		line = "fatal: [" + result.Host + "]: UNREACHABLE!"
+	case AnsibleHostResult_Status_Skipped:
+		line = "skipping: [" + result.Host + "]"
+	default:
+		line = result.Status + ": [" + result.Host + "]"
+	}
+	if result.Failed() && result.Message != "" {
+		body, _ := json.Marshal(map[string]string{"msg": result.Message})
+		line += " => " + string(body)
+	}
+	return line
+}
+
+// Render returns the log in a job log format: the output of the Ansible json callback for JSON, and the output of the
+// default callback in a code block, a preformatted element or an RTF document for the other formats.
+func (log *AnsibleLog) Render(format string) ([]byte, error) {
+	switch format {
+	case JobLog_Format_JSON:
+		return log.renderJSON()
+	case JobLog_Format_Markdown:
+		return []byte("```\n" + log.Text() + "```\n"), nil
+	case JobLog_Format_HTML:
+		return []byte("<pre>" + html.EscapeString(log.Text()) + "</pre>\n"), nil
+	case JobLog_Format_Rtf:
+		return []byte(renderRTF(log.Text())), nil
+	}
+	return nil, fmt.Errorf("unsupported job log format %q", format)
+}
+
+func (log *AnsibleLog) renderJSON() ([]byte, error) {
+	type jsonTask struct {
+		Task struct {
+			Name string `json:"name"`
+		} `json:"task"`
+		Hosts map[string]ansibleJSONHostResult `json:"hosts"`
+	}
+	type jsonPlay struct {
+		Play struct {
+			Name string `json:"name"`
+		} `json:"play"`
+		Tasks []jsonTask `json:"tasks"`
+	}
+	raw := struct {
+		Plays []jsonPlay                  `json:"plays"`
+		Stats map[string]AnsibleHostStats `json:"stats"`
+	}{Plays: []jsonPlay{}, Stats: log.Stats}
+	if raw.Stats == nil {
+		raw.Stats = map[string]AnsibleHostStats{}
+	}
+	for _, play := range log.Plays {
+		rawPlay := jsonPlay{Tasks: []jsonTask{}}
+		rawPlay.Play.Name = play.Name
+		for _, task := range play.Tasks {
+			rawTask := jsonTask{Hosts: map[string]ansibleJSONHostResult{}}
+			rawTask.Task.Name = task.Name
+			for _, result := range task.Results {
+				hostResult := ansibleJSONHostResult{
+					Changed:     result.Status == AnsibleHostResult_Status_Changed,
+					Failed:      result.Status == AnsibleHostResult_Status_Failed,
+					Skipped:     result.Status == AnsibleHostResult_Status_Skipped,
+					Unreachable: result.Status == AnsibleHostResult_Status_Unreachable,
+				}
+				if result.Failed() {
+					hostResult.Msg = result.Message
+				}
+				rawTask.Hosts[result.Host] = hostResult
+			}
+			rawPlay.Tasks = append(rawPlay.Tasks, rawTask)
+		}
+		raw.Plays = append(raw.Plays, rawPlay)
+	}
+	return json.MarshalIndent(raw, "", "    ")
+}
+
+// renderRTF returns text as an RTF document. Characters outside the BMP are written as the two escapes of their UTF-16
+// surrogate pair.
+func renderRTF(text string) string {
+	var rtf strings.Builder
+	rtf.WriteString("{\\rtf1\\ansi\\deff0 {\\fonttbl {\\f0 Courier;}}\n\\f0 ")
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '{' || r == '}':
+			rtf.WriteString("\\" + string(r))
+		case r == '\n':
+			rtf.WriteString("\\par\n")
+		case r > 0xFFFF:
+			high, low := utf16.EncodeRune(r)
+			fmt.Fprintf(&rtf, "\\u%d?\\u%d?", int16(high), int16(low))
+		case r > 127:
+			fmt.Fprintf(&rtf, "\\u%d?", int16(r))
+		default:
+			rtf.WriteRune(r)
+		}
+	}
+	rtf.WriteString("}\n")
+	return rtf.String()
+}
+
+// JobLogPlainText returns the details of a job log in the given format as plain text: HTML tags and RTF control words
+// are removed, Markdown code fences and emphasis are dropped, and JSON logs are rendered as the Ansible default callback
+// output.
+func JobLogPlainText(format string, details []byte) (string, error) {
+	switch format {
+	case JobLog_Format_JSON:
+		log, err := decodeAnsibleJSONLog(details)
+		if err != nil {
+			return "", err
+		}
+		return log.Text(), nil
+	case JobLog_Format_HTML:
+		text := htmlDropRegexp.ReplaceAllString(string(details), "")
+		text = htmlBreakRegexp.ReplaceAllString(text, "$0\n")
+		text = htmlTagRegexp.ReplaceAllString(text, "")
+		return html.UnescapeString(text), nil
+	case JobLog_Format_Rtf:
+		return stripRTF(string(details)), nil
+	case JobLog_Format_Markdown, "":
+		return stripMarkdown(string(details)), nil
+	}
+	return "", fmt.Errorf("unsupported job log format %q", format)
+}
+
+// stripMarkdown removes code fences, heading markers and emphasis. The content of code blocks is kept as is.
+func stripMarkdown(markdown string) string {
+	var text []string
+	inCode := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode {
+			line = markdownHeadingRegexp.ReplaceAllString(line, "")
+			line = markdownEmphasisRegexp.ReplaceAllString(line, "")
+		}
+		text = append(text, line)
+	}
+	return strings.Join(text, "\n")
+}
+
+// rtfSkippedDestinations are the RTF groups whose content is not text.
+var rtfSkippedDestinations = []string{"colortbl", "fonttbl", "info", "pict", "stylesheet"}
+
+// stripRTF returns the text of an RTF document.
+func stripRTF(rtf string) string {
+	var text strings.Builder
+	// skipDepth is the group depth at which a skipped destination started, or 0.
+	depth, skipDepth := 0, 0
+	// highSurrogate is the first half of a surrogate pair whose second \u escape has not been read yet, or 0.
+	var highSurrogate rune
+	for i := 0; i < len(rtf); i++ {
+		c := rtf[i]
+		switch c {
+		case '{':
+			depth++
+			if strings.HasPrefix(rtf[i+1:], "\\*") && skipDepth == 0 {
+				skipDepth = depth
+			}
+		case '}':
+			if depth == skipDepth {
+				skipDepth = 0
+			}
+			depth--
+		case '\\':
+			if i+1 >= len(rtf) {
+				break
+			}
+			next := rtf[i+1]
+			if next == '\\' || next == '{' || next == '}' {
+				if skipDepth == 0 {
+					text.WriteByte(next)
+				}
+				i++
+				break
+			}
+			if next == '\'' && i+3 < len(rtf) {
+				if value, err := strconv.ParseUint(rtf[i+2:i+4], 16, 8); err == nil && skipDepth == 0 {
+					text.WriteRune(rune(value))
+				}
+				i += 3
+				break
+			}
+			end := i + 1
+			for end < len(rtf) && (rtf[end] >= 'a' && rtf[end] <= 'z' || rtf[end] >= 'A' && rtf[end] <= 'Z') {
+				end++
+			}
+			word := rtf[i+1 : end]
+			paramStart := end
+			if end < len(rtf) && rtf[end] == '-' {
+				end++
+			}
+			for end < len(rtf) && rtf[end] >= '0' && rtf[end] <= '9' {
+				end++
+			}
+			param := rtf[paramStart:end]
+			if end < len(rtf) && rtf[end] == ' ' {
+				end++
+			}
+			i = end - 1
+			if word == "" {
+				// A control symbol such as \~ or \-.
+				i++
+				if skipDepth == 0 && next == '~' {
+					text.WriteByte(' ')
+				}
+				break
+			}
+			if containsString(rtfSkippedDestinations, word) && skipDepth == 0 {
+				skipDepth = depth
+			}
+			if skipDepth != 0 {
+				break
+			}
+			switch word {
+			case "par", "line":
+				text.WriteByte('\n')
+			case "tab":
+				text.WriteByte('\t')
+			case "u":
+				if value, err := strconv.Atoi(param); err == nil {
+					if value < 0 {
+						value += 65536
+					}
+					switch r := rune(value); {
+					case utf16.IsSurrogate(r) && r < 0xDC00:
+						highSurrogate = r
+					case highSurrogate != 0:
+						if combined := utf16.DecodeRune(highSurrogate, r); combined != utf8.RuneError {
+							text.WriteRune(combined)
+						} else {
+							text.WriteRune(utf8.RuneError)
+							text.WriteRune(r)
+						}
+						highSurrogate = 0
+					default:
+						text.WriteRune(r)
+					}
+					// Skip the replacement character that follows a unicode escape.
+					if i+1 < len(rtf) && rtf[i+1] != '\\' && rtf[i+1] != '{' && rtf[i+1] != '}' {
+						i++
+					}
+				}
+			}
+		case '\r', '\n':
+		default:
+			if skipDepth == 0 {
+				text.WriteByte(c)
+			}
+		}
+	}
+	return text.String()
+}
+
+// AnsibleLog decodes the details of the job log.
+func (jobLog *JobLog) AnsibleLog() (*AnsibleLog, error) {
+	if jobLog.Details == nil {
+		return &AnsibleLog{Stats: map[string]AnsibleHostStats{}}, nil
+	}
+	return DecodeAnsibleLog(jobLogFormat(jobLog), *jobLog.Details)
+}
+
+// PlainText returns the details of the job log as plain text.
+func (jobLog *JobLog) PlainText() (string, error) {
+	if jobLog.Details == nil {
+		return "", nil
+	}
+	return JobLogPlainText(jobLogFormat(jobLog), *jobLog.Details)
+}
+
+// ConvertJobLog returns a copy of the job log with its details decoded and rendered in another format.
+func ConvertJobLog(jobLog *JobLog, format string) (*JobLog, error) {
+	log, err := jobLog.AnsibleLog()
+	if err != nil {
+		return nil, err
+	}
+	details, err := log.Render(format)
+	if err != nil {
+		return nil, err
+	}
+	converted := *jobLog
+	converted.Format = core.StringPtr(format)
+	converted.Details = &details
+	return &converted, nil
+}
+
+// jobLogFormat returns the format of a job log, defaulting to Markdown for logs without format.
+func jobLogFormat(jobLog *JobLog) string {
+	if jobLog.Format == nil || *jobLog.Format == "" {
+		return JobLog_Format_Markdown
+	}
+	return *jobLog.Format
+}
+
+// GetJobAnsibleLog : Get the decoded Ansible log of a job
+// Get the job log with ListJobLogs and decode its details according to their format.
+func (schematics *SchematicsV1) GetJobAnsibleLog(listJobLogsOptions *ListJobLogsOptions) (result *AnsibleLog, response *core.DetailedResponse, err error) {
+	jobLog, response, err := schematics.ListJobLogs(listJobLogsOptions)
+	if err != nil {
+		return
+	}
+	result, err = jobLog.AnsibleLog()
+	return
+}
@@ -0,0 +1,187 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe(`Job logs`, func() {
+	textLog := "PLAY [web servers] *************************************************************\n" +
+		"\n" +
+		"TASK [Gathering Facts] *********************************************************\n" +
+		"ok: [web-0]\n" +
+		"fatal: [web-1]: UNREACHABLE! => {\"changed\": false, \"msg\": \"Failed to connect\", \"unreachable\": true}\n" +
+		"\n" +
+		"TASK [install nginx] ***********************************************************\n" +
+		"changed: [web-0]\n" +
+		"\n" +
+		"TASK [start nginx] *************************************************************\n" +
+		"fatal: [web-0]: FAILED! => {\"changed\": false, \"msg\": \"Unit nginx not found.\"}\n" +
+		"\n" +
+		"PLAY RECAP *********************************************************************\n" +
+		"web-0                      : ok=2    changed=1    unreachable=0    failed=1    skipped=0    rescued=0    ignored=0\n" +
+		"web-1                      : ok=0    changed=0    unreachable=1    failed=0    skipped=0    rescued=0    ignored=0\n"
+
+	expected := &schematicsv1.AnsibleLog{
+		Plays: []schematicsv1.AnsiblePlay{{
+			Name: "web servers",
+			Tasks: []schematicsv1.AnsibleTask{
+				{Name: "Gathering Facts", Results: []schematicsv1.AnsibleHostResult{
+					{Host: "web-0", Status: "ok"},
+					{Host: "web-1", Status: "unreachable", Message: "Failed to connect"},
+				}},
+				{Name: "install nginx", Results: []schematicsv1.AnsibleHostResult{{Host: "web-0", Status: "changed"}}},
+				{Name: "start nginx", Results: []schematicsv1.AnsibleHostResult{{Host: "web-0", Status: "failed", Message: "Unit nginx not found."}}},
+			},
+		}},
+		Stats: map[string]schematicsv1.AnsibleHostStats{
+			"web-0": {Ok: 2, Changed: 1, Failed: 1},
+			"web-1": {Unreachable: 1},
+		},
+	}
+
+	Describe(`DecodeAnsibleLog(format string, details []byte)`, func() {
+		It(`Decode the default callback output`, func() {
+			log, err := schematicsv1.DecodeAnsibleLog("markdown", []byte("```\n"+textLog+"```\n"))
+			Expect(err).To(BeNil())
+			Expect(log).To(Equal(expected))
+			Expect(log.Plays[0].Tasks[1].Changed()).To(BeTrue())
+			Expect(log.Plays[0].Tasks[1].Failed()).To(BeFalse())
+			Expect(log.FailedTasks()).To(HaveLen(2))
+		})
+		It(`Decode the json callback output`, func() {
+			log, err := schematicsv1.DecodeAnsibleLog("json", []byte(`{
+				"plays": [{"play": {"name": "web servers"}, "tasks": [
+					{"task": {"name": "Gathering Facts"}, "hosts": {"web-1": {"unreachable": true, "msg": "Failed to connect"}, "web-0": {"changed": false}}},
+					{"task": {"name": "install nginx"}, "hosts": {"web-0": {"changed": true}}},
+					{"task": {"name": "start nginx"}, "hosts": {"web-0": {"failed": true, "msg": "Unit nginx not found."}}}
+				]}],
+				"stats": {"web-0": {"ok": 2, "changed": 1, "failures": 1}, "web-1": {"unreachable": 1}}
+			}`))
+			Expect(err).To(BeNil())
+			Expect(log).To(Equal(expected))
+		})
+		It(`Return an error for invalid details`, func() {
+			_, err := schematicsv1.DecodeAnsibleLog("json", []byte(`[`))
+			Expect(err).ToNot(BeNil())
+			_, err = schematicsv1.DecodeAnsibleLog("pdf", []byte(``))
+			Expect(err).To(MatchError(`unsupported job log format "pdf"`))
+		})
+	})
+
+	Describe(`Render(format string)`, func() {
+		It(`Round trip through every format`, func() {
+			Expect(strings.SplitAfter(expected.Text(), "\n")[:4]).To(Equal(strings.SplitAfter(textLog, "\n")[:4]))
+			for _, format := range []string{"json", "markdown", "html", "rtf"} {
+				details, err := expected.Render(format)
+				Expect(err).To(BeNil())
+				log, err := schematicsv1.DecodeAnsibleLog(format, details)
+				Expect(err).To(BeNil())
+				Expect(log).To(Equal(expected), format)
+			}
+		})
+	})
+
+	Describe(`JobLogPlainText(format string, details []byte)`, func() {
+		It(`Strip HTML`, func() {
+			text, err := schematicsv1.JobLogPlainText("html", []byte(`<html><style>p {}</style><p>ok: [a&amp;b]</p><div>done<br/>&lt;end&gt;</div></html>`))
+			Expect(err).To(BeNil())
+			Expect(text).To(Equal("ok: [a&b]\ndone\n<end>\n"))
+		})
+		It(`Strip RTF`, func() {
+			text, err := schematicsv1.JobLogPlainText("rtf", []byte(`{\rtf1\ansi{\fonttbl{\f0 Courier;}}{\*\generator x;}\f0 TASK [a\{b\}] \'e9\u8364?\par
+ok\tab done\par}`))
+			Expect(err).To(BeNil())
+			Expect(text).To(Equal("TASK [a{b}] é€\nok\tdone\n"))
+		})
+		It(`Round trip characters outside the BMP through RTF`, func() {
+			log := &schematicsv1.AnsibleLog{
+				Plays: []schematicsv1.AnsiblePlay{{Name: "deploy 🚀 to café", Tasks: []schematicsv1.AnsibleTask{{Name: "𝄞 notes"}}}},
+				Stats: map[string]schematicsv1.AnsibleHostStats{},
+			}
+			details, err := log.Render("rtf")
+			Expect(err).To(BeNil())
+			Expect(string(details)).To(ContainSubstring(`deploy \u-10179?\u-8576? to caf\u233?`))
+			text, err := schematicsv1.JobLogPlainText("rtf", details)
+			Expect(err).To(BeNil())
+			Expect(text).To(Equal(log.Text()))
+			Expect(text).To(ContainSubstring("deploy 🚀 to café"))
+			Expect(text).To(ContainSubstring("𝄞 notes"))
+		})
+		It(`Strip Markdown`, func() {
+			text, err := schematicsv1.JobLogPlainText("markdown", []byte("# Job **log**\n```\nPLAY [x] ***\n```\n"))
+			Expect(err).To(BeNil())
+			Expect(text).To(Equal("Job log\nPLAY [x] ***\n"))
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				Expect(req.URL.EscapedPath()).To(Equal("/v2/jobs/job-1/logs"))
+				Expect(req.Method).To(Equal("GET"))
+				res.Header().Set("Content-type", "application/json")
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"job_id": "job-1", "format": "html", "details": "%s"}`,
+					base64.StdEncoding.EncodeToString([]byte("<pre>"+textLog+"</pre>")))
+			}))
+		})
+		It(`Invoke GetJobAnsibleLog successfully`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.GetJobAnsibleLog(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			result, response, operationErr = schematicsService.GetJobAnsibleLog(schematicsService.NewListJobLogsOptions("job-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(result).To(Equal(expected))
+
+			jobLog, _, operationErr := schematicsService.ListJobLogs(schematicsService.NewListJobLogsOptions("job-1"))
+			Expect(operationErr).To(BeNil())
+			converted, err := schematicsv1.ConvertJobLog(jobLog, "json")
+			Expect(err).To(BeNil())
+			Expect(*converted.Format).To(Equal("json"))
+			Expect(*jobLog.Format).To(Equal("html"))
+			text, err := converted.PlainText()
+			Expect(err).To(BeNil())
+			Expect(text).To(ContainSubstring("fatal: [web-0]: FAILED! => {\"msg\":\"Unit nginx not found.\"}\n"))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
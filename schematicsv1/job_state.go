@@ -0,0 +1,325 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SummaryValues returns the state summary by name, with the values of 'number' items parsed as float64 and the values
+// of other items as string. An error is returned when a 'number' item does not hold a number.
+func (data *JobStateData) SummaryValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, item := range data.Summary {
+		if item.Name == nil {
+			continue
+		}
+		value := stringValue(item.Value)
+		if item.Type != nil && *item.Type == JobStateDataSummaryItem_Type_Number {
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("state summary %s: %q is not a number", *item.Name, value)
+			}
+			values[*item.Name] = number
+			continue
+		}
+		values[*item.Name] = value
+	}
+	return values, nil
+}
+
+// SummaryNumber returns the value of a 'number' summary item.
+func (data *JobStateData) SummaryNumber(name string) (float64, bool) {
+	for _, item := range data.Summary {
+		if item.Name != nil && *item.Name == name && item.Type != nil && *item.Type == JobStateDataSummaryItem_Type_Number {
+			number, err := strconv.ParseFloat(strings.TrimSpace(stringValue(item.Value)), 64)
+			return number, err == nil
+		}
+	}
+	return 0, false
+}
+
+// SummaryString returns the value of a summary item as it is stored.
+func (data *JobStateData) SummaryString(name string) (string, bool) {
+	for _, item := range data.Summary {
+		if item.Name != nil && *item.Name == name {
+			return stringValue(item.Value), true
+		}
+	}
+	return "", false
+}
+
+// JobStateDetails : The decoded state details of an action job.
+type JobStateDetails struct {
+	// The facts and state of each host.
+	Hosts map[string]*JobHostState `json:"hosts"`
+}
+
+// JobHostState : The facts and state of a host.
+type JobHostState struct {
+	// The Ansible facts gathered from the host.
+	Facts map[string]interface{} `json:"facts,omitempty"`
+
+	// The other state recorded for the host.
+	State map[string]interface{} `json:"state,omitempty"`
+}
+
+// DecodeJobStateDetails decodes the details of a job state. The details are a JSON object of hosts, either at the top
+// level or under 'hosts'. The 'facts' or 'ansible_facts' entry of a host holds its facts; its other entries, or the
+// content of its 'state' entry, are its state.
+func DecodeJobStateDetails(details []byte) (*JobStateDetails, error) {
+	decoded := &JobStateDetails{Hosts: map[string]*JobHostState{}}
+	if len(strings.TrimSpace(string(details))) == 0 {
+		return decoded, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(details, &raw); err != nil {
+		return nil, fmt.Errorf("decoding job state details: %s", err.Error())
+	}
+	if hosts, ok := raw["hosts"].(map[string]interface{}); ok {
+		raw = hosts
+	}
+	for host, value := range raw {
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("decoding job state details: host %s is not an object", host)
+		}
+		hostState := &JobHostState{}
+		for key, entry := range entries {
+			switch key {
+			case "facts", "ansible_facts":
+				facts, ok := entry.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("decoding job state details: facts of host %s are not an object", host)
+				}
+				hostState.Facts = facts
+			case "state":
+				if state, ok := entry.(map[string]interface{}); ok {
+					for stateKey, stateValue := range state {
+						hostState.setState(stateKey, stateValue)
+					}
+					continue
+				}
+				hostState.setState(key, entry)
+			default:
+				hostState.setState(key, entry)
+			}
+		}
+		decoded.Hosts[host] = hostState
+	}
+	return decoded, nil
+}
+
+func (hostState *JobHostState) setState(key string, value interface{}) {
+	if hostState.State == nil {
+		hostState.State = map[string]interface{}{}
+	}
+	hostState.State[key] = value
+}
+
+// DecodedDetails decodes the details of the job state.
+func (data *JobStateData) DecodedDetails() (*JobStateDetails, error) {
+	if data.Details == nil {
+		return DecodeJobStateDetails(nil)
+	}
+	return DecodeJobStateDetails(*data.Details)
+}
+
+// JobStateChange : A value that differs between two job states.
+type JobStateChange struct {
+	// The path of the value, such as 'facts.ansible_distribution_version'. Nested objects are separated by dots.
+	Path string `json:"path"`
+
+	// The previous value. Nil when the value was added.
+	Previous interface{} `json:"previous,omitempty"`
+
+	// The current value. Nil when the value was removed.
+	Current interface{} `json:"current,omitempty"`
+}
+
+// JobStateDiff : The differences between two job states.
+type JobStateDiff struct {
+	// The changed summary values, by summary name.
+	Summary []JobStateChange `json:"summary,omitempty"`
+
+	// The hosts that are only in the current state.
+	AddedHosts []string `json:"added_hosts,omitempty"`
+
+	// The hosts that are only in the previous state.
+	RemovedHosts []string `json:"removed_hosts,omitempty"`
+
+	// The changed facts and state values of the hosts in both states.
+	Hosts map[string][]JobStateChange `json:"hosts,omitempty"`
+}
+
+// IsEmpty reports whether the two states are the same.
+func (diff *JobStateDiff) IsEmpty() bool {
+	return len(diff.Summary) == 0 && len(diff.AddedHosts) == 0 && len(diff.RemovedHosts) == 0 && len(diff.Hosts) == 0
+}
+
+// CompareJobStates returns the differences between the state of a previous and a current job, typically two runs of
+// the same action.
+func CompareJobStates(previous *JobStateData, current *JobStateData) (*JobStateDiff, error) {
+	previousSummary, err := previous.SummaryValues()
+	if err != nil {
+		return nil, err
+	}
+	currentSummary, err := current.SummaryValues()
+	if err != nil {
+		return nil, err
+	}
+	previousDetails, err := previous.DecodedDetails()
+	if err != nil {
+		return nil, err
+	}
+	currentDetails, err := current.DecodedDetails()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &JobStateDiff{Hosts: map[string][]JobStateChange{}}
+	diff.Summary = compareStateValues("", previousSummary, currentSummary, false)
+	for _, host := range sortedKeys(currentDetails.Hosts) {
+		if _, found := previousDetails.Hosts[host]; !found {
+			diff.AddedHosts = append(diff.AddedHosts, host)
+		}
+	}
+	for _, host := range sortedKeys(previousDetails.Hosts) {
+		currentHost, found := currentDetails.Hosts[host]
+		if !found {
+			diff.RemovedHosts = append(diff.RemovedHosts, host)
+			continue
+		}
+		previousHost := previousDetails.Hosts[host]
+		changes := compareStateValues("facts.", previousHost.Facts, currentHost.Facts, true)
+		changes = append(changes, compareStateValues("state.", previousHost.State, currentHost.State, true)...)
+		if len(changes) > 0 {
+			diff.Hosts[host] = changes
+		}
+	}
+	if len(diff.Hosts) == 0 {
+		diff.Hosts = nil
+	}
+	return diff, nil
+}
+
+// compareStateValues returns the changes between two maps, sorted by path. Nested maps are compared entry by entry
+// when recurse is set; other values are compared as a whole.
+func compareStateValues(prefix string, previous map[string]interface{}, current map[string]interface{}, recurse bool) (changes []JobStateChange) {
+	keys := map[string]bool{}
+	for key := range previous {
+		keys[key] = true
+	}
+	for key := range current {
+		keys[key] = true
+	}
+	for _, key := range sortedKeys(keys) {
+		previousValue, inPrevious := previous[key]
+		currentValue, inCurrent := current[key]
+		if recurse && inPrevious && inCurrent {
+			previousMap, previousIsMap := previousValue.(map[string]interface{})
+			currentMap, currentIsMap := currentValue.(map[string]interface{})
+			if previousIsMap && currentIsMap {
+				changes = append(changes, compareStateValues(prefix+key+".", previousMap, currentMap, true)...)
+				continue
+			}
+		}
+		if inPrevious && inCurrent && reflect.DeepEqual(previousValue, currentValue) {
+			continue
+		}
+		changes = append(changes, JobStateChange{Path: prefix + key, Previous: previousValue, Current: currentValue})
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return
+}
+
+// DiffJobStatesOptions : The DiffJobStates options.
+type DiffJobStatesOptions struct {
+	// The ID of the previous job.
+	PreviousJobID *string `json:"previous_job_id" validate:"required,ne="`
+
+	// The ID of the current job.
+	CurrentJobID *string `json:"current_job_id" validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDiffJobStatesOptions : Instantiate DiffJobStatesOptions
+func (*SchematicsV1) NewDiffJobStatesOptions(previousJobID string, currentJobID string) *DiffJobStatesOptions {
+	return &DiffJobStatesOptions{
+		PreviousJobID: core.StringPtr(previousJobID),
+		CurrentJobID:  core.StringPtr(currentJobID),
+	}
+}
+
+// SetPreviousJobID : Allow user to set PreviousJobID
+func (options *DiffJobStatesOptions) SetPreviousJobID(previousJobID string) *DiffJobStatesOptions {
+	options.PreviousJobID = core.StringPtr(previousJobID)
+	return options
+}
+
+// SetCurrentJobID : Allow user to set CurrentJobID
+func (options *DiffJobStatesOptions) SetCurrentJobID(currentJobID string) *DiffJobStatesOptions {
+	options.CurrentJobID = core.StringPtr(currentJobID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DiffJobStatesOptions) SetHeaders(param map[string]string) *DiffJobStatesOptions {
+	options.Headers = param
+	return options
+}
+
+// DiffJobStates : Compare the states of two jobs
+// Get the state of both jobs with ListJobStates and compare them with CompareJobStates.
+func (schematics *SchematicsV1) DiffJobStates(diffJobStatesOptions *DiffJobStatesOptions) (result *JobStateDiff, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(diffJobStatesOptions, "diffJobStatesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(diffJobStatesOptions, "diffJobStatesOptions")
+	if err != nil {
+		return
+	}
+
+	previous, response, err := schematics.ListJobStates(&ListJobStatesOptions{
+		JobID:   diffJobStatesOptions.PreviousJobID,
+		Headers: diffJobStatesOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	current, response, err := schematics.ListJobStates(&ListJobStatesOptions{
+		JobID:   diffJobStatesOptions.CurrentJobID,
+		Headers: diffJobStatesOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	result, err = CompareJobStates(previous, current)
+	return
+}
@@ -0,0 +1,161 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Job states`, func() {
+	summaryItem := func(name string, itemType string, value string) schematicsv1.JobStateDataSummaryItem {
+		return schematicsv1.JobStateDataSummaryItem{Name: core.StringPtr(name), Type: core.StringPtr(itemType), Value: core.StringPtr(value)}
+	}
+	details := func(json string) *[]byte {
+		bytes := []byte(json)
+		return &bytes
+	}
+
+	Describe(`SummaryValues()`, func() {
+		It(`Return typed summary values`, func() {
+			data := &schematicsv1.JobStateData{Summary: []schematicsv1.JobStateDataSummaryItem{
+				summaryItem("hosts", "number", "3"),
+				summaryItem("duration", "number", " 12.5 "),
+				summaryItem("os", "string", "rhel"),
+			}}
+			values, err := data.SummaryValues()
+			Expect(err).To(BeNil())
+			Expect(values).To(Equal(map[string]interface{}{"hosts": 3.0, "duration": 12.5, "os": "rhel"}))
+
+			number, ok := data.SummaryNumber("hosts")
+			Expect(ok).To(BeTrue())
+			Expect(number).To(Equal(3.0))
+			_, ok = data.SummaryNumber("os")
+			Expect(ok).To(BeFalse())
+			text, ok := data.SummaryString("os")
+			Expect(ok).To(BeTrue())
+			Expect(text).To(Equal("rhel"))
+		})
+		It(`Reject invalid numbers`, func() {
+			data := &schematicsv1.JobStateData{Summary: []schematicsv1.JobStateDataSummaryItem{summaryItem("hosts", "number", "three")}}
+			_, err := data.SummaryValues()
+			Expect(err).To(MatchError(`state summary hosts: "three" is not a number`))
+		})
+	})
+
+	Describe(`DecodeJobStateDetails(details []byte)`, func() {
+		It(`Decode facts and state of hosts`, func() {
+			decoded, err := schematicsv1.DecodeJobStateDetails([]byte(`{"hosts": {
+				"web-0": {"ansible_facts": {"distribution": "RedHat"}, "state": {"nginx": "running"}},
+				"web-1": {"facts": {"distribution": "Ubuntu"}, "packages": ["nginx"]}
+			}}`))
+			Expect(err).To(BeNil())
+			Expect(decoded.Hosts["web-0"]).To(Equal(&schematicsv1.JobHostState{
+				Facts: map[string]interface{}{"distribution": "RedHat"},
+				State: map[string]interface{}{"nginx": "running"},
+			}))
+			Expect(decoded.Hosts["web-1"].State).To(Equal(map[string]interface{}{"packages": []interface{}{"nginx"}}))
+
+			decoded, err = schematicsv1.DecodeJobStateDetails([]byte(`{"web-0": {"ansible_facts": {}}}`))
+			Expect(err).To(BeNil())
+			Expect(decoded.Hosts).To(HaveKey("web-0"))
+
+			_, err = schematicsv1.DecodeJobStateDetails([]byte(`{"web-0": 1}`))
+			Expect(err).To(MatchError(`decoding job state details: host web-0 is not an object`))
+		})
+	})
+
+	Describe(`CompareJobStates(previous *JobStateData, current *JobStateData)`, func() {
+		It(`Return the differences`, func() {
+			previous := &schematicsv1.JobStateData{
+				Summary: []schematicsv1.JobStateDataSummaryItem{summaryItem("hosts", "number", "2"), summaryItem("os", "string", "rhel")},
+				Details: details(`{"web-0": {"ansible_facts": {"distribution": {"name": "RedHat", "version": "8.2"}}, "nginx": "stopped"}, "web-1": {}}`),
+			}
+			current := &schematicsv1.JobStateData{
+				Summary: []schematicsv1.JobStateDataSummaryItem{summaryItem("hosts", "number", "2.0"), summaryItem("os", "string", "ubuntu")},
+				Details: details(`{"web-0": {"ansible_facts": {"distribution": {"name": "RedHat", "version": "8.4"}}, "nginx": "running", "port": 80}, "web-2": {}}`),
+			}
+			diff, err := schematicsv1.CompareJobStates(previous, current)
+			Expect(err).To(BeNil())
+			Expect(diff.IsEmpty()).To(BeFalse())
+			Expect(diff.Summary).To(Equal([]schematicsv1.JobStateChange{{Path: "os", Previous: "rhel", Current: "ubuntu"}}))
+			Expect(diff.AddedHosts).To(Equal([]string{"web-2"}))
+			Expect(diff.RemovedHosts).To(Equal([]string{"web-1"}))
+			Expect(diff.Hosts).To(Equal(map[string][]schematicsv1.JobStateChange{"web-0": {
+				{Path: "facts.distribution.version", Previous: "8.2", Current: "8.4"},
+				{Path: "state.nginx", Previous: "stopped", Current: "running"},
+				{Path: "state.port", Current: 80.0},
+			}}))
+
+			diff, err = schematicsv1.CompareJobStates(current, current)
+			Expect(err).To(BeNil())
+			Expect(diff.IsEmpty()).To(BeTrue())
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				switch req.URL.EscapedPath() {
+				case "/v2/jobs/job-1/states":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"job_id": "job-1", "details": "%s"}`, base64.StdEncoding.EncodeToString([]byte(`{"web-0": {"nginx": "stopped"}}`)))
+				case "/v2/jobs/job-2/states":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"job_id": "job-2", "details": "%s"}`, base64.StdEncoding.EncodeToString([]byte(`{"web-0": {"nginx": "running"}}`)))
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke DiffJobStates successfully`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.DiffJobStates(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			result, response, operationErr = schematicsService.DiffJobStates(schematicsService.NewDiffJobStatesOptions("job-1", "job-2"))
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(result.Hosts["web-0"]).To(Equal([]schematicsv1.JobStateChange{{Path: "state.nginx", Previous: "stopped", Current: "running"}}))
+
+			_, response, operationErr = schematicsService.DiffJobStates(schematicsService.NewDiffJobStatesOptions("job-1", "job-3"))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(response.StatusCode).To(Equal(404))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
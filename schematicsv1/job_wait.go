@@ -0,0 +1,440 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+	"time"
+)
+
+// JobStatusAction_StatusCode_JobFinished is the status code the service reports for a finished action job. The
+// generated JobStatusAction_StatusCode_IobFinished constant misspells it; both are treated as finished.
+const JobStatusAction_StatusCode_JobFinished = "job_finished"
+
+// Constants associated with the JobStatusChange.Field property.
+// The status code of the action job that changed.
+const (
+	JobStatusChange_Field_BastionStatusCode   = "bastion_status_code"
+	JobStatusChange_Field_InventoryStatusCode = "inventory_status_code"
+	JobStatusChange_Field_StatusCode          = "status_code"
+)
+
+// JobStatusChange : A transition of one of the status codes of an action job, as observed while waiting for the job.
+type JobStatusChange struct {
+	// The job as returned by the request that observed the change.
+	Job *Job
+
+	// The status code that changed.
+	Field string
+
+	// The previous value. Empty for the first observation.
+	Previous string
+
+	// The new value.
+	Current string
+
+	// The status message that goes with the new value.
+	Message string
+}
+
+// JobFailedError is returned by WaitForJob and RunActionAndWait when a job failed or was cancelled.
+type JobFailedError struct {
+	// The job ID.
+	JobID string
+
+	// The final status code of the job.
+	Status string
+
+	// The job status message.
+	StatusMessage string
+
+	// The bastion status code and message.
+	BastionStatusCode    string
+	BastionStatusMessage string
+
+	// The inventory status code and message.
+	InventoryStatusCode    string
+	InventoryStatusMessage string
+
+	// The log summary of the job.
+	LogSummary *JobLogSummary
+}
+
+// Error implements the error interface.
+func (e *JobFailedError) Error() string {
+	msg := fmt.Sprintf("job %s finished with status %s", e.JobID, e.Status)
+	var details []string
+	if e.StatusMessage != "" {
+		details = append(details, e.StatusMessage)
+	}
+	if e.BastionStatusCode == JobStatusAction_BastionStatusCode_Error {
+		details = append(details, strings.TrimSpace("bastion: "+e.BastionStatusMessage))
+	}
+	if e.InventoryStatusCode == JobStatusAction_InventoryStatusCode_Error {
+		details = append(details, strings.TrimSpace("inventory: "+e.InventoryStatusMessage))
+	}
+	if len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// WaitForJobOptions : The WaitForJob options.
+type WaitForJobOptions struct {
+	// Job Id. Use GET /jobs API to look up the Job Ids in your IBM Cloud account.
+	JobID *string `json:"job_id" validate:"required,ne="`
+
+	// Called for every change of the job, bastion and inventory status codes, in the order they are observed.
+	OnStatusChange func(change *JobStatusChange) `json:"-"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewWaitForJobOptions : Instantiate WaitForJobOptions
+func (*SchematicsV1) NewWaitForJobOptions(jobID string) *WaitForJobOptions {
+	return &WaitForJobOptions{
+		JobID: core.StringPtr(jobID),
+	}
+}
+
+// SetJobID : Allow user to set JobID
+func (options *WaitForJobOptions) SetJobID(jobID string) *WaitForJobOptions {
+	options.JobID = core.StringPtr(jobID)
+	return options
+}
+
+// SetOnStatusChange : Allow user to set OnStatusChange
+func (options *WaitForJobOptions) SetOnStatusChange(onStatusChange func(change *JobStatusChange)) *WaitForJobOptions {
+	options.OnStatusChange = onStatusChange
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *WaitForJobOptions) SetPollInterval(pollInterval time.Duration) *WaitForJobOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *WaitForJobOptions) SetTimeout(timeout time.Duration) *WaitForJobOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *WaitForJobOptions) SetHeaders(param map[string]string) *WaitForJobOptions {
+	options.Headers = param
+	return options
+}
+
+// WaitForJob : Wait for an action job to finish
+// Poll GetJob until the job is finished, failed or cancelled, reporting status code changes to OnStatusChange. The
+// final job is returned; a *JobFailedError is returned with it when the job did not finish successfully.
+func (schematics *SchematicsV1) WaitForJob(waitForJobOptions *WaitForJobOptions) (result *Job, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(waitForJobOptions, "waitForJobOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(waitForJobOptions, "waitForJobOptions")
+	if err != nil {
+		return
+	}
+
+	observed := map[string]string{}
+	err = pollUntil(waitForJobOptions.PollInterval, waitForJobOptions.Timeout, func() (bool, error) {
+		var pollErr error
+		result, response, pollErr = schematics.GetJob(&GetJobOptions{
+			JobID:   waitForJobOptions.JobID,
+			Headers: waitForJobOptions.Headers,
+		})
+		if pollErr != nil {
+			return false, pollErr
+		}
+		status := jobStatusAction(result)
+		if waitForJobOptions.OnStatusChange != nil {
+			reportJobStatusChanges(result, status, observed, waitForJobOptions.OnStatusChange)
+		}
+		return isJobFinished(status.StatusCode), nil
+	})
+	if err != nil {
+		return
+	}
+
+	status := jobStatusAction(result)
+	if !isJobSuccessful(status.StatusCode) {
+		err = &JobFailedError{
+			JobID:                  *waitForJobOptions.JobID,
+			Status:                 stringValue(status.StatusCode),
+			StatusMessage:          stringValue(status.StatusMessage),
+			BastionStatusCode:      stringValue(status.BastionStatusCode),
+			BastionStatusMessage:   stringValue(status.BastionStatusMessage),
+			InventoryStatusCode:    stringValue(status.InventoryStatusCode),
+			InventoryStatusMessage: stringValue(status.InventoryStatusMessage),
+			LogSummary:             result.LogSummary,
+		}
+	}
+	return
+}
+
+// RunActionAndWaitOptions : The RunActionAndWait options.
+type RunActionAndWaitOptions struct {
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// Schematics job command name. Defaults to CreateJobOptions_CommandName_AnsiblePlaybookRun.
+	CommandName *string `json:"command_name,omitempty"`
+
+	// Schematics job command parameter (playbook-name).
+	CommandParameter *string `json:"command_parameter,omitempty"`
+
+	// Command line options for the command.
+	CommandOptions []string `json:"command_options,omitempty"`
+
+	// Job inputs used by Action.
+	Inputs []VariableData `json:"inputs,omitempty"`
+
+	// Environment variables used by the Job while performing Action.
+	Settings []VariableData `json:"settings,omitempty"`
+
+	// User defined tags, while running the job.
+	Tags []string `json:"tags,omitempty"`
+
+	// Called for every change of the job, bastion and inventory status codes, in the order they are observed.
+	OnStatusChange func(change *JobStatusChange) `json:"-"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRunActionAndWaitOptions : Instantiate RunActionAndWaitOptions
+func (*SchematicsV1) NewRunActionAndWaitOptions(refreshToken string, actionID string) *RunActionAndWaitOptions {
+	return &RunActionAndWaitOptions{
+		RefreshToken: core.StringPtr(refreshToken),
+		ActionID:     core.StringPtr(actionID),
+	}
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *RunActionAndWaitOptions) SetRefreshToken(refreshToken string) *RunActionAndWaitOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *RunActionAndWaitOptions) SetActionID(actionID string) *RunActionAndWaitOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetCommandName : Allow user to set CommandName
+func (options *RunActionAndWaitOptions) SetCommandName(commandName string) *RunActionAndWaitOptions {
+	options.CommandName = core.StringPtr(commandName)
+	return options
+}
+
+// SetCommandParameter : Allow user to set CommandParameter
+func (options *RunActionAndWaitOptions) SetCommandParameter(commandParameter string) *RunActionAndWaitOptions {
+	options.CommandParameter = core.StringPtr(commandParameter)
+	return options
+}
+
+// SetCommandOptions : Allow user to set CommandOptions
+func (options *RunActionAndWaitOptions) SetCommandOptions(commandOptions []string) *RunActionAndWaitOptions {
+	options.CommandOptions = commandOptions
+	return options
+}
+
+// SetInputs : Allow user to set Inputs
+func (options *RunActionAndWaitOptions) SetInputs(inputs []VariableData) *RunActionAndWaitOptions {
+	options.Inputs = inputs
+	return options
+}
+
+// SetSettings : Allow user to set Settings
+func (options *RunActionAndWaitOptions) SetSettings(settings []VariableData) *RunActionAndWaitOptions {
+	options.Settings = settings
+	return options
+}
+
+// SetTags : Allow user to set Tags
+func (options *RunActionAndWaitOptions) SetTags(tags []string) *RunActionAndWaitOptions {
+	options.Tags = tags
+	return options
+}
+
+// SetOnStatusChange : Allow user to set OnStatusChange
+func (options *RunActionAndWaitOptions) SetOnStatusChange(onStatusChange func(change *JobStatusChange)) *RunActionAndWaitOptions {
+	options.OnStatusChange = onStatusChange
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *RunActionAndWaitOptions) SetPollInterval(pollInterval time.Duration) *RunActionAndWaitOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *RunActionAndWaitOptions) SetTimeout(timeout time.Duration) *RunActionAndWaitOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RunActionAndWaitOptions) SetHeaders(param map[string]string) *RunActionAndWaitOptions {
+	options.Headers = param
+	return options
+}
+
+// RunActionAndWait : Run an action job and wait for it to finish
+// Check that the action can run jobs, submit an action job with CreateJob and wait for it with WaitForJob. An
+// *ActionNotRunnableError is returned without submitting a job when the action is disabled or its status is critical,
+// disabled or pending. The final job is returned with its log summary; a *JobFailedError is returned with it when the
+// job failed or was cancelled. When waiting fails before the job could be read, the submitted job is returned so that
+// its ID is not lost.
+func (schematics *SchematicsV1) RunActionAndWait(runActionAndWaitOptions *RunActionAndWaitOptions) (result *Job, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(runActionAndWaitOptions, "runActionAndWaitOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(runActionAndWaitOptions, "runActionAndWaitOptions")
+	if err != nil {
+		return
+	}
+
+	action, response, err := schematics.GetAction(&GetActionOptions{
+		ActionID: runActionAndWaitOptions.ActionID,
+		Headers:  runActionAndWaitOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	err = CheckActionRunnable(action)
+	if err != nil {
+		return
+	}
+
+	commandName := runActionAndWaitOptions.CommandName
+	if commandName == nil {
+		commandName = core.StringPtr(CreateJobOptions_CommandName_AnsiblePlaybookRun)
+	}
+	job, response, err := schematics.CreateJob(&CreateJobOptions{
+		RefreshToken:     runActionAndWaitOptions.RefreshToken,
+		CommandObject:    core.StringPtr(CreateJobOptions_CommandObject_Action),
+		CommandObjectID:  runActionAndWaitOptions.ActionID,
+		CommandName:      commandName,
+		CommandParameter: runActionAndWaitOptions.CommandParameter,
+		CommandOptions:   runActionAndWaitOptions.CommandOptions,
+		Inputs:           runActionAndWaitOptions.Inputs,
+		Settings:         runActionAndWaitOptions.Settings,
+		Tags:             runActionAndWaitOptions.Tags,
+		Headers:          runActionAndWaitOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if job.ID == nil {
+		err = fmt.Errorf("the job of action %s was submitted without job ID", *runActionAndWaitOptions.ActionID)
+		return
+	}
+
+	result, response, err = schematics.WaitForJob(&WaitForJobOptions{
+		JobID:          job.ID,
+		OnStatusChange: runActionAndWaitOptions.OnStatusChange,
+		PollInterval:   runActionAndWaitOptions.PollInterval,
+		Timeout:        runActionAndWaitOptions.Timeout,
+		Headers:        runActionAndWaitOptions.Headers,
+	})
+	if result == nil {
+		result = job
+	}
+	return
+}
+
+// jobStatusAction returns the action status of a job, or an empty status.
+func jobStatusAction(job *Job) *JobStatusAction {
+	if job.Status == nil || job.Status.ActionJobStatus == nil {
+		return &JobStatusAction{}
+	}
+	return job.Status.ActionJobStatus
+}
+
+// reportJobStatusChanges calls onStatusChange for every status code of the job that differs from the observed value,
+// and records the new values.
+func reportJobStatusChanges(job *Job, status *JobStatusAction, observed map[string]string, onStatusChange func(change *JobStatusChange)) {
+	codes := []struct {
+		field   string
+		code    *string
+		message *string
+	}{
+		{JobStatusChange_Field_BastionStatusCode, status.BastionStatusCode, status.BastionStatusMessage},
+		{JobStatusChange_Field_InventoryStatusCode, status.InventoryStatusCode, status.InventoryStatusMessage},
+		{JobStatusChange_Field_StatusCode, status.StatusCode, status.StatusMessage},
+	}
+	for _, code := range codes {
+		current := stringValue(code.code)
+		if current == "" || current == observed[code.field] {
+			continue
+		}
+		onStatusChange(&JobStatusChange{
+			Job:      job,
+			Field:    code.field,
+			Previous: observed[code.field],
+			Current:  current,
+			Message:  stringValue(code.message),
+		})
+		observed[code.field] = current
+	}
+}
+
+// isJobFinished reports whether a job with the given status code is finished, failed or cancelled.
+func isJobFinished(statusCode *string) bool {
+	return isJobSuccessful(statusCode) ||
+		isActivityStatus(statusCode, JobStatusAction_StatusCode_JobFailed) ||
+		isActivityStatus(statusCode, JobStatusAction_StatusCode_JobCancelled)
+}
+
+// isJobSuccessful reports whether a job with the given status code finished successfully.
+func isJobSuccessful(statusCode *string) bool {
+	return isActivityStatus(statusCode, JobStatusAction_StatusCode_JobFinished) ||
+		isActivityStatus(statusCode, JobStatusAction_StatusCode_IobFinished)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
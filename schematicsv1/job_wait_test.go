@@ -0,0 +1,147 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Action job waiter`, func() {
+	var testServer *httptest.Server
+	var statuses []string
+	var polls int
+
+	Context(`Using mock server endpoint`, func() {
+		BeforeEach(func() {
+			polls = 0
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "POST" && path == "/v2/jobs":
+					Expect(req.Header.Get("refresh_token")).To(Equal("testString"))
+					body, _ := ioutil.ReadAll(req.Body)
+					var options schematicsv1.CreateJobOptions
+					Expect(json.Unmarshal(body, &options)).To(Succeed())
+					Expect(*options.CommandObject).To(Equal("action"))
+					Expect(*options.CommandObjectID).To(Equal("act-1"))
+					Expect(*options.CommandName).To(Equal("ansible_playbook_run"))
+					res.WriteHeader(201)
+					fmt.Fprintf(res, "%s", `{"id": "job-1"}`)
+				case req.Method == "GET" && path == "/v2/actions/act-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "act-1", "user_state": {"state": "live"}, "state": {"status_code": "normal"}}`)
+				case req.Method == "GET" && path == "/v2/jobs/job-1":
+					status := statuses[polls]
+					if polls < len(statuses)-1 {
+						polls++
+					}
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"id": "job-1", "status": {"action_job_status": %s}, "log_summary": {"job_id": "job-1"}}`, status)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke RunActionAndWait successfully`, func() {
+			statuses = []string{
+				`{"status_code": "job_pending", "bastion_status_code": "none", "inventory_status_code": "none"}`,
+				`{"status_code": "job_in_progress", "bastion_status_code": "processing", "inventory_status_code": "none"}`,
+				`{"status_code": "job_in_progress", "bastion_status_code": "ready", "inventory_status_code": "ready", "inventory_status_message": "2 hosts"}`,
+				`{"status_code": "job_finished", "bastion_status_code": "ready", "inventory_status_code": "ready"}`,
+			}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.RunActionAndWait(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			var changes []string
+			options := schematicsService.NewRunActionAndWaitOptions("testString", "act-1").
+				SetPollInterval(time.Millisecond).
+				SetOnStatusChange(func(change *schematicsv1.JobStatusChange) {
+					changes = append(changes, fmt.Sprintf("%s %s->%s %s", change.Field, change.Previous, change.Current, change.Message))
+				})
+			result, response, operationErr = schematicsService.RunActionAndWait(options)
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(*result.ID).To(Equal("job-1"))
+			Expect(*result.LogSummary.JobID).To(Equal("job-1"))
+			Expect(changes).To(Equal([]string{
+				"bastion_status_code ->none ",
+				"inventory_status_code ->none ",
+				"status_code ->job_pending ",
+				"bastion_status_code none->processing ",
+				"status_code job_pending->job_in_progress ",
+				"bastion_status_code processing->ready ",
+				"inventory_status_code none->ready 2 hosts",
+				"status_code job_in_progress->job_finished ",
+			}))
+		})
+		It(`Invoke WaitForJob with a failed job`, func() {
+			statuses = []string{
+				`{"status_code": "job_failed", "status_message": "playbook failed", "bastion_status_code": "error", "bastion_status_message": "unreachable"}`,
+			}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			result, _, operationErr := schematicsService.WaitForJob(schematicsService.NewWaitForJobOptions("job-1").SetPollInterval(time.Millisecond))
+			Expect(result).ToNot(BeNil())
+			Expect(operationErr).ToNot(BeNil())
+			failed, ok := operationErr.(*schematicsv1.JobFailedError)
+			Expect(ok).To(BeTrue())
+			Expect(failed.Status).To(Equal("job_failed"))
+			Expect(*failed.LogSummary.JobID).To(Equal("job-1"))
+			Expect(failed.Error()).To(Equal("job job-1 finished with status job_failed: playbook failed; bastion: unreachable"))
+		})
+		It(`Invoke WaitForJob with a job that does not finish in time`, func() {
+			statuses = []string{`{"status_code": "job_in_progress"}`}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			_, _, operationErr := schematicsService.WaitForJob(schematicsService.NewWaitForJobOptions("job-1").
+				SetPollInterval(time.Millisecond).SetTimeout(5 * time.Millisecond))
+			Expect(operationErr).To(Equal(schematicsv1.ErrWaitTimeout))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,388 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+	"time"
+)
+
+// PlaybookRecap : The play recap of an Ansible playbook job.
+type PlaybookRecap struct {
+	// The hosts of the play.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// The number of tasks that were ok.
+	Ok int64 `json:"ok"`
+
+	// The number of tasks that changed, or would change in a check job.
+	Changed int64 `json:"changed"`
+
+	// The number of tasks that failed.
+	Failed int64 `json:"failed"`
+
+	// The number of tasks that were skipped.
+	Skipped int64 `json:"skipped"`
+
+	// The number of unreachable hosts.
+	Unreachable int64 `json:"unreachable"`
+
+	// The hosts that a task changed, or would change in a check job.
+	ChangedHosts []string `json:"changed_hosts,omitempty"`
+
+	// The hosts on which a task failed.
+	FailedHosts []string `json:"failed_hosts,omitempty"`
+
+	// The hosts that could not be reached.
+	UnreachableHosts []string `json:"unreachable_hosts,omitempty"`
+}
+
+// ParsePlaybookRecap returns the recap of an action job from its log summary and the play recap of its Ansible log, or
+// nil if neither has a recap. The log, which may be nil, gives the hosts that changed, failed or could not be reached.
+func ParsePlaybookRecap(summary *JobLogSummary, log *AnsibleLog) *PlaybookRecap {
+	var recap *PlaybookRecap
+	if summary != nil && summary.ActionJob != nil && summary.ActionJob.Recap != nil {
+		count := func(value *float64) int64 {
+			if value == nil {
+				return 0
+			}
+			return int64(*value)
+		}
+		summaryRecap := summary.ActionJob.Recap
+		recap = &PlaybookRecap{
+			Hosts:       copyStrings(summaryRecap.Hosts),
+			Ok:          count(summaryRecap.Ok),
+			Changed:     count(summaryRecap.Changed),
+			Failed:      count(summaryRecap.Failed),
+			Skipped:     count(summaryRecap.Skipped),
+			Unreachable: count(summaryRecap.Unreachable),
+		}
+	}
+	if log == nil || len(log.Stats) == 0 {
+		return recap
+	}
+
+	if recap == nil {
+		recap = &PlaybookRecap{}
+		for _, stats := range log.Stats {
+			recap.Ok += stats.Ok
+			recap.Changed += stats.Changed
+			recap.Failed += stats.Failed
+			recap.Skipped += stats.Skipped
+			if stats.Unreachable > 0 {
+				recap.Unreachable++
+			}
+		}
+	}
+	for _, host := range sortedKeys(log.Stats) {
+		stats := log.Stats[host]
+		if !containsString(recap.Hosts, host) {
+			recap.Hosts = append(recap.Hosts, host)
+		}
+		if stats.Changed > 0 {
+			recap.ChangedHosts = append(recap.ChangedHosts, host)
+		}
+		if stats.Failed > 0 {
+			recap.FailedHosts = append(recap.FailedHosts, host)
+		}
+		if stats.Unreachable > 0 {
+			recap.UnreachableHosts = append(recap.UnreachableHosts, host)
+		}
+	}
+	return recap
+}
+
+// PlaybookChangePolicy decides whether the changes predicted by a check job may be applied. It returns nil to approve
+// them, or an error that explains why they are rejected.
+type PlaybookChangePolicy func(recap *PlaybookRecap) error
+
+// ApproveCleanCheck approves the changes of a check job that failed on no host and reached every host. A recap
+// without hosts falls back to its task counts. It is the default policy of CheckAndRunPlaybook.
+func ApproveCleanCheck(recap *PlaybookRecap) error {
+	var problems []string
+	if len(recap.FailedHosts) > 0 {
+		problems = append(problems, "failed on hosts "+strings.Join(recap.FailedHosts, ", "))
+	}
+	if len(recap.UnreachableHosts) > 0 {
+		problems = append(problems, "could not reach hosts "+strings.Join(recap.UnreachableHosts, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("the check %s", strings.Join(problems, " and "))
+	}
+	if recap.Failed > 0 || recap.Unreachable > 0 {
+		return fmt.Errorf("the check reported %d failed tasks and %d unreachable hosts", recap.Failed, recap.Unreachable)
+	}
+	return nil
+}
+
+// ApproveMaxChanged returns a policy that approves clean checks with at most maxChanged changed tasks.
+func ApproveMaxChanged(maxChanged int64) PlaybookChangePolicy {
+	return func(recap *PlaybookRecap) error {
+		if err := ApproveCleanCheck(recap); err != nil {
+			return err
+		}
+		if recap.Changed > maxChanged {
+			return fmt.Errorf("the check predicts %d changed tasks, more than the %d allowed", recap.Changed, maxChanged)
+		}
+		return nil
+	}
+}
+
+// PlaybookChangesRejectedError is returned by CheckAndRunPlaybook when the policy rejected the changes predicted by
+// the check job. The playbook was not run.
+type PlaybookChangesRejectedError struct {
+	// The ID of the check job.
+	CheckJobID string
+
+	// The recap of the check job.
+	Recap *PlaybookRecap
+
+	// The reason given by the policy.
+	Reason error
+}
+
+// Error implements the error interface.
+func (e *PlaybookChangesRejectedError) Error() string {
+	return fmt.Sprintf("changes predicted by check job %s were rejected: %s", e.CheckJobID, e.Reason.Error())
+}
+
+// CheckAndRunPlaybookResult : The check and run jobs of a CheckAndRunPlaybook workflow.
+type CheckAndRunPlaybookResult struct {
+	// The ID of the check job.
+	CheckJobID *string `json:"check_job_id,omitempty"`
+
+	// The recap of the check job.
+	CheckRecap *PlaybookRecap `json:"check_recap,omitempty"`
+
+	// The ID of the run job. Empty when the playbook was not run.
+	RunJobID *string `json:"run_job_id,omitempty"`
+
+	// The recap of the run job.
+	RunRecap *PlaybookRecap `json:"run_recap,omitempty"`
+}
+
+// CheckAndRunPlaybookOptions : The CheckAndRunPlaybook options.
+type CheckAndRunPlaybookOptions struct {
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// Schematics job command parameter (playbook-name).
+	CommandParameter *string `json:"command_parameter,omitempty"`
+
+	// Command line options for the command.
+	CommandOptions []string `json:"command_options,omitempty"`
+
+	// Job inputs used by Action.
+	Inputs []VariableData `json:"inputs,omitempty"`
+
+	// Environment variables used by the Job while performing Action.
+	Settings []VariableData `json:"settings,omitempty"`
+
+	// User defined tags, while running the job.
+	Tags []string `json:"tags,omitempty"`
+
+	// Decides whether the changes predicted by the check job may be applied. Defaults to ApproveCleanCheck.
+	Policy PlaybookChangePolicy `json:"-"`
+
+	// Called for every change of the job, bastion and inventory status codes of both jobs.
+	OnStatusChange func(change *JobStatusChange) `json:"-"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting for each job. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCheckAndRunPlaybookOptions : Instantiate CheckAndRunPlaybookOptions
+func (*SchematicsV1) NewCheckAndRunPlaybookOptions(refreshToken string, actionID string) *CheckAndRunPlaybookOptions {
+	return &CheckAndRunPlaybookOptions{
+		RefreshToken: core.StringPtr(refreshToken),
+		ActionID:     core.StringPtr(actionID),
+	}
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *CheckAndRunPlaybookOptions) SetRefreshToken(refreshToken string) *CheckAndRunPlaybookOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *CheckAndRunPlaybookOptions) SetActionID(actionID string) *CheckAndRunPlaybookOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetCommandParameter : Allow user to set CommandParameter
+func (options *CheckAndRunPlaybookOptions) SetCommandParameter(commandParameter string) *CheckAndRunPlaybookOptions {
+	options.CommandParameter = core.StringPtr(commandParameter)
+	return options
+}
+
+// SetCommandOptions : Allow user to set CommandOptions
+func (options *CheckAndRunPlaybookOptions) SetCommandOptions(commandOptions []string) *CheckAndRunPlaybookOptions {
+	options.CommandOptions = commandOptions
+	return options
+}
+
+// SetInputs : Allow user to set Inputs
+func (options *CheckAndRunPlaybookOptions) SetInputs(inputs []VariableData) *CheckAndRunPlaybookOptions {
+	options.Inputs = inputs
+	return options
+}
+
+// SetSettings : Allow user to set Settings
+func (options *CheckAndRunPlaybookOptions) SetSettings(settings []VariableData) *CheckAndRunPlaybookOptions {
+	options.Settings = settings
+	return options
+}
+
+// SetTags : Allow user to set Tags
+func (options *CheckAndRunPlaybookOptions) SetTags(tags []string) *CheckAndRunPlaybookOptions {
+	options.Tags = tags
+	return options
+}
+
+// SetPolicy : Allow user to set Policy
+func (options *CheckAndRunPlaybookOptions) SetPolicy(policy PlaybookChangePolicy) *CheckAndRunPlaybookOptions {
+	options.Policy = policy
+	return options
+}
+
+// SetOnStatusChange : Allow user to set OnStatusChange
+func (options *CheckAndRunPlaybookOptions) SetOnStatusChange(onStatusChange func(change *JobStatusChange)) *CheckAndRunPlaybookOptions {
+	options.OnStatusChange = onStatusChange
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *CheckAndRunPlaybookOptions) SetPollInterval(pollInterval time.Duration) *CheckAndRunPlaybookOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *CheckAndRunPlaybookOptions) SetTimeout(timeout time.Duration) *CheckAndRunPlaybookOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CheckAndRunPlaybookOptions) SetHeaders(param map[string]string) *CheckAndRunPlaybookOptions {
+	options.Headers = param
+	return options
+}
+
+// CheckAndRunPlaybook : Check an action playbook, then run it if the predicted changes are approved
+// Run an 'ansible_playbook_check' job for the action and pass its recap, with the hosts read from the job log, to the
+// policy. The 'ansible_playbook_run' job is only submitted when the policy approves. The result holds the IDs and
+// recaps of the jobs that ran, also when an error is returned: a *JobFailedError when a job failed, and a
+// *PlaybookChangesRejectedError when the policy rejected the changes.
+func (schematics *SchematicsV1) CheckAndRunPlaybook(checkAndRunPlaybookOptions *CheckAndRunPlaybookOptions) (result *CheckAndRunPlaybookResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(checkAndRunPlaybookOptions, "checkAndRunPlaybookOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(checkAndRunPlaybookOptions, "checkAndRunPlaybookOptions")
+	if err != nil {
+		return
+	}
+	policy := checkAndRunPlaybookOptions.Policy
+	if policy == nil {
+		policy = ApproveCleanCheck
+	}
+
+	runOptions := func(commandName string) *RunActionAndWaitOptions {
+		return &RunActionAndWaitOptions{
+			RefreshToken:     checkAndRunPlaybookOptions.RefreshToken,
+			ActionID:         checkAndRunPlaybookOptions.ActionID,
+			CommandName:      core.StringPtr(commandName),
+			CommandParameter: checkAndRunPlaybookOptions.CommandParameter,
+			CommandOptions:   checkAndRunPlaybookOptions.CommandOptions,
+			Inputs:           checkAndRunPlaybookOptions.Inputs,
+			Settings:         checkAndRunPlaybookOptions.Settings,
+			Tags:             checkAndRunPlaybookOptions.Tags,
+			OnStatusChange:   checkAndRunPlaybookOptions.OnStatusChange,
+			PollInterval:     checkAndRunPlaybookOptions.PollInterval,
+			Timeout:          checkAndRunPlaybookOptions.Timeout,
+			Headers:          checkAndRunPlaybookOptions.Headers,
+		}
+	}
+
+	result = &CheckAndRunPlaybookResult{}
+	checkJob, response, err := schematics.RunActionAndWait(runOptions(CreateJobOptions_CommandName_AnsiblePlaybookCheck))
+	if checkJob == nil {
+		return
+	}
+	result.CheckJobID = checkJob.ID
+	result.CheckRecap = ParsePlaybookRecap(checkJob.LogSummary, nil)
+	if err != nil {
+		return
+	}
+	result.CheckRecap, response, err = schematics.playbookJobRecap(checkJob, checkAndRunPlaybookOptions.Headers)
+	if err != nil {
+		return
+	}
+	if result.CheckRecap == nil {
+		err = fmt.Errorf("check job %s has no play recap", *checkJob.ID)
+		return
+	}
+	if reason := policy(result.CheckRecap); reason != nil {
+		err = &PlaybookChangesRejectedError{
+			CheckJobID: *checkJob.ID,
+			Recap:      result.CheckRecap,
+			Reason:     reason,
+		}
+		return
+	}
+
+	runJob, response, err := schematics.RunActionAndWait(runOptions(CreateJobOptions_CommandName_AnsiblePlaybookRun))
+	if runJob == nil {
+		return
+	}
+	result.RunJobID = runJob.ID
+	result.RunRecap = ParsePlaybookRecap(runJob.LogSummary, nil)
+	if err != nil {
+		return
+	}
+	result.RunRecap, response, err = schematics.playbookJobRecap(runJob, checkAndRunPlaybookOptions.Headers)
+	return
+}
+
+// playbookJobRecap returns the recap of a finished action job, with the hosts read from its Ansible log. The recap of
+// the log summary is returned with the error when the log can not be read.
+func (schematics *SchematicsV1) playbookJobRecap(job *Job, headers map[string]string) (recap *PlaybookRecap, response *core.DetailedResponse, err error) {
+	log, response, err := schematics.GetJobAnsibleLog(&ListJobLogsOptions{
+		JobID:   job.ID,
+		Headers: headers,
+	})
+	if err != nil {
+		recap = ParsePlaybookRecap(job.LogSummary, nil)
+		return
+	}
+	recap = ParsePlaybookRecap(job.LogSummary, log)
+	return
+}
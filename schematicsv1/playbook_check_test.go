@@ -0,0 +1,201 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Playbook check and run`, func() {
+	var testServer *httptest.Server
+	var submitted []string
+	var checkRecap string
+	var checkStats string
+	jobLog := func(jobID string, stats string) string {
+		return fmt.Sprintf(`{"job_id": "%s", "format": "json", "details": "%s"}`, jobID,
+			base64.StdEncoding.EncodeToString([]byte(`{"plays": [], "stats": `+stats+`}`)))
+	}
+
+	Context(`Using mock server endpoint`, func() {
+		BeforeEach(func() {
+			submitted = nil
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "POST" && path == "/v2/jobs":
+					body, _ := ioutil.ReadAll(req.Body)
+					var options schematicsv1.CreateJobOptions
+					Expect(json.Unmarshal(body, &options)).To(Succeed())
+					Expect(*options.CommandParameter).To(Equal("site.yml"))
+					submitted = append(submitted, *options.CommandName)
+					res.WriteHeader(201)
+					if *options.CommandObjectID == "act-lost" {
+						fmt.Fprintf(res, "%s", `{"id": "check-lost"}`)
+					} else if *options.CommandName == "ansible_playbook_check" {
+						fmt.Fprintf(res, "%s", `{"id": "check-1"}`)
+					} else {
+						fmt.Fprintf(res, "%s", `{"id": "run-1"}`)
+					}
+				case req.Method == "GET" && (path == "/v2/actions/act-1" || path == "/v2/actions/act-lost"):
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "act-1", "user_state": {"state": "live"}, "state": {"status_code": "normal"}}`)
+				case req.Method == "GET" && path == "/v2/jobs/check-1/logs":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", jobLog("check-1", checkStats))
+				case req.Method == "GET" && path == "/v2/jobs/run-1/logs":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", jobLog("run-1", `{"h1": {"ok": 4, "changed": 2}, "h2": {"ok": 4, "changed": 1}}`))
+				case req.Method == "GET" && path == "/v2/jobs/check-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"id": "check-1", "status": {"action_job_status": {"status_code": "job_finished"}}, "log_summary": {"action_job": {"recap": %s}}}`, checkRecap)
+				case req.Method == "GET" && path == "/v2/jobs/run-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "run-1", "status": {"action_job_status": {"status_code": "job_finished"}}, "log_summary": {"action_job": {"recap": {"hosts": ["h1", "h2"], "ok": 8, "changed": 3}}}}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke CheckAndRunPlaybook successfully`, func() {
+			checkRecap = `{"hosts": ["h1", "h2"], "ok": 5, "changed": 3, "skipped": 1}`
+			checkStats = `{"h1": {"ok": 2, "changed": 3}, "h2": {"ok": 3, "skipped": 1}}`
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.CheckAndRunPlaybook(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			options := schematicsService.NewCheckAndRunPlaybookOptions("testString", "act-1").
+				SetCommandParameter("site.yml").
+				SetPolicy(schematicsv1.ApproveMaxChanged(5)).
+				SetPollInterval(time.Millisecond)
+			result, response, operationErr = schematicsService.CheckAndRunPlaybook(options)
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(submitted).To(Equal([]string{"ansible_playbook_check", "ansible_playbook_run"}))
+			Expect(*result.CheckJobID).To(Equal("check-1"))
+			Expect(*result.CheckRecap).To(Equal(schematicsv1.PlaybookRecap{Hosts: []string{"h1", "h2"}, Ok: 5, Changed: 3, Skipped: 1, ChangedHosts: []string{"h1"}}))
+			Expect(*result.RunJobID).To(Equal("run-1"))
+			Expect(result.RunRecap.Ok).To(Equal(int64(8)))
+			Expect(result.RunRecap.ChangedHosts).To(Equal([]string{"h1", "h2"}))
+		})
+		It(`Invoke CheckAndRunPlaybook with rejected changes`, func() {
+			checkRecap = `{"hosts": ["h1", "h2"], "ok": 5, "changed": 3, "unreachable": 1}`
+			checkStats = `{"h1": {"ok": 5, "changed": 3}, "h2": {"unreachable": 1}}`
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			options := schematicsService.NewCheckAndRunPlaybookOptions("testString", "act-1").
+				SetCommandParameter("site.yml").
+				SetPollInterval(time.Millisecond)
+			result, _, operationErr := schematicsService.CheckAndRunPlaybook(options)
+			Expect(operationErr).ToNot(BeNil())
+			rejected, ok := operationErr.(*schematicsv1.PlaybookChangesRejectedError)
+			Expect(ok).To(BeTrue())
+			Expect(rejected.CheckJobID).To(Equal("check-1"))
+			Expect(operationErr.Error()).To(Equal("changes predicted by check job check-1 were rejected: the check could not reach hosts h2"))
+			Expect(submitted).To(Equal([]string{"ansible_playbook_check"}))
+			Expect(*result.CheckJobID).To(Equal("check-1"))
+			Expect(result.RunJobID).To(BeNil())
+			Expect(result.CheckRecap.UnreachableHosts).To(Equal([]string{"h2"}))
+		})
+		It(`Invoke CheckAndRunPlaybook with a check job that can not be read`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			options := schematicsService.NewCheckAndRunPlaybookOptions("testString", "act-lost").
+				SetCommandParameter("site.yml").
+				SetPollInterval(time.Millisecond)
+			result, _, operationErr := schematicsService.CheckAndRunPlaybook(options)
+			Expect(operationErr).ToNot(BeNil())
+			Expect(submitted).To(Equal([]string{"ansible_playbook_check"}))
+			Expect(*result.CheckJobID).To(Equal("check-lost"))
+			Expect(result.CheckRecap).To(BeNil())
+			Expect(result.RunJobID).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+
+	Describe(`ApproveCleanCheck(recap *PlaybookRecap)`, func() {
+		It(`Reject checks that failed on or could not reach hosts`, func() {
+			Expect(schematicsv1.ApproveCleanCheck(&schematicsv1.PlaybookRecap{Changed: 2, ChangedHosts: []string{"h1"}})).To(BeNil())
+			Expect(schematicsv1.ApproveCleanCheck(&schematicsv1.PlaybookRecap{Failed: 2, FailedHosts: []string{"h1", "h2"}, Unreachable: 1, UnreachableHosts: []string{"h3"}})).
+				To(MatchError("the check failed on hosts h1, h2 and could not reach hosts h3"))
+			Expect(schematicsv1.ApproveCleanCheck(&schematicsv1.PlaybookRecap{Failed: 1})).
+				To(MatchError("the check reported 1 failed tasks and 0 unreachable hosts"))
+		})
+	})
+	Describe(`ApproveMaxChanged(maxChanged int64)`, func() {
+		It(`Reject checks with too many changes`, func() {
+			policy := schematicsv1.ApproveMaxChanged(2)
+			Expect(policy(&schematicsv1.PlaybookRecap{Changed: 2})).To(BeNil())
+			Expect(policy(&schematicsv1.PlaybookRecap{Changed: 3})).To(MatchError("the check predicts 3 changed tasks, more than the 2 allowed"))
+			Expect(policy(&schematicsv1.PlaybookRecap{Failed: 1})).ToNot(BeNil())
+		})
+	})
+
+	Describe(`ParsePlaybookRecap(summary *JobLogSummary, log *AnsibleLog)`, func() {
+		It(`Return nil without recap`, func() {
+			Expect(schematicsv1.ParsePlaybookRecap(nil, nil)).To(BeNil())
+			Expect(schematicsv1.ParsePlaybookRecap(&schematicsv1.JobLogSummary{}, &schematicsv1.AnsibleLog{})).To(BeNil())
+		})
+		It(`Read the recap from the log without summary`, func() {
+			log := &schematicsv1.AnsibleLog{Stats: map[string]schematicsv1.AnsibleHostStats{
+				"h2": {Ok: 1, Failed: 1},
+				"h1": {Ok: 2, Changed: 1},
+				"h3": {Unreachable: 1},
+			}}
+			Expect(*schematicsv1.ParsePlaybookRecap(nil, log)).To(Equal(schematicsv1.PlaybookRecap{
+				Hosts:            []string{"h1", "h2", "h3"},
+				Ok:               3,
+				Changed:          1,
+				Failed:           1,
+				Unreachable:      1,
+				ChangedHosts:     []string{"h1"},
+				FailedHosts:      []string{"h2"},
+				UnreachableHosts: []string{"h3"},
+			}))
+		})
+	})
+})
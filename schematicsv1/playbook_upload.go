@@ -0,0 +1,336 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/go-openapi/strfmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// playbookSupportDirectories are the directories of a playbook project that hold roles, variables and collections
+// rather than playbooks.
+var playbookSupportDirectories = []string{"collections", "group_vars", "host_vars", "library", "roles", "vars"}
+
+// playbookSupportFiles are the YAML files of a playbook project that are not playbooks.
+var playbookSupportFiles = []string{"galaxy.yml", "requirements.yml", "requirements.yaml"}
+
+// PlaybookPackage : A tar archive of a local playbook directory.
+type PlaybookPackage struct {
+	// The tar archive.
+	Archive []byte
+
+	// The files in the archive, relative to the directory and slash separated.
+	Files []string
+
+	// The playbooks found in the directory, relative to the directory and slash separated.
+	Playbooks []string
+}
+
+// PackagePlaybookDirectory packages a local playbook directory, including its roles, group_vars and requirements.yml,
+// into a tar archive. Hidden files and directories such as .git are left out, as are files that are not regular files.
+// A YAML file outside the support directories is a playbook when it is a list of plays, that is of maps with 'hosts'
+// or 'import_playbook'.
+func PackagePlaybookDirectory(directory string) (*PlaybookPackage, error) {
+	info, err := os.Stat(directory)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", directory)
+	}
+
+	result := &PlaybookPackage{}
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	err = filepath.Walk(directory, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(directory, file)
+		if err != nil {
+			return err
+		}
+		if relative == "." {
+			return nil
+		}
+		name := filepath.ToSlash(relative)
+		if strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return writer.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: 0755, ModTime: info.ModTime()})
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: int64(info.Mode().Perm()), Size: int64(len(content)), ModTime: info.ModTime()}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+		result.Files = append(result.Files, name)
+		if isPlaybookCandidate(name) && isPlaybook(content) {
+			result.Playbooks = append(result.Playbooks, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if len(result.Playbooks) == 0 {
+		return nil, fmt.Errorf("no playbook found in %s", directory)
+	}
+	result.Archive = archive.Bytes()
+	return result, nil
+}
+
+// isPlaybookCandidate reports whether the file at name can be a playbook.
+func isPlaybookCandidate(name string) bool {
+	extension := path.Ext(name)
+	if extension != ".yml" && extension != ".yaml" {
+		return false
+	}
+	if containsString(playbookSupportFiles, path.Base(name)) {
+		return false
+	}
+	for _, directory := range strings.Split(path.Dir(name), "/") {
+		if containsString(playbookSupportDirectories, directory) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPlaybook reports whether content is a YAML list of plays.
+func isPlaybook(content []byte) bool {
+	var plays []map[string]interface{}
+	if err := yaml.Unmarshal(content, &plays); err != nil || len(plays) == 0 {
+		return false
+	}
+	for _, play := range plays {
+		_, hasHosts := play["hosts"]
+		_, imports := play["import_playbook"]
+		_, importsBuiltin := play["ansible.builtin.import_playbook"]
+		if !hasHosts && !imports && !importsBuiltin {
+			return false
+		}
+	}
+	return true
+}
+
+// ActionScanFailedError is returned by UploadPlaybookDirectory when the action left the pending state with a status
+// other than normal.
+type ActionScanFailedError struct {
+	// The action ID.
+	ActionID string
+
+	// The status code of the action.
+	Status string
+
+	// The status message of the action.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ActionScanFailedError) Error() string {
+	msg := fmt.Sprintf("scan of action %s finished with status %s", e.ActionID, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// PlaybookUploadResult : The result of UploadPlaybookDirectory.
+type PlaybookUploadResult struct {
+	// The upload response.
+	Upload *TemplateRepoTarUploadResponse `json:"upload,omitempty"`
+
+	// The playbooks found in the local directory.
+	LocalPlaybooks []string `json:"local_playbooks,omitempty"`
+
+	// The playbook names discovered by the server scan.
+	PlaybookNames []string `json:"playbook_names,omitempty"`
+
+	// The action after the scan.
+	Action *Action `json:"action,omitempty"`
+}
+
+// UploadPlaybookDirectoryOptions : The UploadPlaybookDirectory options.
+type UploadPlaybookDirectoryOptions struct {
+	// Action Id.  Use GET /actions API to look up the Action Ids in your IBM Cloud account.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// The local playbook directory.
+	Directory *string `json:"directory" validate:"required,ne="`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewUploadPlaybookDirectoryOptions : Instantiate UploadPlaybookDirectoryOptions
+func (*SchematicsV1) NewUploadPlaybookDirectoryOptions(actionID string, directory string) *UploadPlaybookDirectoryOptions {
+	return &UploadPlaybookDirectoryOptions{
+		ActionID:  core.StringPtr(actionID),
+		Directory: core.StringPtr(directory),
+	}
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *UploadPlaybookDirectoryOptions) SetActionID(actionID string) *UploadPlaybookDirectoryOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetDirectory : Allow user to set Directory
+func (options *UploadPlaybookDirectoryOptions) SetDirectory(directory string) *UploadPlaybookDirectoryOptions {
+	options.Directory = core.StringPtr(directory)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *UploadPlaybookDirectoryOptions) SetPollInterval(pollInterval time.Duration) *UploadPlaybookDirectoryOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *UploadPlaybookDirectoryOptions) SetTimeout(timeout time.Duration) *UploadPlaybookDirectoryOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *UploadPlaybookDirectoryOptions) SetHeaders(param map[string]string) *UploadPlaybookDirectoryOptions {
+	options.Headers = param
+	return options
+}
+
+// UploadPlaybookDirectory : Upload a local playbook directory to an action
+// Package the directory with PackagePlaybookDirectory, upload it with UploadTemplateTarAction and poll GetAction until
+// the scan of the upload finished: the action state was seen pending and left it, or the update time of the action
+// moved past the one read before the upload. Only update times of the service are compared, so the local clock does
+// not matter. A state that predates the upload is never taken as the result of the scan. The result holds the
+// playbook names discovered by the server; it is returned with an *ActionScanFailedError when the scan did not end in
+// the normal state.
+func (schematics *SchematicsV1) UploadPlaybookDirectory(uploadPlaybookDirectoryOptions *UploadPlaybookDirectoryOptions) (result *PlaybookUploadResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(uploadPlaybookDirectoryOptions, "uploadPlaybookDirectoryOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(uploadPlaybookDirectoryOptions, "uploadPlaybookDirectoryOptions")
+	if err != nil {
+		return
+	}
+
+	playbookPackage, err := PackagePlaybookDirectory(*uploadPlaybookDirectoryOptions.Directory)
+	if err != nil {
+		return
+	}
+	before, response, err := schematics.GetAction(&GetActionOptions{
+		ActionID: uploadPlaybookDirectoryOptions.ActionID,
+		Headers:  uploadPlaybookDirectoryOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	upload, response, err := schematics.UploadTemplateTarAction(&UploadTemplateTarActionOptions{
+		ActionID:        uploadPlaybookDirectoryOptions.ActionID,
+		File:            ioutil.NopCloser(bytes.NewReader(playbookPackage.Archive)),
+		FileContentType: core.StringPtr("application/x-tar"),
+		Headers:         uploadPlaybookDirectoryOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	var action *Action
+	seenPending := false
+	err = pollUntil(uploadPlaybookDirectoryOptions.PollInterval, uploadPlaybookDirectoryOptions.Timeout, func() (bool, error) {
+		var pollErr error
+		action, response, pollErr = schematics.GetAction(&GetActionOptions{
+			ActionID: uploadPlaybookDirectoryOptions.ActionID,
+			Headers:  uploadPlaybookDirectoryOptions.Headers,
+		})
+		if pollErr != nil {
+			return false, pollErr
+		}
+		if action.State == nil || action.State.StatusCode == nil {
+			return false, nil
+		}
+		if *action.State.StatusCode == ActionState_StatusCode_Pending {
+			seenPending = true
+			return false, nil
+		}
+		return seenPending || updatedSince(action.UpdatedAt, before.UpdatedAt), nil
+	})
+	if err != nil {
+		return
+	}
+
+	result = &PlaybookUploadResult{
+		Upload:         upload,
+		LocalPlaybooks: playbookPackage.Playbooks,
+		PlaybookNames:  action.PlaybookNames,
+		Action:         action,
+	}
+	if *action.State.StatusCode != ActionState_StatusCode_Normal {
+		err = &ActionScanFailedError{
+			ActionID: *uploadPlaybookDirectoryOptions.ActionID,
+			Status:   *action.State.StatusCode,
+			Message:  stringValue(action.State.StatusMessage),
+		}
+	}
+	return
+}
+
+// updatedSince reports whether an update time of the service is later than a previous one. Any update time is later
+// than none.
+func updatedSince(updatedAt *strfmt.DateTime, previous *strfmt.DateTime) bool {
+	if updatedAt == nil {
+		return false
+	}
+	return previous == nil || time.Time(*updatedAt).After(time.Time(*previous))
+}
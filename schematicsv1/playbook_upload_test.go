@@ -0,0 +1,193 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"archive/tar"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe(`Playbook directory upload`, func() {
+	var directory string
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "playbooks")
+		Expect(err).To(BeNil())
+		files := map[string]string{
+			"site.yml":                    "- hosts: web\n  roles: [web]\n",
+			"db.yaml":                     "- import_playbook: site.yml\n",
+			"vars.yml":                    "port: 80\n",
+			"requirements.yml":            "- src: geerlingguy.nginx\n",
+			"group_vars/all.yml":          "port: 80\n",
+			"roles/web/tasks/main.yml":    "- name: install\n  package: {name: nginx}\n",
+			"roles/web/tests/test.yml":    "- hosts: localhost\n",
+			"playbooks/deploy.yml":        "- hosts: all\n  tasks: []\n",
+			".git/config":                 "[core]\n",
+			"playbooks/.hidden-draft.yml": "- hosts: all\n",
+		}
+		for name, content := range files {
+			file := filepath.Join(directory, filepath.FromSlash(name))
+			Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(file, []byte(content), 0644)).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	Describe(`PackagePlaybookDirectory(directory string)`, func() {
+		It(`Package the directory and find its playbooks`, func() {
+			playbookPackage, err := schematicsv1.PackagePlaybookDirectory(directory)
+			Expect(err).To(BeNil())
+			Expect(playbookPackage.Playbooks).To(Equal([]string{"db.yaml", "playbooks/deploy.yml", "site.yml"}))
+			Expect(playbookPackage.Files).To(ContainElement("roles/web/tasks/main.yml"))
+			Expect(playbookPackage.Files).To(ContainElement("requirements.yml"))
+			Expect(playbookPackage.Files).ToNot(ContainElement(".git/config"))
+			Expect(playbookPackage.Files).ToNot(ContainElement("playbooks/.hidden-draft.yml"))
+		})
+		It(`Return an error without playbooks`, func() {
+			_, err := schematicsv1.PackagePlaybookDirectory(filepath.Join(directory, "group_vars"))
+			Expect(err).To(MatchError(fmt.Sprintf("no playbook found in %s", filepath.Join(directory, "group_vars"))))
+			_, err = schematicsv1.PackagePlaybookDirectory(filepath.Join(directory, "site.yml"))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		var uploaded []string
+		var states []string
+		var polls int
+
+		BeforeEach(func() {
+			uploaded = nil
+			polls = 0
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "PUT" && path == "/v2/actions/act-1/template_repo_upload":
+					file, _, err := req.FormFile("file")
+					Expect(err).To(BeNil())
+					reader := tar.NewReader(file)
+					for {
+						header, err := reader.Next()
+						if err == io.EOF {
+							break
+						}
+						Expect(err).To(BeNil())
+						uploaded = append(uploaded, header.Name)
+					}
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "act-1", "has_received_file": true}`)
+				case req.Method == "GET" && path == "/v2/actions/act-1":
+					state := states[polls]
+					if polls < len(states)-1 {
+						polls++
+					}
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"id": "act-1", "state": %s, "playbook_names": ["site.yml", "db.yaml"]}`, state)
+				case req.Method == "GET" && path == "/v2/actions/act-stale":
+					// The action keeps the state of the previous upload until the scan starts. The clock of the service is
+					// far behind the local clock.
+					polls++
+					updatedAt := "2021-01-01T00:00:00Z"
+					names := `["old.yml"]`
+					if polls > 3 {
+						updatedAt = "2021-01-01T00:05:00Z"
+						names = `["site.yml"]`
+					}
+					res.WriteHeader(200)
+					fmt.Fprintf(res, `{"id": "act-stale", "state": {"status_code": "normal"}, "updated_at": "%s", "playbook_names": %s}`, updatedAt, names)
+				case req.Method == "PUT" && path == "/v2/actions/act-stale/template_repo_upload":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "act-stale", "has_received_file": true}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke UploadPlaybookDirectory successfully`, func() {
+			states = []string{`{"status_code": "normal"}`, `{"status_code": "pending"}`, `{"status_code": "normal"}`}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.UploadPlaybookDirectory(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			options := schematicsService.NewUploadPlaybookDirectoryOptions("act-1", directory).SetPollInterval(time.Millisecond)
+			result, response, operationErr = schematicsService.UploadPlaybookDirectory(options)
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(uploaded).To(ContainElement("site.yml"))
+			Expect(uploaded).To(ContainElement("roles/web/tasks/main.yml"))
+			Expect(*result.Upload.HasReceivedFile).To(BeTrue())
+			Expect(result.LocalPlaybooks).To(Equal([]string{"db.yaml", "playbooks/deploy.yml", "site.yml"}))
+			Expect(result.PlaybookNames).To(Equal([]string{"site.yml", "db.yaml"}))
+			Expect(polls).To(Equal(2))
+		})
+		It(`Invoke UploadPlaybookDirectory on an action that still reports its previous scan`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			options := schematicsService.NewUploadPlaybookDirectoryOptions("act-stale", directory).SetPollInterval(time.Millisecond)
+			result, _, operationErr := schematicsService.UploadPlaybookDirectory(options)
+			Expect(operationErr).To(BeNil())
+			Expect(polls).To(Equal(4))
+			Expect(result.PlaybookNames).To(Equal([]string{"site.yml"}))
+		})
+		It(`Invoke UploadPlaybookDirectory with a failed scan`, func() {
+			states = []string{`{"status_code": "normal"}`, `{"status_code": "pending"}`, `{"status_code": "critical", "status_message": "invalid playbook"}`}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			options := schematicsService.NewUploadPlaybookDirectoryOptions("act-1", directory).SetPollInterval(time.Millisecond)
+			result, _, operationErr := schematicsService.UploadPlaybookDirectory(options)
+			Expect(operationErr).To(MatchError("scan of action act-1 finished with status critical: invalid playbook"))
+			Expect(result.Action).ToNot(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
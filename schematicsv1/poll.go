@@ -0,0 +1,58 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultPollInterval is the time waited between two status requests by the helpers that wait for a long running
+// operation to finish.
+const DefaultPollInterval = 10 * time.Second
+
+// DefaultWaitTimeout is the maximum time the helpers that wait for a long running operation spend waiting.
+const DefaultWaitTimeout = 60 * time.Minute
+
+// ErrWaitTimeout is returned when a long running operation did not finish within the wait timeout.
+var ErrWaitTimeout = errors.New("timed out waiting for the operation to finish")
+
+// pollUntil calls condition every interval until it reports done or returns an error. ErrWaitTimeout is returned when
+// the condition is not met within timeout. Zero values select DefaultPollInterval and DefaultWaitTimeout.
+func pollUntil(interval time.Duration, timeout time.Duration, condition func() (done bool, err error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return ErrWaitTimeout
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,857 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secret values in redacted output.
+const RedactedValue = "[REDACTED]"
+
+// secretNames are the JSON fields, header names and query parameters whose values are always secret, in lower case.
+var secretNames = []string{
+	"access_token", "api_key", "apikey", "authorization", "git_token", "password", "private_key", "refresh_token",
+	"x-auth-refresh-token", "x-github-token",
+}
+
+// secureValueNames are the fields that hold the value of a variable, which is secret when the variable is secure.
+var secureValueNames = []string{"default_value", "override_value", "value"}
+
+// secretCollectionNames are the fields whose variables are secure even when they are not marked secure.
+var secretCollectionNames = []string{"bastion_credential", "credentials"}
+
+// secretTextPatterns match secrets in free text such as log messages: JSON fields, header lines, query parameters and
+// bearer tokens. The first group is kept, the rest of the match is replaced.
+var secretTextPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)("(?:access_token|api_?key|authorization|git_token|password|private_key|refresh_token|x-auth-refresh-token|x-github-token)"\s*:\s*)"(?:[^"\\]|\\.)*"`),
+	regexp.MustCompile(`(?im)((?:^|[\s,;])(?:authorization|refresh_token|x-auth-refresh-token|x-github-token)\s*:[ \t]*)\S.*$`),
+	regexp.MustCompile(`(?i)((?:^|[?&\s])(?:access_token|api_?key|git_token|password|refresh_token)=)[^&\s"]+`),
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+}
+
+// RedactSecrets returns a copy of a model or options struct, as decoded JSON, with its secrets replaced by
+// RedactedValue: refresh tokens, Git tokens, secret headers, the values of secure variables and the values of
+// credentials. An unencodable value is returned as RedactedValue.
+func RedactSecrets(value interface{}) interface{} {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return RedactedValue
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return RedactedValue
+	}
+	return redactJSONValue(decoded, false)
+}
+
+func redactJSONValue(value interface{}, secure bool) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		secure = secure || isSecureJSONObject(typed)
+		if metadata, ok := typed["metadata"].(map[string]interface{}); ok && isSecureJSONObject(metadata) {
+			secure = true
+		}
+		redacted := make(map[string]interface{}, len(typed))
+		for key, entry := range typed {
+			name := strings.ToLower(key)
+			switch {
+			case entry == nil || entry == "":
+				redacted[key] = entry
+			case containsString(secretNames, name), secure && containsString(secureValueNames, name):
+				redacted[key] = RedactedValue
+			case containsString(secretCollectionNames, name):
+				redacted[key] = redactJSONValue(entry, true)
+			default:
+				redacted[key] = redactJSONValue(entry, false)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(typed))
+		for i, entry := range typed {
+			redacted[i] = redactJSONValue(entry, secure)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func isSecureJSONObject(object map[string]interface{}) bool {
+	switch secure := object["secure"].(type) {
+	case bool:
+		return secure
+	case string:
+		return strings.EqualFold(secure, "true")
+	}
+	return false
+}
+
+// RedactJSON returns a JSON document, such as a request or response body, with its secrets redacted as by
+// RedactSecrets. Content that is not JSON is redacted with RedactText.
+func RedactJSON(document []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return []byte(RedactText(string(document)))
+	}
+	redacted, err := json.Marshal(redactJSONValue(decoded, false))
+	if err != nil {
+		return []byte(RedactText(string(document)))
+	}
+	return redacted
+}
+
+// RedactText returns free text, such as a log message or an error message, with the secrets it recognizes redacted:
+// secret JSON fields, secret header lines, secret query parameters and bearer tokens.
+func RedactText(text string) string {
+	for i, pattern := range secretTextPatterns {
+		replacement := "${1}" + RedactedValue
+		if i == 0 {
+			replacement = `${1}"` + RedactedValue + `"`
+		}
+		text = pattern.ReplaceAllString(text, replacement)
+	}
+	return text
+}
+
+// RedactHeaders returns a copy of HTTP headers with the values of secret headers redacted.
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		if containsString(secretNames, strings.ToLower(name)) {
+			redacted[name] = []string{RedactedValue}
+			continue
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// redactedString returns the JSON encoding of the redacted value, for String methods.
+func redactedString(value interface{}) string {
+	encoded, err := json.Marshal(RedactSecrets(value))
+	if err != nil {
+		return RedactedValue
+	}
+	return string(encoded)
+}
+
+// redactedGoString returns the type and the JSON encoding of the redacted value, for GoString methods.
+func redactedGoString(value interface{}) string {
+	return fmt.Sprintf("%T%s", value, redactedString(value))
+}
+
+// redactingLogger formats each message and redacts it with RedactText before passing it on.
+type redactingLogger struct {
+	logger core.Logger
+}
+
+// NewRedactingLogger returns a core.Logger that redacts the secrets in each message with RedactText before it logs
+// the message with logger.
+func NewRedactingLogger(logger core.Logger) core.Logger {
+	if _, ok := logger.(*redactingLogger); ok {
+		return logger
+	}
+	return &redactingLogger{logger: logger}
+}
+
+func (l *redactingLogger) Log(level core.LogLevel, format string, inserts ...interface{}) {
+	l.logger.Log(level, "%s", RedactText(fmt.Sprintf(format, inserts...)))
+}
+
+func (l *redactingLogger) Error(format string, inserts ...interface{}) {
+	l.logger.Error("%s", RedactText(fmt.Sprintf(format, inserts...)))
+}
+
+func (l *redactingLogger) Warn(format string, inserts ...interface{}) {
+	l.logger.Warn("%s", RedactText(fmt.Sprintf(format, inserts...)))
+}
+
+func (l *redactingLogger) Info(format string, inserts ...interface{}) {
+	l.logger.Info("%s", RedactText(fmt.Sprintf(format, inserts...)))
+}
+
+func (l *redactingLogger) Debug(format string, inserts ...interface{}) {
+	l.logger.Debug("%s", RedactText(fmt.Sprintf(format, inserts...)))
+}
+
+// requestLoggingTransport logs requests and responses at the debug level of the core logger, with secrets redacted.
+type requestLoggingTransport struct {
+	next http.RoundTripper
+}
+
+func (transport *requestLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readLoggedBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	core.GetLogger().Debug("request: %s %s\n%s%s", req.Method, req.URL.String(), formatLoggedHeaders(req.Header),
+		formatLoggedBody(req.Header, body))
+
+	res, err := transport.next.RoundTrip(req)
+	if err != nil {
+		core.GetLogger().Debug("request failed: %s %s: %s", req.Method, req.URL.String(), err.Error())
+		return res, err
+	}
+	body, err = readLoggedBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+	core.GetLogger().Debug("response: %s %s: %s\n%s%s", req.Method, req.URL.String(), res.Status,
+		formatLoggedHeaders(res.Header), formatLoggedBody(res.Header, body))
+	return res, nil
+}
+
+// readLoggedBody reads a request or response body and replaces it with a copy that can be read again.
+func readLoggedBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	content, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(content))
+	return content, nil
+}
+
+func formatLoggedHeaders(headers http.Header) string {
+	var buffer bytes.Buffer
+	RedactHeaders(headers).Write(&buffer)
+	return buffer.String()
+}
+
+// formatLoggedBody redacts a JSON or text body. Other bodies, such as template tar files, are summarized.
+func formatLoggedBody(headers http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	contentType := strings.ToLower(headers.Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "json"):
+		return "\n" + string(RedactJSON(body))
+	case contentType == "" || strings.HasPrefix(contentType, "text/"):
+		return "\n" + RedactText(string(body))
+	default:
+		return fmt.Sprintf("\n<%d bytes of %s>", len(body), contentType)
+	}
+}
+
+// ownHTTPClient replaces the HTTP client of the service with a copy, so that its transport can be wrapped without
+// changing a client that is shared with other services, and returns the copy.
+func (schematics *SchematicsV1) ownHTTPClient() *http.Client {
+	var client *http.Client
+	if schematics.Service.Client == nil {
+		client = core.DefaultHTTPClient()
+	} else {
+		owned := *schematics.Service.Client
+		client = &owned
+	}
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+	schematics.Service.SetHTTPClient(client)
+	return client
+}
+
+// EnableRequestLogging : Log requests and responses with their secrets redacted
+// Wrap the HTTP client of the service so that each request and response is logged at the debug level of the core
+// logger, with secret headers and the secrets in JSON bodies redacted, and wrap the core logger with
+// NewRedactingLogger. A client that is shared with other services is copied, not changed.
+//
+// Set the logging level before this call: core.SetLoggingLevel replaces the core logger with one that does not
+// redact, so call EnableRequestLogging again after it. Call it again also after replacing the HTTP client, for example
+// with EnableRetries.
+func (schematics *SchematicsV1) EnableRequestLogging() {
+	core.SetLogger(NewRedactingLogger(core.GetLogger()))
+	if client := schematics.Service.Client; client != nil {
+		if _, ok := client.Transport.(*requestLoggingTransport); ok {
+			return
+		}
+	}
+	client := schematics.ownHTTPClient()
+	client.Transport = &requestLoggingTransport{next: client.Transport}
+}
+
+// String returns the EnvVariableResponse as JSON, with the value of a secure variable redacted.
+func (variable EnvVariableResponse) String() string {
+	return redactedString(variable)
+}
+
+// GoString returns the EnvVariableResponse for the %#v verb, with the value of a secure variable redacted.
+func (variable EnvVariableResponse) GoString() string {
+	return redactedGoString(variable)
+}
+
+// MarshalLog returns the EnvVariableResponse for structured loggers, with the value of a secure variable redacted.
+func (variable EnvVariableResponse) MarshalLog() interface{} {
+	return RedactSecrets(variable)
+}
+
+// String returns the ExternalSourceGit as JSON, with the Git token redacted.
+func (source ExternalSourceGit) String() string {
+	return redactedString(source)
+}
+
+// GoString returns the ExternalSourceGit for the %#v verb, with the Git token redacted.
+func (source ExternalSourceGit) GoString() string {
+	return redactedGoString(source)
+}
+
+// MarshalLog returns the ExternalSourceGit for structured loggers, with the Git token redacted.
+func (source ExternalSourceGit) MarshalLog() interface{} {
+	return RedactSecrets(source)
+}
+
+// String returns the SharedDatasetData as JSON, with the values of secure data redacted.
+func (data SharedDatasetData) String() string {
+	return redactedString(data)
+}
+
+// GoString returns the SharedDatasetData for the %#v verb, with the values of secure data redacted.
+func (data SharedDatasetData) GoString() string {
+	return redactedGoString(data)
+}
+
+// MarshalLog returns the SharedDatasetData for structured loggers, with the values of secure data redacted.
+func (data SharedDatasetData) MarshalLog() interface{} {
+	return RedactSecrets(data)
+}
+
+// String returns the VariableData as JSON, with the value of a secure variable redacted.
+func (variable VariableData) String() string {
+	return redactedString(variable)
+}
+
+// GoString returns the VariableData for the %#v verb, with the value of a secure variable redacted.
+func (variable VariableData) GoString() string {
+	return redactedGoString(variable)
+}
+
+// MarshalLog returns the VariableData for structured loggers, with the value of a secure variable redacted.
+func (variable VariableData) MarshalLog() interface{} {
+	return RedactSecrets(variable)
+}
+
+// String returns the VariableMetadata as JSON, with the default value of a secure variable redacted.
+func (metadata VariableMetadata) String() string {
+	return redactedString(metadata)
+}
+
+// GoString returns the VariableMetadata for the %#v verb, with the default value of a secure variable redacted.
+func (metadata VariableMetadata) GoString() string {
+	return redactedGoString(metadata)
+}
+
+// MarshalLog returns the VariableMetadata for structured loggers, with the default value of a secure variable redacted.
+func (metadata VariableMetadata) MarshalLog() interface{} {
+	return RedactSecrets(metadata)
+}
+
+// String returns the WorkspaceVariableRequest as JSON, with the value of a secure variable redacted.
+func (variable WorkspaceVariableRequest) String() string {
+	return redactedString(variable)
+}
+
+// GoString returns the WorkspaceVariableRequest for the %#v verb, with the value of a secure variable redacted.
+func (variable WorkspaceVariableRequest) GoString() string {
+	return redactedGoString(variable)
+}
+
+// MarshalLog returns the WorkspaceVariableRequest for structured loggers, with the value of a secure variable redacted.
+func (variable WorkspaceVariableRequest) MarshalLog() interface{} {
+	return RedactSecrets(variable)
+}
+
+// String returns the WorkspaceVariableResponse as JSON, with the value of a secure variable redacted.
+func (variable WorkspaceVariableResponse) String() string {
+	return redactedString(variable)
+}
+
+// GoString returns the WorkspaceVariableResponse for the %#v verb, with the value of a secure variable redacted.
+func (variable WorkspaceVariableResponse) GoString() string {
+	return redactedGoString(variable)
+}
+
+// MarshalLog returns the WorkspaceVariableResponse for structured loggers, with the value of a secure variable redacted.
+func (variable WorkspaceVariableResponse) MarshalLog() interface{} {
+	return RedactSecrets(variable)
+}
+
+// String returns the ApplyWorkspaceCommandOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options ApplyWorkspaceCommandOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the ApplyWorkspaceCommandOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options ApplyWorkspaceCommandOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the ApplyWorkspaceCommandOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options ApplyWorkspaceCommandOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the BulkDeleteWorkspacesOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options BulkDeleteWorkspacesOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the BulkDeleteWorkspacesOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options BulkDeleteWorkspacesOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the BulkDeleteWorkspacesOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options BulkDeleteWorkspacesOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CheckActionBastionOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CheckActionBastionOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CheckActionBastionOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CheckActionBastionOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CheckActionBastionOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CheckActionBastionOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CheckAndRunPlaybookOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CheckAndRunPlaybookOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CheckAndRunPlaybookOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CheckAndRunPlaybookOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CheckAndRunPlaybookOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CheckAndRunPlaybookOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CheckBastionOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CheckBastionOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CheckBastionOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CheckBastionOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CheckBastionOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CheckBastionOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CloneWorkspaceOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CloneWorkspaceOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CloneWorkspaceOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CloneWorkspaceOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CloneWorkspaceOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CloneWorkspaceOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CreateActionOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CreateActionOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CreateActionOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CreateActionOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CreateActionOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CreateActionOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CreateJobOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CreateJobOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CreateJobOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CreateJobOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CreateJobOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CreateJobOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CreateWorkspaceDeletionJobOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceDeletionJobOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CreateWorkspaceDeletionJobOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceDeletionJobOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CreateWorkspaceDeletionJobOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceDeletionJobOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the CreateWorkspaceOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the CreateWorkspaceOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the CreateWorkspaceOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options CreateWorkspaceOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the DeleteJobOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options DeleteJobOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the DeleteJobOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options DeleteJobOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the DeleteJobOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options DeleteJobOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the DeleteWorkspaceOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options DeleteWorkspaceOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the DeleteWorkspaceOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options DeleteWorkspaceOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the DeleteWorkspaceOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options DeleteWorkspaceOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the DestroyWorkspaceCommandOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options DestroyWorkspaceCommandOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the DestroyWorkspaceCommandOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options DestroyWorkspaceCommandOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the DestroyWorkspaceCommandOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options DestroyWorkspaceCommandOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the DetectDriftBatchOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options DetectDriftBatchOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the DetectDriftBatchOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options DetectDriftBatchOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the DetectDriftBatchOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options DetectDriftBatchOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the DetectDriftOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options DetectDriftOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the DetectDriftOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options DetectDriftOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the DetectDriftOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options DetectDriftOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the ImportResourceOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options ImportResourceOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the ImportResourceOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options ImportResourceOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the ImportResourceOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options ImportResourceOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the MoveResourceOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options MoveResourceOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the MoveResourceOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options MoveResourceOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the MoveResourceOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options MoveResourceOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the PlanWorkspaceCommandOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options PlanWorkspaceCommandOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the PlanWorkspaceCommandOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options PlanWorkspaceCommandOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the PlanWorkspaceCommandOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options PlanWorkspaceCommandOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RefreshWorkspaceCommandOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RefreshWorkspaceCommandOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RefreshWorkspaceCommandOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RefreshWorkspaceCommandOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RefreshWorkspaceCommandOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RefreshWorkspaceCommandOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RemoveFromStateOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RemoveFromStateOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RemoveFromStateOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RemoveFromStateOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RemoveFromStateOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RemoveFromStateOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the ReplaceJobOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options ReplaceJobOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the ReplaceJobOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options ReplaceJobOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the ReplaceJobOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options ReplaceJobOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the ReplaceWorkspaceInputsOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options ReplaceWorkspaceInputsOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the ReplaceWorkspaceInputsOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options ReplaceWorkspaceInputsOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the ReplaceWorkspaceInputsOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options ReplaceWorkspaceInputsOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RunActionAndWaitOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RunActionAndWaitOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RunActionAndWaitOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RunActionAndWaitOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RunActionAndWaitOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RunActionAndWaitOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RunStateCommandsOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RunStateCommandsOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RunStateCommandsOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RunStateCommandsOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RunStateCommandsOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RunStateCommandsOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RunTerraformCommandsOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RunTerraformCommandsOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RunTerraformCommandsOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RunTerraformCommandsOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RunTerraformCommandsOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RunTerraformCommandsOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the RunWorkspaceCommandsOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options RunWorkspaceCommandsOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the RunWorkspaceCommandsOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options RunWorkspaceCommandsOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the RunWorkspaceCommandsOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options RunWorkspaceCommandsOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the TaintOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options TaintOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the TaintOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options TaintOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the TaintOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options TaintOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the SyncSharedDatasetOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options SyncSharedDatasetOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the SyncSharedDatasetOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options SyncSharedDatasetOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the SyncSharedDatasetOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options SyncSharedDatasetOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the TargetedWorkspaceCommandOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options TargetedWorkspaceCommandOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the TargetedWorkspaceCommandOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options TargetedWorkspaceCommandOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the TargetedWorkspaceCommandOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options TargetedWorkspaceCommandOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
+
+// String returns the UpdateActionOptions as JSON, with tokens, secret headers and secure values redacted.
+func (options UpdateActionOptions) String() string {
+	return redactedString(options)
+}
+
+// GoString returns the UpdateActionOptions for the %#v verb, with tokens, secret headers and secure values redacted.
+func (options UpdateActionOptions) GoString() string {
+	return redactedGoString(options)
+}
+
+// MarshalLog returns the UpdateActionOptions for structured loggers, with tokens, secret headers and secure values redacted.
+func (options UpdateActionOptions) MarshalLog() interface{} {
+	return RedactSecrets(options)
+}
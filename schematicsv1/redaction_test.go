@@ -0,0 +1,220 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Secret redaction`, func() {
+	secureVariable := schematicsv1.WorkspaceVariableRequest{
+		Name:   core.StringPtr("api_key"),
+		Secure: core.BoolPtr(true),
+		Value:  core.StringPtr("s3cr3t-value"),
+	}
+	plainVariable := schematicsv1.WorkspaceVariableRequest{
+		Name:  core.StringPtr("region"),
+		Value: core.StringPtr("us-south"),
+	}
+
+	Describe(`Formatting models and options`, func() {
+		It(`Redact secure variables`, func() {
+			for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+				formatted := fmt.Sprintf(format, secureVariable)
+				Expect(formatted).ToNot(ContainSubstring("s3cr3t-value"))
+				Expect(formatted).To(ContainSubstring(schematicsv1.RedactedValue))
+				Expect(fmt.Sprintf(format, &secureVariable)).ToNot(ContainSubstring("s3cr3t-value"))
+			}
+			Expect(plainVariable.String()).To(Equal(`{"name":"region","value":"us-south"}`))
+			Expect(secureVariable.GoString()).To(Equal(`schematicsv1.WorkspaceVariableRequest{"name":"api_key","secure":true,"value":"[REDACTED]"}`))
+
+			variable := schematicsv1.VariableData{
+				Name:     core.StringPtr("password"),
+				Value:    core.StringPtr("hunter2"),
+				Metadata: &schematicsv1.VariableMetadata{Secure: core.BoolPtr(true), DefaultValue: core.StringPtr("changeme")},
+			}
+			Expect(variable.String()).ToNot(ContainSubstring("hunter2"))
+			Expect(variable.String()).ToNot(ContainSubstring("changeme"))
+			Expect(fmt.Sprintf("%+v", []schematicsv1.VariableData{variable})).ToNot(ContainSubstring("hunter2"))
+		})
+		It(`Redact tokens, headers and credentials of options`, func() {
+			createWorkspaceOptions := &schematicsv1.CreateWorkspaceOptions{
+				Name:         core.StringPtr("ws"),
+				XGithubToken: core.StringPtr("ghp_token"),
+				TemplateData: []schematicsv1.TemplateSourceDataRequest{{
+					Variablestore: []schematicsv1.WorkspaceVariableRequest{secureVariable, plainVariable},
+				}},
+				TemplateRepo: &schematicsv1.TemplateRepoRequest{URL: core.StringPtr("https://github.com/org/repo")},
+				Headers:      map[string]string{"Authorization": "Bearer abc", "X-Request-Id": "req-1"},
+			}
+			formatted := fmt.Sprintf("%+v", createWorkspaceOptions)
+			Expect(formatted).ToNot(ContainSubstring("ghp_token"))
+			Expect(formatted).ToNot(ContainSubstring("s3cr3t-value"))
+			Expect(formatted).ToNot(ContainSubstring("Bearer abc"))
+			Expect(formatted).To(ContainSubstring("us-south"))
+			Expect(formatted).To(ContainSubstring("req-1"))
+
+			createActionOptions := &schematicsv1.CreateActionOptions{
+				Name:        core.StringPtr("action"),
+				Source:      &schematicsv1.ExternalSource{Git: &schematicsv1.ExternalSourceGit{GitRepoURL: core.StringPtr("https://github.com/org/repo"), GitToken: core.StringPtr("git-secret")}},
+				Credentials: []schematicsv1.VariableData{{Name: core.StringPtr("ssh_key"), Value: core.StringPtr("-----BEGIN KEY")}},
+				Inputs:      []schematicsv1.VariableData{{Name: core.StringPtr("count"), Value: core.StringPtr("3")}},
+			}
+			redacted := createActionOptions.MarshalLog().(map[string]interface{})
+			Expect(redacted["source"].(map[string]interface{})["git"].(map[string]interface{})["git_token"]).To(Equal(schematicsv1.RedactedValue))
+			Expect(redacted["credentials"].([]interface{})[0].(map[string]interface{})["value"]).To(Equal(schematicsv1.RedactedValue))
+			Expect(redacted["inputs"].([]interface{})[0].(map[string]interface{})["value"]).To(Equal("3"))
+
+			createJobOptions := &schematicsv1.CreateJobOptions{RefreshToken: core.StringPtr("refresh-secret")}
+			Expect(fmt.Sprintf("%#v", createJobOptions)).ToNot(ContainSubstring("refresh-secret"))
+
+			replaceWorkspaceInputsOptions := &schematicsv1.ReplaceWorkspaceInputsOptions{
+				WID:           core.StringPtr("ws-1"),
+				TID:           core.StringPtr("t-1"),
+				EnvValues:     []interface{}{map[string]interface{}{"name": "TOKEN", "value": "env-secret", "secure": true}},
+				Variablestore: []schematicsv1.WorkspaceVariableRequest{secureVariable},
+				Headers:       map[string]string{"Authorization": "Bearer abc"},
+			}
+			for _, format := range []string{"%v", "%+v", "%#v"} {
+				formatted := fmt.Sprintf(format, replaceWorkspaceInputsOptions)
+				Expect(formatted).ToNot(ContainSubstring("env-secret"))
+				Expect(formatted).ToNot(ContainSubstring("s3cr3t-value"))
+				Expect(formatted).ToNot(ContainSubstring("Bearer abc"))
+				Expect(formatted).To(ContainSubstring("ws-1"))
+			}
+		})
+		It(`Keep the JSON encoding of requests intact`, func() {
+			encoded, err := json.Marshal(secureVariable)
+			Expect(err).To(BeNil())
+			Expect(string(encoded)).To(ContainSubstring("s3cr3t-value"))
+		})
+	})
+
+	Describe(`Redacting text, JSON and headers`, func() {
+		It(`Redact secrets in free text`, func() {
+			text := "Authorization: Bearer eyJhbGciOi.abc\nrefresh_token: r3fresh\nGET /v1/workspaces?refresh_token=r3fresh&profile=ids\n" +
+				`{"git_token": "ghp_x", "name": "ws"}`
+			redacted := schematicsv1.RedactText(text)
+			Expect(redacted).ToNot(ContainSubstring("eyJhbGciOi"))
+			Expect(redacted).ToNot(ContainSubstring("r3fresh"))
+			Expect(redacted).ToNot(ContainSubstring("ghp_x"))
+			Expect(redacted).To(ContainSubstring("profile=ids"))
+			Expect(redacted).To(ContainSubstring(`"git_token": "[REDACTED]"`))
+			Expect(redacted).To(ContainSubstring(`"name": "ws"`))
+		})
+		It(`Redact JSON documents`, func() {
+			document := `{"variablestore": [{"name": "a", "secure": true, "value": "x1"}, {"name": "b", "value": "y2"}], "count": 12345678901234567890}`
+			redacted := string(schematicsv1.RedactJSON([]byte(document)))
+			Expect(redacted).ToNot(ContainSubstring("x1"))
+			Expect(redacted).To(ContainSubstring(`"value":"y2"`))
+			Expect(redacted).To(ContainSubstring("12345678901234567890"))
+			Expect(string(schematicsv1.RedactJSON([]byte("refresh_token=abc")))).To(Equal("refresh_token=[REDACTED]"))
+		})
+		It(`Redact headers`, func() {
+			headers := http.Header{"Authorization": {"Bearer abc"}, "X-Github-Token": {"ghp"}, "Accept": {"application/json"}}
+			redacted := schematicsv1.RedactHeaders(headers)
+			Expect(redacted.Get("Authorization")).To(Equal(schematicsv1.RedactedValue))
+			Expect(redacted.Get("X-Github-Token")).To(Equal(schematicsv1.RedactedValue))
+			Expect(redacted.Get("Accept")).To(Equal("application/json"))
+			Expect(headers.Get("Authorization")).To(Equal("Bearer abc"))
+		})
+	})
+
+	Describe(`Logging`, func() {
+		var previousLogger core.Logger
+		var output *bytes.Buffer
+
+		BeforeEach(func() {
+			previousLogger = core.GetLogger()
+			output = &bytes.Buffer{}
+			core.SetLogger(core.NewLogger(core.LevelDebug, log.New(output, "", 0)))
+		})
+		AfterEach(func() {
+			core.SetLogger(previousLogger)
+		})
+		It(`Redact log messages`, func() {
+			logger := schematicsv1.NewRedactingLogger(core.GetLogger())
+			Expect(schematicsv1.NewRedactingLogger(logger)).To(BeIdenticalTo(logger))
+			logger.Info("calling with %s", "Authorization: Bearer abc.def")
+			logger.Error("variable %v", secureVariable)
+			Expect(output.String()).To(Equal("[Info] calling with Authorization: [REDACTED]\n" +
+				`[Error] variable {"name":"api_key","secure":true,"value":"[REDACTED]"}` + "\n"))
+		})
+		It(`Log requests and responses with EnableRequestLogging`, func() {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				body, err := ioutil.ReadAll(req.Body)
+				Expect(err).To(BeNil())
+				Expect(string(body)).To(ContainSubstring("s3cr3t-value"))
+				Expect(req.Header.Get("X-Github-token")).To(Equal("ghp_token"))
+				res.Header().Set("Content-type", "application/json")
+				res.WriteHeader(201)
+				fmt.Fprintf(res, "%s", `{"id": "ws-1", "template_data": [{"variablestore": [{"name": "api_key", "secure": true, "value": "s3cr3t-value"}]}]}`)
+			}))
+			defer testServer.Close()
+
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+			schematicsService.EnableRequestLogging()
+			schematicsService.EnableRequestLogging()
+
+			options := schematicsService.NewCreateWorkspaceOptions()
+			options.SetXGithubToken("ghp_token")
+			options.SetTemplateData([]schematicsv1.TemplateSourceDataRequest{{
+				Variablestore: []schematicsv1.WorkspaceVariableRequest{secureVariable},
+			}})
+			result, _, operationErr := schematicsService.CreateWorkspace(options)
+			Expect(operationErr).To(BeNil())
+			Expect(*result.TemplateData[0].Variablestore[0].Value).To(Equal("s3cr3t-value"))
+
+			logged := output.String()
+			Expect(logged).To(ContainSubstring("request: POST " + testServer.URL + "/v1/workspaces"))
+			Expect(logged).To(ContainSubstring("response: POST " + testServer.URL + "/v1/workspaces: 201 Created"))
+			Expect(logged).To(ContainSubstring("X-Github-token: [REDACTED]"))
+			Expect(logged).ToNot(ContainSubstring("ghp_token"))
+			Expect(logged).ToNot(ContainSubstring("s3cr3t-value"))
+		})
+		It(`Keep a shared HTTP client unchanged`, func() {
+			shared := &http.Client{Timeout: 30 * time.Second}
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           "http://schematicsv1modelgenerator.com",
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+			schematicsService.Service.SetHTTPClient(shared)
+			schematicsService.EnableRequestLogging()
+			Expect(shared.Transport).To(BeNil())
+			Expect(schematicsService.Service.Client).ToNot(BeIdenticalTo(shared))
+			Expect(schematicsService.Service.Client.Timeout).To(Equal(30 * time.Second))
+		})
+	})
+})
@@ -0,0 +1,490 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Constants associated with the ResourceQueryParam.Name property of a workspaces query.
+// The conditions that a workspaces query supports.
+const (
+	ResourceQueryParam_Name_IPAddress     = "ip-address"
+	ResourceQueryParam_Name_ResourceName  = "resource-name"
+	ResourceQueryParam_Name_ResourceTag   = "resource-tag"
+	ResourceQueryParam_Name_ResourceType  = "resource-type"
+	ResourceQueryParam_Name_WorkspaceID   = "workspace-id"
+	ResourceQueryParam_Name_WorkspaceName = "workspace-name"
+)
+
+// Constants associated with the ResourceQuery.QuerySelect property of a workspaces query.
+// The fields that a workspaces query can select.
+const (
+	ResourceQuery_QuerySelect_ID            = "id"
+	ResourceQuery_QuerySelect_IPv4Address   = "ipv4_address"
+	ResourceQuery_QuerySelect_Name          = "name"
+	ResourceQuery_QuerySelect_Tags          = "tags"
+	ResourceQuery_QuerySelect_Type          = "type"
+	ResourceQuery_QuerySelect_WorkspaceID   = "workspace_id"
+	ResourceQuery_QuerySelect_WorkspaceName = "workspace_name"
+)
+
+// resourceQueryFields lists the conditions and select fields of each supported query type.
+var resourceQueryFields = map[string]struct {
+	conditions []string
+	selects    []string
+}{
+	ResourceQuery_QueryType_Workspaces: {
+		conditions: []string{
+			ResourceQueryParam_Name_IPAddress,
+			ResourceQueryParam_Name_ResourceName,
+			ResourceQueryParam_Name_ResourceTag,
+			ResourceQueryParam_Name_ResourceType,
+			ResourceQueryParam_Name_WorkspaceID,
+			ResourceQueryParam_Name_WorkspaceName,
+		},
+		selects: []string{
+			ResourceQuery_QuerySelect_ID,
+			ResourceQuery_QuerySelect_IPv4Address,
+			ResourceQuery_QuerySelect_Name,
+			ResourceQuery_QuerySelect_Tags,
+			ResourceQuery_QuerySelect_Type,
+			ResourceQuery_QuerySelect_WorkspaceID,
+			ResourceQuery_QuerySelect_WorkspaceName,
+		},
+	},
+}
+
+// ResourceQueryBuilder : Build a validated workspaces resource query.
+type ResourceQueryBuilder struct {
+	query ResourceQuery
+}
+
+// NewResourceQueryBuilder : Instantiate ResourceQueryBuilder
+func (*SchematicsV1) NewResourceQueryBuilder() *ResourceQueryBuilder {
+	return &ResourceQueryBuilder{
+		query: ResourceQuery{QueryType: core.StringPtr(ResourceQuery_QueryType_Workspaces)},
+	}
+}
+
+// WorkspaceID : Match the resources of the workspace with this ID
+func (builder *ResourceQueryBuilder) WorkspaceID(wID string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_WorkspaceID, wID)
+}
+
+// WorkspaceName : Match the resources of the workspaces whose name matches the pattern
+func (builder *ResourceQueryBuilder) WorkspaceName(pattern string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_WorkspaceName, pattern)
+}
+
+// ResourceType : Match the resources of this Terraform type, such as ibm_is_instance
+func (builder *ResourceQueryBuilder) ResourceType(resourceType string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_ResourceType, resourceType)
+}
+
+// ResourceName : Match the resources whose name matches the pattern
+func (builder *ResourceQueryBuilder) ResourceName(pattern string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_ResourceName, pattern)
+}
+
+// Tag : Match the resources with this tag. A tag without colon also matches key:value tags with that key.
+func (builder *ResourceQueryBuilder) Tag(tag string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_ResourceTag, tag)
+}
+
+// IPAddress : Match the resources with an IP address equal to ip or within the CIDR range
+func (builder *ResourceQueryBuilder) IPAddress(ipOrCIDR string) *ResourceQueryBuilder {
+	return builder.Where(ResourceQueryParam_Name_IPAddress, ipOrCIDR)
+}
+
+// Where : Add a condition
+func (builder *ResourceQueryBuilder) Where(name string, value string) *ResourceQueryBuilder {
+	builder.query.QueryCondition = append(builder.query.QueryCondition, ResourceQueryParam{
+		Name:  core.StringPtr(name),
+		Value: core.StringPtr(value),
+	})
+	return builder
+}
+
+// Select : Add fields to the query output
+func (builder *ResourceQueryBuilder) Select(fields ...string) *ResourceQueryBuilder {
+	builder.query.QuerySelect = append(builder.query.QuerySelect, fields...)
+	return builder
+}
+
+// Build : Validate the query and return it
+func (builder *ResourceQueryBuilder) Build() (*ResourceQuery, error) {
+	query := builder.query
+	query.QueryCondition = append([]ResourceQueryParam{}, builder.query.QueryCondition...)
+	query.QuerySelect = append([]string{}, builder.query.QuerySelect...)
+	if err := ValidateResourceQuery(&query); err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// ValidateResourceQuery checks that the query type is supported, that the conditions and select fields exist for the
+// query type and that condition values are well formed. All problems found are reported in a single error.
+func ValidateResourceQuery(query *ResourceQuery) error {
+	if query.QueryType == nil {
+		return fmt.Errorf("invalid resource query: query_type is required")
+	}
+	fields, supported := resourceQueryFields[*query.QueryType]
+	if !supported {
+		return fmt.Errorf("invalid resource query: unsupported query_type %q", *query.QueryType)
+	}
+
+	var problems []string
+	for _, condition := range query.QueryCondition {
+		if condition.Name == nil || !containsString(fields.conditions, *condition.Name) {
+			name := ""
+			if condition.Name != nil {
+				name = *condition.Name
+			}
+			problems = append(problems, fmt.Sprintf("unknown condition %q, expected one of %s", name, strings.Join(fields.conditions, ", ")))
+			continue
+		}
+		value := ""
+		if condition.Value != nil {
+			value = *condition.Value
+		}
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("condition %s: value is required", *condition.Name))
+			continue
+		}
+		switch *condition.Name {
+		case ResourceQueryParam_Name_IPAddress:
+			if _, err := parseIPFilter(value); err != nil {
+				problems = append(problems, fmt.Sprintf("condition %s: %s", *condition.Name, err.Error()))
+			}
+		case ResourceQueryParam_Name_WorkspaceName, ResourceQueryParam_Name_ResourceName:
+			if _, err := path.Match(value, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("condition %s: invalid pattern %q", *condition.Name, value))
+			}
+		}
+	}
+	if len(query.QuerySelect) == 0 {
+		problems = append(problems, "query_select is required")
+	}
+	for _, field := range query.QuerySelect {
+		if !containsString(fields.selects, field) {
+			problems = append(problems, fmt.Sprintf("unknown select field %q, expected one of %s", field, strings.Join(fields.selects, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid resource query: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// parseIPFilter parses an IP address or CIDR range.
+func parseIPFilter(value string) (*net.IPNet, error) {
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("%q is neither an IP address nor a CIDR range", value)
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip, bits = ip.To4(), 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// String returns the query in a readable form, such as:
+// workspaces where workspace-name = "prod-*" and resource-type = "ibm_is_instance" select name, ipv4_address
+func (query *ResourceQuery) String() string {
+	var text strings.Builder
+	if query.QueryType != nil {
+		text.WriteString(*query.QueryType)
+	}
+	for i, condition := range query.QueryCondition {
+		if i == 0 {
+			text.WriteString(" where ")
+		} else {
+			text.WriteString(" and ")
+		}
+		name, value := "", ""
+		if condition.Name != nil {
+			name = *condition.Name
+		}
+		if condition.Value != nil {
+			value = *condition.Value
+		}
+		text.WriteString(name + " = " + strconv.Quote(value))
+	}
+	if len(query.QuerySelect) > 0 {
+		text.WriteString(" select " + strings.Join(query.QuerySelect, ", "))
+	}
+	return text.String()
+}
+
+// QueryableResource : A resource that a resource query can be evaluated against locally.
+type QueryableResource struct {
+	// The ID of the workspace of the resource.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+
+	// The name of the workspace of the resource.
+	WorkspaceName string `json:"workspace_name,omitempty"`
+
+	// The Terraform address of the resource instance.
+	Address string `json:"address,omitempty"`
+
+	// The Terraform resource type.
+	Type string `json:"type,omitempty"`
+
+	// The resource attributes.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Name returns the 'name' attribute of the resource, or the name of its Terraform address.
+func (resource *QueryableResource) Name() string {
+	if name, ok := resource.Attributes["name"].(string); ok && name != "" {
+		return name
+	}
+	if dot := strings.LastIndex(resource.Address, "."); dot >= 0 {
+		name := resource.Address[dot+1:]
+		if bracket := strings.Index(name, "["); bracket >= 0 {
+			name = name[:bracket]
+		}
+		return name
+	}
+	return resource.Address
+}
+
+// ID returns the 'id' attribute of the resource.
+func (resource *QueryableResource) ID() string {
+	id, _ := resource.Attributes["id"].(string)
+	return id
+}
+
+// Tags returns the 'tags' attribute of the resource.
+func (resource *QueryableResource) Tags() (tags []string) {
+	list, _ := resource.Attributes["tags"].([]interface{})
+	for _, tag := range list {
+		if s, ok := tag.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return
+}
+
+// IPAddresses returns the IP addresses found in the attributes of the resource whose name contains 'ip' or
+// 'address', in attribute name order.
+func (resource *QueryableResource) IPAddresses() []string {
+	var addresses []string
+	var walk func(key string, value interface{})
+	walk = func(key string, value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for _, child := range sortedKeys(typed) {
+				walk(child, typed[child])
+			}
+		case []interface{}:
+			for _, item := range typed {
+				walk(key, item)
+			}
+		case string:
+			lower := strings.ToLower(key)
+			if (strings.Contains(lower, "ip") || strings.Contains(lower, "address")) && net.ParseIP(typed) != nil &&
+				!containsString(addresses, typed) {
+				addresses = append(addresses, typed)
+			}
+		}
+	}
+	walk("", resource.Attributes)
+	return addresses
+}
+
+// QueryableResourcesFromState returns the managed resource instances of a decoded Terraform state.
+func QueryableResourcesFromState(wID string, workspaceName string, state *TerraformState) (resources []QueryableResource) {
+	for i := range state.Resources {
+		resource := &state.Resources[i]
+		if resource.Mode == TerraformStateResource_Mode_Data {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			resources = append(resources, QueryableResource{
+				WorkspaceID:   wID,
+				WorkspaceName: workspaceName,
+				Address:       resource.InstanceAddress(instance),
+				Type:          resource.Type,
+				Attributes:    instance.Attributes,
+			})
+		}
+	}
+	return
+}
+
+// QueryableResourcesFromTemplateResources returns the resources of a GetWorkspaceResources result. The type and name of
+// a resource are read from its 'resource_type' and 'resource_name' entries, or 'type' and 'name'; every entry is
+// kept as an attribute.
+func QueryableResourcesFromTemplateResources(wID string, workspaceName string, templates []TemplateResources) (resources []QueryableResource) {
+	for _, template := range templates {
+		for _, item := range template.Resources {
+			attributes, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			resourceType := firstString(attributes, "resource_type", "type")
+			name := firstString(attributes, "resource_name", "name")
+			address := resourceType + "." + name
+			if resourceAddress := firstString(attributes, "address", "resource_address"); resourceAddress != "" {
+				address = resourceAddress
+			}
+			resources = append(resources, QueryableResource{
+				WorkspaceID:   wID,
+				WorkspaceName: workspaceName,
+				Address:       address,
+				Type:          resourceType,
+				Attributes:    attributes,
+			})
+		}
+	}
+	return
+}
+
+func firstString(attributes map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := attributes[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// MatchResourceQuery returns the resources that match every condition of the query, in their original order.
+func MatchResourceQuery(query *ResourceQuery, resources []QueryableResource) ([]QueryableResource, error) {
+	if err := ValidateResourceQuery(query); err != nil {
+		return nil, err
+	}
+	var matches []QueryableResource
+	for _, resource := range resources {
+		if matchesResourceQuery(query, &resource) {
+			matches = append(matches, resource)
+		}
+	}
+	return matches, nil
+}
+
+func matchesResourceQuery(query *ResourceQuery, resource *QueryableResource) bool {
+	for _, condition := range query.QueryCondition {
+		value := *condition.Value
+		switch *condition.Name {
+		case ResourceQueryParam_Name_WorkspaceID:
+			if !strings.EqualFold(resource.WorkspaceID, value) {
+				return false
+			}
+		case ResourceQueryParam_Name_WorkspaceName:
+			if !matchesPattern(value, resource.WorkspaceName) {
+				return false
+			}
+		case ResourceQueryParam_Name_ResourceType:
+			if resource.Type != value {
+				return false
+			}
+		case ResourceQueryParam_Name_ResourceName:
+			if !matchesPattern(value, resource.Name()) {
+				return false
+			}
+		case ResourceQueryParam_Name_ResourceTag:
+			if !matchesTag(resource.Tags(), value) {
+				return false
+			}
+		case ResourceQueryParam_Name_IPAddress:
+			network, _ := parseIPFilter(value)
+			found := false
+			for _, address := range resource.IPAddresses() {
+				if network.Contains(net.ParseIP(address)) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesPattern reports whether value matches the pattern, ignoring case.
+func matchesPattern(pattern string, value string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return err == nil && matched
+}
+
+// PreviewResourceQuery evaluates the query locally against the resources and returns the output that the query is
+// expected to produce: for every matching resource, one output item per select field in select order. Fields without a
+// value are omitted.
+func PreviewResourceQuery(query *ResourceQuery, resources []QueryableResource) (*ResourceQueryResponseRecordResponseItem, error) {
+	matches, err := MatchResourceQuery(query, resources)
+	if err != nil {
+		return nil, err
+	}
+	result := &ResourceQueryResponseRecordResponseItem{
+		QueryType:      query.QueryType,
+		QueryCondition: query.QueryCondition,
+		QuerySelect:    query.QuerySelect,
+		QueryOutput:    []ResourceQueryResponseRecordResponseItemQueryOutputItem{},
+	}
+	for i := range matches {
+		for _, field := range query.QuerySelect {
+			if value := selectResourceField(&matches[i], field); value != "" {
+				result.QueryOutput = append(result.QueryOutput, ResourceQueryResponseRecordResponseItemQueryOutputItem{
+					Name:  core.StringPtr(field),
+					Value: core.StringPtr(value),
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func selectResourceField(resource *QueryableResource, field string) string {
+	switch field {
+	case ResourceQuery_QuerySelect_ID:
+		return resource.ID()
+	case ResourceQuery_QuerySelect_IPv4Address:
+		for _, address := range resource.IPAddresses() {
+			if net.ParseIP(address).To4() != nil {
+				return address
+			}
+		}
+	case ResourceQuery_QuerySelect_Name:
+		return resource.Name()
+	case ResourceQuery_QuerySelect_Tags:
+		tags := resource.Tags()
+		sort.Strings(tags)
+		return strings.Join(tags, ",")
+	case ResourceQuery_QuerySelect_Type:
+		return resource.Type
+	case ResourceQuery_QuerySelect_WorkspaceID:
+		return resource.WorkspaceID
+	case ResourceQuery_QuerySelect_WorkspaceName:
+		return resource.WorkspaceName
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Resource queries`, func() {
+	schematicsService, _ := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+		URL:           "http://schematicsv1modelgenerator.com",
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+
+	stateJSON := `{
+		"version": 4,
+		"resources": [
+			{"mode": "data", "type": "ibm_is_image", "name": "image", "instances": [{"attributes": {"id": "img-1", "name": "ubuntu"}}]},
+			{"mode": "managed", "type": "ibm_is_instance", "name": "vsi", "instances": [
+				{"index_key": 0, "attributes": {"id": "vsi-0", "name": "web-0", "tags": ["env:prod", "web"],
+					"primary_network_interface": [{"primary_ipv4_address": "10.10.10.4"}]}},
+				{"index_key": 1, "attributes": {"id": "vsi-1", "name": "web-1", "tags": ["env:dev"],
+					"primary_network_interface": [{"primary_ipv4_address": "10.20.0.5"}]}}
+			]},
+			{"mode": "managed", "type": "ibm_is_vpc", "name": "vpc", "instances": [{"attributes": {"id": "vpc-1", "name": "Prod-VPC", "tags": ["env:prod"]}}]}
+		]
+	}`
+
+	resources := func() []schematicsv1.QueryableResource {
+		state, err := schematicsv1.DecodeTerraformState([]byte(stateJSON))
+		Expect(err).To(BeNil())
+		return schematicsv1.QueryableResourcesFromState("ws-1", "prod-network", state)
+	}
+
+	Describe(`ResourceQueryBuilder`, func() {
+		It(`Build and print a valid query`, func() {
+			query, err := schematicsService.NewResourceQueryBuilder().
+				WorkspaceName("prod-*").
+				ResourceType("ibm_is_instance").
+				IPAddress("10.10.0.0/16").
+				Select("name", "ipv4_address").
+				Build()
+			Expect(err).To(BeNil())
+			Expect(*query.QueryType).To(Equal(schematicsv1.ResourceQuery_QueryType_Workspaces))
+			Expect(query.QueryCondition).To(HaveLen(3))
+			Expect(*query.QueryCondition[0].Name).To(Equal(schematicsv1.ResourceQueryParam_Name_WorkspaceName))
+			Expect(query.String()).To(Equal(`workspaces where workspace-name = "prod-*" and resource-type = "ibm_is_instance" and ip-address = "10.10.0.0/16" select name, ipv4_address`))
+		})
+		It(`Reject unknown conditions, select fields and malformed values`, func() {
+			_, err := schematicsService.NewResourceQueryBuilder().
+				Where("region", "us-south").
+				IPAddress("10.0.0.300").
+				ResourceName("web-[").
+				Select("name", "cpu").
+				Build()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`unknown condition "region"`))
+			Expect(err.Error()).To(ContainSubstring(`condition ip-address: "10.0.0.300" is neither an IP address nor a CIDR range`))
+			Expect(err.Error()).To(ContainSubstring(`condition resource-name: invalid pattern "web-["`))
+			Expect(err.Error()).To(ContainSubstring(`unknown select field "cpu"`))
+
+			_, err = schematicsService.NewResourceQueryBuilder().Tag("web").Build()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`query_select is required`))
+		})
+		It(`Reject unsupported query types`, func() {
+			err := schematicsv1.ValidateResourceQuery(&schematicsv1.ResourceQuery{
+				QueryType:   core.StringPtr("clusters"),
+				QuerySelect: []string{"name"},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`invalid resource query: unsupported query_type "clusters"`))
+		})
+	})
+
+	Describe(`QueryableResourcesFromState(wID string, workspaceName string, state *TerraformState)`, func() {
+		It(`Return managed resource instances`, func() {
+			list := resources()
+			Expect(list).To(HaveLen(3))
+			Expect(list[0].Address).To(Equal("ibm_is_instance.vsi[0]"))
+			Expect(list[0].Name()).To(Equal("web-0"))
+			Expect(list[0].ID()).To(Equal("vsi-0"))
+			Expect(list[0].Tags()).To(Equal([]string{"env:prod", "web"}))
+			Expect(list[0].IPAddresses()).To(Equal([]string{"10.10.10.4"}))
+			Expect(list[2].WorkspaceName).To(Equal("prod-network"))
+		})
+	})
+
+	Describe(`QueryableResourcesFromTemplateResources(wID string, workspaceName string, templates []TemplateResources)`, func() {
+		It(`Read type and name from the resource entries`, func() {
+			list := schematicsv1.QueryableResourcesFromTemplateResources("ws-2", "dev", []schematicsv1.TemplateResources{{
+				Resources: []interface{}{
+					map[string]interface{}{"resource_type": "ibm_is_vpc", "resource_name": "vpc", "id": "vpc-2"},
+					"not a resource",
+				},
+			}})
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].Address).To(Equal("ibm_is_vpc.vpc"))
+			Expect(list[0].Type).To(Equal("ibm_is_vpc"))
+			Expect(list[0].Name()).To(Equal("vpc"))
+		})
+	})
+
+	Describe(`MatchResourceQuery(query *ResourceQuery, resources []QueryableResource)`, func() {
+		It(`Match all conditions`, func() {
+			query, err := schematicsService.NewResourceQueryBuilder().Tag("env").Select("name").Build()
+			Expect(err).To(BeNil())
+			matches, err := schematicsv1.MatchResourceQuery(query, resources())
+			Expect(err).To(BeNil())
+			Expect(matches).To(HaveLen(3))
+
+			query, _ = schematicsService.NewResourceQueryBuilder().Tag("env:prod").ResourceName("prod-*").Select("name").Build()
+			matches, err = schematicsv1.MatchResourceQuery(query, resources())
+			Expect(err).To(BeNil())
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Address).To(Equal("ibm_is_vpc.vpc"))
+
+			query, _ = schematicsService.NewResourceQueryBuilder().IPAddress("10.20.0.5").Select("name").Build()
+			matches, _ = schematicsv1.MatchResourceQuery(query, resources())
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Name()).To(Equal("web-1"))
+
+			query, _ = schematicsService.NewResourceQueryBuilder().WorkspaceID("ws-2").Select("name").Build()
+			matches, _ = schematicsv1.MatchResourceQuery(query, resources())
+			Expect(matches).To(BeEmpty())
+		})
+	})
+
+	Describe(`PreviewResourceQuery(query *ResourceQuery, resources []QueryableResource)`, func() {
+		It(`Return the selected fields of the matching resources`, func() {
+			query, err := schematicsService.NewResourceQueryBuilder().
+				ResourceType("ibm_is_instance").
+				IPAddress("10.10.0.0/16").
+				Select("name", "ipv4_address", "tags").
+				Build()
+			Expect(err).To(BeNil())
+			preview, err := schematicsv1.PreviewResourceQuery(query, resources())
+			Expect(err).To(BeNil())
+			Expect(preview.QueryType).To(Equal(query.QueryType))
+			Expect(preview.QueryOutput).To(Equal([]schematicsv1.ResourceQueryResponseRecordResponseItemQueryOutputItem{
+				{Name: core.StringPtr("name"), Value: core.StringPtr("web-0")},
+				{Name: core.StringPtr("ipv4_address"), Value: core.StringPtr("10.10.10.4")},
+				{Name: core.StringPtr("tags"), Value: core.StringPtr("env:prod,web")},
+			}))
+		})
+		It(`Return an error for an invalid query`, func() {
+			_, err := schematicsv1.PreviewResourceQuery(&schematicsv1.ResourceQuery{}, resources())
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
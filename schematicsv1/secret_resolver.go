@@ -0,0 +1,518 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Constants associated with the SecretReference.Scheme property.
+// The scheme of the secret reference.
+const (
+	SecretReference_Scheme_Env    = "env"
+	SecretReference_Scheme_File   = "file"
+	SecretReference_Scheme_Secret = "secret"
+)
+
+// SecretReference : A reference to a secret, such as 'secret://vault/path#key', 'env://NAME' or 'file://path#key'.
+type SecretReference struct {
+	// The reference as written.
+	Raw string `json:"raw"`
+
+	// The scheme of the reference.
+	Scheme string `json:"scheme"`
+
+	// The secret provider of a 'secret' reference, such as 'vault'.
+	Provider string `json:"provider,omitempty"`
+
+	// The path of the secret: the variable name of an 'env' reference, the file of a 'file' reference, or the path
+	// within the provider of a 'secret' reference.
+	Path string `json:"path"`
+
+	// The key within the secret, from the fragment of the reference.
+	Key string `json:"key,omitempty"`
+}
+
+// String returns the reference as written.
+func (reference *SecretReference) String() string {
+	return reference.Raw
+}
+
+// ParseSecretReference parses a value that may be a secret reference. Nil is returned without error when the value is
+// not a reference, that is when it does not start with 'env://', 'file://' or 'secret://'.
+func ParseSecretReference(value string) (*SecretReference, error) {
+	separator := strings.Index(value, "://")
+	if separator < 0 {
+		return nil, nil
+	}
+	scheme := strings.ToLower(value[:separator])
+	if scheme != SecretReference_Scheme_Env && scheme != SecretReference_Scheme_File && scheme != SecretReference_Scheme_Secret {
+		return nil, nil
+	}
+
+	reference := &SecretReference{Raw: value, Scheme: scheme}
+	rest := value[separator+len("://"):]
+	if hash := strings.LastIndex(rest, "#"); hash >= 0 {
+		rest, reference.Key = rest[:hash], rest[hash+1:]
+	}
+	if scheme == SecretReference_Scheme_Secret {
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return nil, fmt.Errorf("invalid secret reference %s: expected secret://provider/path", value)
+		}
+		reference.Provider, rest = rest[:slash], rest[slash+1:]
+	}
+	path, err := url.PathUnescape(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret reference %s: %s", value, err.Error())
+	}
+	reference.Path = path
+	if reference.Path == "" {
+		return nil, fmt.Errorf("invalid secret reference %s: the path is empty", value)
+	}
+	return reference, nil
+}
+
+// SecretResolver resolves secret references to their values.
+type SecretResolver interface {
+	ResolveSecret(reference *SecretReference) (string, error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface, typically to call a secret store client.
+type SecretResolverFunc func(reference *SecretReference) (string, error)
+
+// ResolveSecret calls the function.
+func (resolve SecretResolverFunc) ResolveSecret(reference *SecretReference) (string, error) {
+	return resolve(reference)
+}
+
+// EnvSecretResolver resolves 'env://NAME' references from the environment.
+type EnvSecretResolver struct {
+	// Looks up an environment variable. Defaults to os.LookupEnv.
+	LookupEnv func(name string) (string, bool)
+}
+
+// ResolveSecret returns the value of the environment variable. It is an error when the variable is not set.
+func (resolver *EnvSecretResolver) ResolveSecret(reference *SecretReference) (string, error) {
+	lookupEnv := resolver.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	value, found := lookupEnv(reference.Path)
+	if !found {
+		return "", fmt.Errorf("environment variable %s is not set", reference.Path)
+	}
+	return secretKeyValue(reference, []byte(value))
+}
+
+// FileSecretResolver resolves 'file://path' references from local files. Without key the content of the file, less
+// its trailing line break, is the secret; with a key the file is decoded as a JSON or YAML object and the secret is
+// the value of the key. Only the files of the base directory can be read, unless AllowOutsideBaseDirectory is set.
+type FileSecretResolver struct {
+	// The directory that relative paths are resolved against. Defaults to the working directory.
+	BaseDirectory string
+
+	// Whether references may name files outside the base directory, with an absolute path or with '..'.
+	AllowOutsideBaseDirectory bool
+
+	// Decrypts the content of an encrypted file. The content is used as is when not set.
+	Decrypt func(path string, content []byte) ([]byte, error)
+}
+
+// ResolveSecret reads, decrypts and decodes the file.
+func (resolver *FileSecretResolver) ResolveSecret(reference *SecretReference) (string, error) {
+	baseDirectory := resolver.BaseDirectory
+	if baseDirectory == "" {
+		baseDirectory = "."
+	}
+	path := filepath.FromSlash(reference.Path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDirectory, path)
+	}
+	if !resolver.AllowOutsideBaseDirectory {
+		base, err := filepath.Abs(baseDirectory)
+		if err != nil {
+			return "", err
+		}
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		relative, err := filepath.Rel(base, absolute)
+		if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is outside the base directory of the file secret resolver", reference.Path)
+		}
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if resolver.Decrypt != nil {
+		content, err = resolver.Decrypt(path, content)
+		if err != nil {
+			return "", fmt.Errorf("decrypting %s: %s", path, err.Error())
+		}
+	}
+	return secretKeyValue(reference, content)
+}
+
+// secretKeyValue returns the content of a secret, or the value of the key of the reference in the content decoded as a
+// JSON or YAML object.
+func secretKeyValue(reference *SecretReference, content []byte) (string, error) {
+	if reference.Key == "" {
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	var object map[string]interface{}
+	if err := yaml.Unmarshal(content, &object); err != nil {
+		return "", fmt.Errorf("the secret is not a JSON or YAML object: %s", err.Error())
+	}
+	value, found := object[reference.Key]
+	if !found {
+		return "", fmt.Errorf("the secret has no key %s", reference.Key)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// SchemeSecretResolver resolves secret references by scheme: 'env' references with Env, 'file' references with File
+// and 'secret' references with the resolver registered for their provider.
+type SchemeSecretResolver struct {
+	// Resolves 'env' references. Defaults to an EnvSecretResolver.
+	Env SecretResolver
+
+	// Resolves 'file' references. Defaults to a FileSecretResolver.
+	File SecretResolver
+
+	// Resolves 'secret' references by provider, such as 'vault'.
+	Providers map[string]SecretResolver
+}
+
+// NewSchemeSecretResolver : Instantiate SchemeSecretResolver with the default env and file resolvers
+func NewSchemeSecretResolver() *SchemeSecretResolver {
+	return &SchemeSecretResolver{
+		Env:       &EnvSecretResolver{},
+		File:      &FileSecretResolver{},
+		Providers: map[string]SecretResolver{},
+	}
+}
+
+// SetProvider : Allow user to set the resolver of a secret provider
+func (resolver *SchemeSecretResolver) SetProvider(provider string, providerResolver SecretResolver) *SchemeSecretResolver {
+	if resolver.Providers == nil {
+		resolver.Providers = map[string]SecretResolver{}
+	}
+	resolver.Providers[provider] = providerResolver
+	return resolver
+}
+
+// ResolveSecret resolves the reference with the resolver of its scheme or provider.
+func (resolver *SchemeSecretResolver) ResolveSecret(reference *SecretReference) (string, error) {
+	var schemeResolver SecretResolver
+	switch reference.Scheme {
+	case SecretReference_Scheme_Env:
+		schemeResolver = resolver.Env
+		if schemeResolver == nil {
+			schemeResolver = &EnvSecretResolver{}
+		}
+	case SecretReference_Scheme_File:
+		schemeResolver = resolver.File
+		if schemeResolver == nil {
+			schemeResolver = &FileSecretResolver{}
+		}
+	case SecretReference_Scheme_Secret:
+		schemeResolver = resolver.Providers[reference.Provider]
+		if schemeResolver == nil {
+			return "", fmt.Errorf("no resolver for secret provider %s", reference.Provider)
+		}
+	default:
+		return "", fmt.Errorf("unsupported secret reference scheme %s", reference.Scheme)
+	}
+	return schemeResolver.ResolveSecret(reference)
+}
+
+// SecretResolutionError is returned when a secret reference can not be resolved. It names the reference, never the
+// secret.
+type SecretResolutionError struct {
+	// The reference as written.
+	Reference string
+
+	// The request field or header that held the reference.
+	Field string
+
+	// The resolver error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SecretResolutionError) Error() string {
+	return fmt.Sprintf("resolving secret reference %s in %s: %s", e.Reference, e.Field, e.Err.Error())
+}
+
+// Unwrap returns the resolver error.
+func (e *SecretResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveSecretValue returns the secret a value refers to, or the value itself when it is not a secret reference.
+func ResolveSecretValue(resolver SecretResolver, value string) (string, error) {
+	reference, err := ParseSecretReference(value)
+	if err != nil || reference == nil {
+		return value, err
+	}
+	return resolver.ResolveSecret(reference)
+}
+
+// resolveSecretString returns the secret a value refers to. The value itself is returned when it is not a reference.
+func resolveSecretString(resolver SecretResolver, field string, value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	reference, err := ParseSecretReference(*value)
+	if err == nil && reference == nil {
+		return value, nil
+	}
+	var secret string
+	if err == nil {
+		secret, err = resolver.ResolveSecret(reference)
+	}
+	if err != nil {
+		return nil, &SecretResolutionError{Reference: *value, Field: field, Err: err}
+	}
+	return &secret, nil
+}
+
+// resolveVariableDataSecrets returns a copy of the variables with the secret references of their values resolved.
+func resolveVariableDataSecrets(resolver SecretResolver, field string, variables []VariableData) ([]VariableData, error) {
+	if variables == nil {
+		return nil, nil
+	}
+	resolved := make([]VariableData, len(variables))
+	for i, variable := range variables {
+		value, err := resolveSecretString(resolver, fmt.Sprintf("%s[%d].value", field, i), variable.Value)
+		if err != nil {
+			return nil, err
+		}
+		variable.Value = value
+		resolved[i] = variable
+	}
+	return resolved, nil
+}
+
+// resolveWorkspaceVariableSecrets returns a copy of the variables with the secret references of their values
+// resolved.
+func resolveWorkspaceVariableSecrets(resolver SecretResolver, field string, variables []WorkspaceVariableRequest) ([]WorkspaceVariableRequest, error) {
+	if variables == nil {
+		return nil, nil
+	}
+	resolved := make([]WorkspaceVariableRequest, len(variables))
+	for i, variable := range variables {
+		value, err := resolveSecretString(resolver, fmt.Sprintf("%s[%d].value", field, i), variable.Value)
+		if err != nil {
+			return nil, err
+		}
+		variable.Value = value
+		resolved[i] = variable
+	}
+	return resolved, nil
+}
+
+// resolveEnvValueSecrets returns a copy of the environment values with the secret references of their values
+// resolved. Environment values that are not name and value objects are kept as is.
+func resolveEnvValueSecrets(resolver SecretResolver, field string, envValues []interface{}) ([]interface{}, error) {
+	if envValues == nil {
+		return nil, nil
+	}
+	resolved := make([]interface{}, len(envValues))
+	for i, envValue := range envValues {
+		resolved[i] = envValue
+		env, ok := envValue.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, ok := env["value"].(string)
+		if !ok {
+			continue
+		}
+		secret, err := resolveSecretString(resolver, fmt.Sprintf("%s[%d].value", field, i), &value)
+		if err != nil {
+			return nil, err
+		}
+		copied := make(map[string]interface{}, len(env))
+		for key, entry := range env {
+			copied[key] = entry
+		}
+		copied["value"] = *secret
+		resolved[i] = copied
+	}
+	return resolved, nil
+}
+
+// validateSecretResolution checks the options and the resolver of the ...WithSecrets methods.
+func validateSecretResolution(options interface{}, name string, resolver SecretResolver) error {
+	if err := core.ValidateNotNil(options, name+" cannot be nil"); err != nil {
+		return err
+	}
+	return core.ValidateNotNil(resolver, "resolver cannot be nil")
+}
+
+// CreateWorkspaceWithSecrets : Create a workspace with secret references resolved
+// Resolve the secret references in the X-Github-token header and in the variable and environment values of a copy of
+// the options with resolver, just before the workspace is created from the copy. The options keep the references.
+// Only the options built by the caller are resolved: values that the SDK reads back from the service, for example in
+// CloneWorkspace, are sent as they are.
+func (schematics *SchematicsV1) CreateWorkspaceWithSecrets(createWorkspaceOptions *CreateWorkspaceOptions, resolver SecretResolver) (result *WorkspaceResponse, response *core.DetailedResponse, err error) {
+	err = validateSecretResolution(createWorkspaceOptions, "createWorkspaceOptions", resolver)
+	if err != nil {
+		return
+	}
+	resolved := *createWorkspaceOptions
+	resolved.XGithubToken, err = resolveSecretString(resolver, "X-Github-token", createWorkspaceOptions.XGithubToken)
+	if err != nil {
+		return
+	}
+	if createWorkspaceOptions.TemplateData != nil {
+		resolved.TemplateData = make([]TemplateSourceDataRequest, len(createWorkspaceOptions.TemplateData))
+		for i, templateData := range createWorkspaceOptions.TemplateData {
+			prefix := fmt.Sprintf("template_data[%d].", i)
+			templateData.EnvValues, err = resolveEnvValueSecrets(resolver, prefix+"env_values", templateData.EnvValues)
+			if err != nil {
+				return
+			}
+			templateData.Variablestore, err = resolveWorkspaceVariableSecrets(resolver, prefix+"variablestore", templateData.Variablestore)
+			if err != nil {
+				return
+			}
+			resolved.TemplateData[i] = templateData
+		}
+	}
+	return schematics.CreateWorkspace(&resolved)
+}
+
+// ReplaceWorkspaceInputsWithSecrets : Replace workspace inputs with secret references resolved
+// Resolve the secret references in the variable and environment values of a copy of the options with resolver, just
+// before the inputs are replaced with the copy. The options keep the references.
+func (schematics *SchematicsV1) ReplaceWorkspaceInputsWithSecrets(replaceWorkspaceInputsOptions *ReplaceWorkspaceInputsOptions, resolver SecretResolver) (result *UserValues, response *core.DetailedResponse, err error) {
+	err = validateSecretResolution(replaceWorkspaceInputsOptions, "replaceWorkspaceInputsOptions", resolver)
+	if err != nil {
+		return
+	}
+	resolved := *replaceWorkspaceInputsOptions
+	resolved.EnvValues, err = resolveEnvValueSecrets(resolver, "env_values", replaceWorkspaceInputsOptions.EnvValues)
+	if err != nil {
+		return
+	}
+	resolved.Variablestore, err = resolveWorkspaceVariableSecrets(resolver, "variablestore", replaceWorkspaceInputsOptions.Variablestore)
+	if err != nil {
+		return
+	}
+	return schematics.ReplaceWorkspaceInputs(&resolved)
+}
+
+// CreateActionWithSecrets : Create an action with secret references resolved
+// Resolve the secret references in the X-Github-token header, the Git token of the source and the values of the
+// bastion credential, credentials, inputs and settings of a copy of the options with resolver, just before the
+// action is created from the copy. The options keep the references.
+func (schematics *SchematicsV1) CreateActionWithSecrets(createActionOptions *CreateActionOptions, resolver SecretResolver) (result *Action, response *core.DetailedResponse, err error) {
+	err = validateSecretResolution(createActionOptions, "createActionOptions", resolver)
+	if err != nil {
+		return
+	}
+	resolved := *createActionOptions
+	resolved.XGithubToken, err = resolveSecretString(resolver, "X-Github-token", createActionOptions.XGithubToken)
+	if err != nil {
+		return
+	}
+	if createActionOptions.Source != nil && createActionOptions.Source.Git != nil {
+		source, git := *createActionOptions.Source, *createActionOptions.Source.Git
+		git.GitToken, err = resolveSecretString(resolver, "source.git.git_token", git.GitToken)
+		if err != nil {
+			return
+		}
+		source.Git = &git
+		resolved.Source = &source
+	}
+	if createActionOptions.BastionCredential != nil {
+		credential := *createActionOptions.BastionCredential
+		credential.Value, err = resolveSecretString(resolver, "bastion_credential.value", credential.Value)
+		if err != nil {
+			return
+		}
+		resolved.BastionCredential = &credential
+	}
+	for _, variables := range []struct {
+		field    string
+		from     []VariableData
+		resolved *[]VariableData
+	}{
+		{"credentials", createActionOptions.Credentials, &resolved.Credentials},
+		{"inputs", createActionOptions.Inputs, &resolved.Inputs},
+		{"settings", createActionOptions.Settings, &resolved.Settings},
+	} {
+		*variables.resolved, err = resolveVariableDataSecrets(resolver, variables.field, variables.from)
+		if err != nil {
+			return
+		}
+	}
+	return schematics.CreateAction(&resolved)
+}
+
+// CreateJobWithSecrets : Create a job with secret references resolved
+// Resolve the secret references in the refresh_token header and in the values of the inputs and settings of a copy of
+// the options, and of its action job data, with resolver, just before the job is created from the copy. The options
+// keep the references.
+func (schematics *SchematicsV1) CreateJobWithSecrets(createJobOptions *CreateJobOptions, resolver SecretResolver) (result *Job, response *core.DetailedResponse, err error) {
+	err = validateSecretResolution(createJobOptions, "createJobOptions", resolver)
+	if err != nil {
+		return
+	}
+	resolved := *createJobOptions
+	resolved.RefreshToken, err = resolveSecretString(resolver, "refresh_token", createJobOptions.RefreshToken)
+	if err != nil {
+		return
+	}
+	resolved.Inputs, err = resolveVariableDataSecrets(resolver, "inputs", createJobOptions.Inputs)
+	if err != nil {
+		return
+	}
+	resolved.Settings, err = resolveVariableDataSecrets(resolver, "settings", createJobOptions.Settings)
+	if err != nil {
+		return
+	}
+	if createJobOptions.Data != nil && createJobOptions.Data.ActionJobData != nil {
+		data, actionJobData := *createJobOptions.Data, *createJobOptions.Data.ActionJobData
+		actionJobData.Inputs, err = resolveVariableDataSecrets(resolver, "data.action_job_data.inputs", actionJobData.Inputs)
+		if err != nil {
+			return
+		}
+		actionJobData.Settings, err = resolveVariableDataSecrets(resolver, "data.action_job_data.settings", actionJobData.Settings)
+		if err != nil {
+			return
+		}
+		data.ActionJobData = &actionJobData
+		resolved.Data = &data
+	}
+	return schematics.CreateJob(&resolved)
+}
@@ -0,0 +1,332 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe(`Secret resolvers`, func() {
+	vault := schematicsv1.SecretResolverFunc(func(reference *schematicsv1.SecretReference) (string, error) {
+		if reference.Path == "kv/app" && reference.Key == "api_key" {
+			return "vault-api-key", nil
+		}
+		return "", errors.New("secret not found")
+	})
+	lookupEnv := func(name string) (string, bool) {
+		if name == "GITHUB_TOKEN" {
+			return "ghp_from_env", true
+		}
+		return "", false
+	}
+
+	Describe(`ParseSecretReference(value string)`, func() {
+		It(`Parse references`, func() {
+			reference, err := schematicsv1.ParseSecretReference("secret://vault/kv/app#api_key")
+			Expect(err).To(BeNil())
+			Expect(*reference).To(Equal(schematicsv1.SecretReference{
+				Raw: "secret://vault/kv/app#api_key", Scheme: "secret", Provider: "vault", Path: "kv/app", Key: "api_key",
+			}))
+			reference, err = schematicsv1.ParseSecretReference("env://GITHUB_TOKEN")
+			Expect(err).To(BeNil())
+			Expect(reference.Path).To(Equal("GITHUB_TOKEN"))
+			reference, err = schematicsv1.ParseSecretReference("file:///etc/secrets/my%20token")
+			Expect(err).To(BeNil())
+			Expect(reference.Path).To(Equal("/etc/secrets/my token"))
+		})
+		It(`Ignore plain values and reject invalid references`, func() {
+			for _, value := range []string{"plain", "https://github.com/org/repo", ""} {
+				reference, err := schematicsv1.ParseSecretReference(value)
+				Expect(err).To(BeNil())
+				Expect(reference).To(BeNil())
+			}
+			_, err := schematicsv1.ParseSecretReference("secret://vault")
+			Expect(err).To(MatchError("invalid secret reference secret://vault: expected secret://provider/path"))
+			_, err = schematicsv1.ParseSecretReference("env://")
+			Expect(err).To(MatchError("invalid secret reference env://: the path is empty"))
+		})
+	})
+
+	Describe(`Resolvers`, func() {
+		var directory string
+
+		BeforeEach(func() {
+			var err error
+			directory, err = ioutil.TempDir("", "secrets")
+			Expect(err).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(directory, "token"), []byte("file-token\n"), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(directory, "app.yaml"), []byte("password: p4ss\nport: 5432\n"), 0600)).To(Succeed())
+			encrypted := base64.StdEncoding.EncodeToString([]byte(`{"password": "decrypted"}`))
+			Expect(ioutil.WriteFile(filepath.Join(directory, "app.json.enc"), []byte(encrypted), 0600)).To(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(directory)
+		})
+		It(`Resolve env, file and provider references`, func() {
+			resolver := schematicsv1.NewSchemeSecretResolver().SetProvider("vault", vault)
+			resolver.Env = &schematicsv1.EnvSecretResolver{LookupEnv: lookupEnv}
+			resolver.File = &schematicsv1.FileSecretResolver{
+				BaseDirectory: directory,
+				Decrypt: func(path string, content []byte) ([]byte, error) {
+					if filepath.Ext(path) != ".enc" {
+						return content, nil
+					}
+					return base64.StdEncoding.DecodeString(string(content))
+				},
+			}
+
+			for value, expected := range map[string]string{
+				"env://GITHUB_TOKEN":             "ghp_from_env",
+				"file://token":                   "file-token",
+				"file://app.yaml#password":       "p4ss",
+				"file://app.yaml#port":           "5432",
+				"file://app.json.enc#password":   "decrypted",
+				"secret://vault/kv/app#api_key":  "vault-api-key",
+				"not a reference":                "not a reference",
+				"https://example.com/#not-token": "https://example.com/#not-token",
+			} {
+				resolved, err := schematicsv1.ResolveSecretValue(resolver, value)
+				Expect(err).To(BeNil())
+				Expect(resolved).To(Equal(expected), value)
+			}
+
+			_, err := schematicsv1.ResolveSecretValue(resolver, "env://MISSING")
+			Expect(err).To(MatchError("environment variable MISSING is not set"))
+			_, err = schematicsv1.ResolveSecretValue(resolver, "file://app.yaml#user")
+			Expect(err).To(MatchError("the secret has no key user"))
+			_, err = schematicsv1.ResolveSecretValue(resolver, "secret://aws/app#key")
+			Expect(err).To(MatchError("no resolver for secret provider aws"))
+		})
+		It(`Keep file references within the base directory`, func() {
+			resolver := &schematicsv1.FileSecretResolver{BaseDirectory: filepath.Join(directory, "nested")}
+			Expect(os.Mkdir(resolver.BaseDirectory, 0700)).To(Succeed())
+			for _, value := range []string{"file://../token", "file://" + filepath.ToSlash(filepath.Join(directory, "token"))} {
+				_, err := schematicsv1.ResolveSecretValue(resolver, value)
+				Expect(err).To(MatchError(ContainSubstring("is outside the base directory")), value)
+			}
+
+			resolver.AllowOutsideBaseDirectory = true
+			resolved, err := schematicsv1.ResolveSecretValue(resolver, "file://../token")
+			Expect(err).To(BeNil())
+			Expect(resolved).To(Equal("file-token"))
+
+			// Without base directory, files outside the working directory are rejected.
+			_, err = schematicsv1.ResolveSecretValue(&schematicsv1.FileSecretResolver{}, "file:///etc/passwd")
+			Expect(err).To(MatchError(ContainSubstring("is outside the base directory")))
+		})
+	})
+
+	Context(`Using mock server endpoint`, func() {
+		var testServer *httptest.Server
+		var requests int
+		var githubToken string
+		var variableValue interface{}
+		var envValue interface{}
+
+		BeforeEach(func() {
+			requests = 0
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				requests++
+				res.Header().Set("Content-type", "application/json")
+				var body map[string]interface{}
+				if req.Method != "GET" {
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				}
+				switch {
+				case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspaces/ws-src":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "ws-src", "name": "src", "template_data": [{"id": "t-1", "type": "terraform_v0.12"}]}`)
+				case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspaces/ws-src/templates/values":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"template_data": [{"id": "t-1", "type": "terraform_v0.12", "env_values": [{"name": "TOKEN", "value": "env://GITHUB_TOKEN"}], "variablestore": [{"name": "api_key", "value": "env://GITHUB_TOKEN"}]}]}`)
+				case req.Method == "POST" && req.URL.EscapedPath() == "/v1/workspaces":
+					githubToken = req.Header.Get("X-Github-token")
+					templateData := body["template_data"].([]interface{})[0].(map[string]interface{})
+					variableValue = templateData["variablestore"].([]interface{})[0].(map[string]interface{})["value"]
+					if envValues, ok := templateData["env_values"].([]interface{}); ok {
+						envValue = envValues[0].(map[string]interface{})["value"]
+					}
+					res.WriteHeader(201)
+					fmt.Fprintf(res, "%s", `{"id": "ws-1"}`)
+				case req.Method == "POST" && req.URL.EscapedPath() == "/v2/actions":
+					git := body["source"].(map[string]interface{})["git"].(map[string]interface{})
+					Expect(git["git_token"]).To(Equal("ghp_from_env"))
+					Expect(git["git_repo_url"]).To(Equal("https://github.com/org/repo"))
+					Expect(body["inputs"].([]interface{})[0]).To(HaveKeyWithValue("value", "vault-api-key"))
+					res.WriteHeader(201)
+					fmt.Fprintf(res, "%s", `{"id": "act-1"}`)
+				case req.Method == "POST" && req.URL.EscapedPath() == "/v2/jobs":
+					Expect(req.Header.Get("refresh_token")).To(Equal("ghp_from_env"))
+					inputs := body["data"].(map[string]interface{})["action_job_data"].(map[string]interface{})["inputs"]
+					Expect(inputs.([]interface{})[0]).To(HaveKeyWithValue("value", "vault-api-key"))
+					res.WriteHeader(201)
+					fmt.Fprintf(res, "%s", `{"id": "job-1"}`)
+				case req.Method == "PUT" && req.URL.EscapedPath() == "/v1/workspaces/ws-1/template_data/t-1/values":
+					variableValue = body["variablestore"].([]interface{})[0].(map[string]interface{})["value"]
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		newService := func() *schematicsv1.SchematicsV1 {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+			return schematicsService
+		}
+		newResolver := func() schematicsv1.SecretResolver {
+			resolver := schematicsv1.NewSchemeSecretResolver().SetProvider("vault", vault)
+			resolver.Env = &schematicsv1.EnvSecretResolver{LookupEnv: lookupEnv}
+			return resolver
+		}
+		newCreateWorkspaceOptions := func(schematicsService *schematicsv1.SchematicsV1, value string) *schematicsv1.CreateWorkspaceOptions {
+			options := schematicsService.NewCreateWorkspaceOptions()
+			options.SetXGithubToken("env://GITHUB_TOKEN")
+			options.SetTemplateData([]schematicsv1.TemplateSourceDataRequest{{
+				EnvValues: []interface{}{map[string]interface{}{"name": "TOKEN", "value": "env://GITHUB_TOKEN", "secure": true}},
+				Variablestore: []schematicsv1.WorkspaceVariableRequest{{
+					Name:   core.StringPtr("api_key"),
+					Secure: core.BoolPtr(true),
+					Value:  core.StringPtr(value),
+				}},
+			}})
+			return options
+		}
+		It(`Invoke CreateWorkspaceWithSecrets successfully`, func() {
+			schematicsService := newService()
+
+			// Invoke operation with nil options model or resolver (negative test)
+			result, response, operationErr := schematicsService.CreateWorkspaceWithSecrets(nil, newResolver())
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+			_, _, operationErr = schematicsService.CreateWorkspaceWithSecrets(schematicsService.NewCreateWorkspaceOptions(), nil)
+			Expect(operationErr).To(MatchError("resolver cannot be nil"))
+
+			options := newCreateWorkspaceOptions(schematicsService, "secret://vault/kv/app#api_key")
+			result, _, operationErr = schematicsService.CreateWorkspaceWithSecrets(options, newResolver())
+			Expect(operationErr).To(BeNil())
+			Expect(*result.ID).To(Equal("ws-1"))
+			Expect(githubToken).To(Equal("ghp_from_env"))
+			Expect(variableValue).To(Equal("vault-api-key"))
+			Expect(envValue).To(Equal("ghp_from_env"))
+			Expect(*options.TemplateData[0].Variablestore[0].Value).To(Equal("secret://vault/kv/app#api_key"))
+			Expect(options.TemplateData[0].EnvValues[0]).To(HaveKeyWithValue("value", "env://GITHUB_TOKEN"))
+			Expect(*options.XGithubToken).To(Equal("env://GITHUB_TOKEN"))
+		})
+		It(`Invoke ReplaceWorkspaceInputsWithSecrets successfully`, func() {
+			schematicsService := newService()
+			options := schematicsService.NewReplaceWorkspaceInputsOptions("ws-1", "t-1").
+				SetVariablestore([]schematicsv1.WorkspaceVariableRequest{{Name: core.StringPtr("api_key"), Value: core.StringPtr("secret://vault/kv/app#api_key")}})
+			_, _, operationErr := schematicsService.ReplaceWorkspaceInputsWithSecrets(options, newResolver())
+			Expect(operationErr).To(BeNil())
+			Expect(variableValue).To(Equal("vault-api-key"))
+			Expect(*options.Variablestore[0].Value).To(Equal("secret://vault/kv/app#api_key"))
+		})
+		It(`Invoke CreateActionWithSecrets and CreateJobWithSecrets successfully`, func() {
+			schematicsService := newService()
+			actionOptions := schematicsService.NewCreateActionOptions()
+			actionOptions.SetName("action")
+			actionOptions.SetSource(&schematicsv1.ExternalSource{
+				SourceType: core.StringPtr(schematicsv1.ExternalSource_SourceType_GitHub),
+				Git:        &schematicsv1.ExternalSourceGit{GitRepoURL: core.StringPtr("https://github.com/org/repo"), GitToken: core.StringPtr("env://GITHUB_TOKEN")},
+			})
+			actionOptions.SetInputs([]schematicsv1.VariableData{{Name: core.StringPtr("api_key"), Value: core.StringPtr("secret://vault/kv/app#api_key")}})
+			action, _, operationErr := schematicsService.CreateActionWithSecrets(actionOptions, newResolver())
+			Expect(operationErr).To(BeNil())
+			Expect(*action.ID).To(Equal("act-1"))
+			Expect(*actionOptions.Source.Git.GitToken).To(Equal("env://GITHUB_TOKEN"))
+			Expect(*actionOptions.Inputs[0].Value).To(Equal("secret://vault/kv/app#api_key"))
+
+			jobOptions := schematicsService.NewCreateJobOptions("env://GITHUB_TOKEN")
+			jobOptions.SetData(&schematicsv1.JobData{
+				JobType: core.StringPtr("repo_download_job"),
+				ActionJobData: &schematicsv1.JobDataAction{
+					Inputs: []schematicsv1.VariableData{{Name: core.StringPtr("api_key"), Value: core.StringPtr("secret://vault/kv/app#api_key")}},
+				},
+			})
+			job, _, operationErr := schematicsService.CreateJobWithSecrets(jobOptions, newResolver())
+			Expect(operationErr).To(BeNil())
+			Expect(*job.ID).To(Equal("job-1"))
+			Expect(*jobOptions.RefreshToken).To(Equal("env://GITHUB_TOKEN"))
+			Expect(*jobOptions.Data.ActionJobData.Inputs[0].Value).To(Equal("secret://vault/kv/app#api_key"))
+		})
+		It(`Fail before sending unresolvable references`, func() {
+			schematicsService := newService()
+			options := newCreateWorkspaceOptions(schematicsService, "secret://vault/kv/other#api_key")
+			_, _, operationErr := schematicsService.CreateWorkspaceWithSecrets(options, newResolver())
+			var resolutionErr *schematicsv1.SecretResolutionError
+			Expect(errors.As(operationErr, &resolutionErr)).To(BeTrue())
+			Expect(resolutionErr.Field).To(Equal("template_data[0].variablestore[0].value"))
+			Expect(resolutionErr.Error()).To(Equal("resolving secret reference secret://vault/kv/other#api_key in template_data[0].variablestore[0].value: secret not found"))
+			Expect(requests).To(Equal(0))
+
+			// Without a resolver, references are sent as they are.
+			_, _, operationErr = schematicsService.CreateWorkspace(options)
+			Expect(operationErr).To(BeNil())
+			Expect(requests).To(Equal(1))
+			Expect(githubToken).To(Equal("env://GITHUB_TOKEN"))
+			Expect(variableValue).To(Equal("secret://vault/kv/other#api_key"))
+		})
+		It(`Leave references read from the service unresolved`, func() {
+			schematicsService := newService()
+			result, _, operationErr := schematicsService.CloneWorkspace(schematicsService.NewCloneWorkspaceOptions("ws-src"))
+			Expect(operationErr).To(BeNil())
+			Expect(*result.Workspace.ID).To(Equal("ws-1"))
+			Expect(variableValue).To(Equal("env://GITHUB_TOKEN"))
+			Expect(envValue).To(Equal("env://GITHUB_TOKEN"))
+		})
+		It(`Redact resolved secrets in the request log`, func() {
+			previousLogger := core.GetLogger()
+			defer core.SetLogger(previousLogger)
+			output := &bytes.Buffer{}
+			core.SetLogger(core.NewLogger(core.LevelDebug, log.New(output, "", 0)))
+
+			schematicsService := newService()
+			schematicsService.EnableRequestLogging()
+			options := newCreateWorkspaceOptions(schematicsService, "secret://vault/kv/app#api_key")
+			_, _, operationErr := schematicsService.CreateWorkspaceWithSecrets(options, newResolver())
+			Expect(operationErr).To(BeNil())
+			Expect(variableValue).To(Equal("vault-api-key"))
+			Expect(output.String()).ToNot(ContainSubstring("vault-api-key"))
+			Expect(output.String()).ToNot(ContainSubstring("ghp_from_env"))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
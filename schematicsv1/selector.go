@@ -0,0 +1,542 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/go-openapi/strfmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// listPageSize is the number of items requested per page when a helper lists all items of a collection.
+const listPageSize = 100
+
+// Selector : Selects workspaces, actions or jobs on the client side. All the criteria that are set must match; a
+// criterion with several values matches if any of the values matches. String comparisons ignore case.
+type Selector struct {
+	// Tags that the item must have. A tag in the form key:value must be present as is, a tag without a colon matches
+	// both the tag itself and any key:value tag with that key.
+	Tags []string `json:"tags,omitempty"`
+
+	// Groups of alternative tags. The item must have at least one tag of each group, with the same matching rules as
+	// Tags.
+	TagAlternatives [][]string `json:"tag_alternatives,omitempty"`
+
+	// A glob pattern, as accepted by path.Match, that the name must match.
+	NamePattern *string `json:"name_pattern,omitempty"`
+
+	// The resource group of the item.
+	ResourceGroup *string `json:"resource_group,omitempty"`
+
+	// The status of the item: the workspace status, the action state or the action job status code.
+	Statuses []string `json:"statuses,omitempty"`
+
+	// The location of the item.
+	Locations []string `json:"locations,omitempty"`
+
+	// The template type of the workspace, for example terraform_v0.12. Actions and jobs have no template type and
+	// never match a selector that sets it.
+	TemplateTypes []string `json:"template_types,omitempty"`
+
+	// The item must have been created, or submitted for jobs, at or after this time.
+	CreatedAfter *strfmt.DateTime `json:"created_after,omitempty"`
+
+	// The item must have been created, or submitted for jobs, before this time.
+	CreatedBefore *strfmt.DateTime `json:"created_before,omitempty"`
+
+	// The item must have been updated at or after this time.
+	UpdatedAfter *strfmt.DateTime `json:"updated_after,omitempty"`
+
+	// The item must have been updated before this time.
+	UpdatedBefore *strfmt.DateTime `json:"updated_before,omitempty"`
+}
+
+// ParseSelector parses a selector expression. An expression is a comma separated list of key=value terms; the value
+// of a tag, status, location or type term may list alternatives separated by |. The supported keys are tag (may be
+// repeated), name, resource_group, status, location, type, created_after, created_before, updated_after and
+// updated_before. Times are RFC 3339 timestamps or dates in the form 2006-01-02.
+//
+// For example: tag=env:prod,tag=team:a|team:b,status=ACTIVE|INACTIVE,resource_group=default,name=app-*
+func ParseSelector(expression string) (selector *Selector, err error) {
+	selector = &Selector{}
+	for _, term := range strings.Split(expression, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		parts := strings.SplitN(term, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid selector term '%s': expected key=value", term)
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		values := strings.Split(value, "|")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+
+		switch key {
+		case "tag", "tags":
+			if len(values) == 1 {
+				selector.Tags = append(selector.Tags, values...)
+			} else {
+				selector.TagAlternatives = append(selector.TagAlternatives, values)
+			}
+		case "name":
+			if len(values) > 1 {
+				return nil, fmt.Errorf("invalid selector term '%s': name does not accept alternatives", term)
+			}
+			if _, err = path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid name pattern '%s': %s", value, err.Error())
+			}
+			selector.NamePattern = core.StringPtr(value)
+		case "resource_group":
+			if len(values) > 1 {
+				return nil, fmt.Errorf("invalid selector term '%s': resource_group does not accept alternatives", term)
+			}
+			selector.ResourceGroup = core.StringPtr(value)
+		case "status":
+			selector.Statuses = append(selector.Statuses, values...)
+		case "location":
+			selector.Locations = append(selector.Locations, values...)
+		case "type":
+			selector.TemplateTypes = append(selector.TemplateTypes, values...)
+		case "created_after", "created_before", "updated_after", "updated_before":
+			var t *strfmt.DateTime
+			t, err = parseSelectorTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time for '%s': %s", key, err.Error())
+			}
+			switch key {
+			case "created_after":
+				selector.CreatedAfter = t
+			case "created_before":
+				selector.CreatedBefore = t
+			case "updated_after":
+				selector.UpdatedAfter = t
+			case "updated_before":
+				selector.UpdatedBefore = t
+			}
+		default:
+			return nil, fmt.Errorf("unknown selector key '%s'", key)
+		}
+	}
+	return
+}
+
+// isEmpty reports whether the selector sets no criteria, and so matches every item.
+func (selector *Selector) isEmpty() bool {
+	return len(selector.Tags) == 0 && len(selector.TagAlternatives) == 0 && selector.NamePattern == nil && selector.ResourceGroup == nil &&
+		len(selector.Statuses) == 0 && len(selector.Locations) == 0 && len(selector.TemplateTypes) == 0 &&
+		selector.CreatedAfter == nil && selector.CreatedBefore == nil && selector.UpdatedAfter == nil &&
+		selector.UpdatedBefore == nil
+}
+
+func parseSelectorTime(value string) (*strfmt.DateTime, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			dateTime := strfmt.DateTime(t)
+			return &dateTime, nil
+		}
+	}
+	return nil, fmt.Errorf("'%s' is neither an RFC 3339 timestamp nor a date", value)
+}
+
+// MatchesWorkspace returns true if the workspace matches all the criteria of the selector. A nil selector matches
+// every workspace.
+func (selector *Selector) MatchesWorkspace(workspace *WorkspaceResponse) bool {
+	if selector == nil {
+		return true
+	}
+	if workspace == nil {
+		return false
+	}
+	templateTypes := copyStrings(workspace.Type)
+	for _, template := range workspace.TemplateData {
+		if template.Type != nil {
+			templateTypes = append(templateTypes, *template.Type)
+		}
+	}
+	return selector.matches(selectorItem{
+		name:          workspace.Name,
+		resourceGroup: workspace.ResourceGroup,
+		status:        workspace.Status,
+		location:      workspace.Location,
+		tags:          workspace.Tags,
+		templateTypes: templateTypes,
+		createdAt:     workspace.CreatedAt,
+		updatedAt:     workspace.UpdatedAt,
+	})
+}
+
+// MatchesAction returns true if the action matches all the criteria of the selector. A nil selector matches every
+// action.
+func (selector *Selector) MatchesAction(action *ActionLite) bool {
+	if selector == nil {
+		return true
+	}
+	if action == nil {
+		return false
+	}
+	item := selectorItem{
+		name:          action.Name,
+		resourceGroup: action.ResourceGroup,
+		location:      action.Location,
+		tags:          action.Tags,
+		createdAt:     action.CreatedAt,
+		updatedAt:     action.UpdatedAt,
+	}
+	if action.State != nil {
+		item.status = action.State.StatusCode
+	}
+	return selector.matches(item)
+}
+
+// MatchesJob returns true if the job matches all the criteria of the selector. A nil selector matches every job.
+func (selector *Selector) MatchesJob(job *JobLite) bool {
+	if selector == nil {
+		return true
+	}
+	if job == nil {
+		return false
+	}
+	item := selectorItem{
+		name:          job.Name,
+		resourceGroup: job.ResourceGroup,
+		location:      job.Location,
+		tags:          job.Tags,
+		createdAt:     job.SubmittedAt,
+		updatedAt:     job.UpdatedAt,
+	}
+	if job.Status != nil && job.Status.ActionJobStatus != nil {
+		item.status = job.Status.ActionJobStatus.StatusCode
+	}
+	return selector.matches(item)
+}
+
+// selectorItem holds the fields of a workspace, action or job that a selector looks at.
+type selectorItem struct {
+	name          *string
+	resourceGroup *string
+	status        *string
+	location      *string
+	tags          []string
+	templateTypes []string
+	createdAt     *strfmt.DateTime
+	updatedAt     *strfmt.DateTime
+}
+
+func (selector *Selector) matches(item selectorItem) bool {
+	if selector.NamePattern != nil {
+		if item.name == nil {
+			return false
+		}
+		matched, err := path.Match(strings.ToLower(*selector.NamePattern), strings.ToLower(*item.name))
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if selector.ResourceGroup != nil && (item.resourceGroup == nil || !strings.EqualFold(*item.resourceGroup, *selector.ResourceGroup)) {
+		return false
+	}
+	if len(selector.Statuses) > 0 && (item.status == nil || !containsFold(selector.Statuses, *item.status)) {
+		return false
+	}
+	if len(selector.Locations) > 0 && (item.location == nil || !containsFold(selector.Locations, *item.location)) {
+		return false
+	}
+	if len(selector.TemplateTypes) > 0 && !anyContainsFold(selector.TemplateTypes, item.templateTypes) {
+		return false
+	}
+	for _, tag := range selector.Tags {
+		if !matchesTag(item.tags, tag) {
+			return false
+		}
+	}
+	for _, alternatives := range selector.TagAlternatives {
+		if !matchesAnyTag(item.tags, alternatives) {
+			return false
+		}
+	}
+	return inTimeRange(item.createdAt, selector.CreatedAfter, selector.CreatedBefore) &&
+		inTimeRange(item.updatedAt, selector.UpdatedAfter, selector.UpdatedBefore)
+}
+
+// matchesTag reports whether tags contain want. A want without a colon also matches key:value tags with that key.
+func matchesTag(tags []string, want string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, want) {
+			return true
+		}
+		if !strings.Contains(want, ":") {
+			if i := strings.Index(tag, ":"); i >= 0 && strings.EqualFold(tag[:i], want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAnyTag reports whether tags contain at least one of the alternatives.
+func matchesAnyTag(tags []string, alternatives []string) bool {
+	for _, want := range alternatives {
+		if matchesTag(tags, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func inTimeRange(t *strfmt.DateTime, after *strfmt.DateTime, before *strfmt.DateTime) bool {
+	if after == nil && before == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	if after != nil && time.Time(*t).Before(time.Time(*after)) {
+		return false
+	}
+	if before != nil && !time.Time(*t).Before(time.Time(*before)) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyContainsFold(list []string, values []string) bool {
+	for _, value := range values {
+		if containsFold(list, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectWorkspacesOptions : The SelectWorkspaces options.
+type SelectWorkspacesOptions struct {
+	// The selector that the workspaces must match. When nil, all workspaces are returned.
+	Selector *Selector `json:"selector,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewSelectWorkspacesOptions : Instantiate SelectWorkspacesOptions
+func (*SchematicsV1) NewSelectWorkspacesOptions() *SelectWorkspacesOptions {
+	return &SelectWorkspacesOptions{}
+}
+
+// SetSelector : Allow user to set Selector
+func (options *SelectWorkspacesOptions) SetSelector(selector *Selector) *SelectWorkspacesOptions {
+	options.Selector = selector
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *SelectWorkspacesOptions) SetHeaders(param map[string]string) *SelectWorkspacesOptions {
+	options.Headers = param
+	return options
+}
+
+// SelectWorkspaces : List the workspaces that match a selector
+// Read every page of ListWorkspaces and return the workspaces that match the selector.
+func (schematics *SchematicsV1) SelectWorkspaces(selectWorkspacesOptions *SelectWorkspacesOptions) (result []WorkspaceResponse, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(selectWorkspacesOptions, "selectWorkspacesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+
+	var offset int64
+	for {
+		var page *WorkspaceResponseList
+		page, response, err = schematics.ListWorkspaces(&ListWorkspacesOptions{
+			Offset:  core.Int64Ptr(offset),
+			Limit:   core.Int64Ptr(listPageSize),
+			Headers: selectWorkspacesOptions.Headers,
+		})
+		if err != nil {
+			result = nil
+			return
+		}
+		for i := range page.Workspaces {
+			if selectWorkspacesOptions.Selector.MatchesWorkspace(&page.Workspaces[i]) {
+				result = append(result, page.Workspaces[i])
+			}
+		}
+		offset += int64(len(page.Workspaces))
+		if len(page.Workspaces) < listPageSize || (page.Count != nil && offset >= *page.Count) {
+			return
+		}
+	}
+}
+
+// SelectActionsOptions : The SelectActions options.
+type SelectActionsOptions struct {
+	// The selector that the actions must match. When nil, all actions are returned.
+	Selector *Selector `json:"selector,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewSelectActionsOptions : Instantiate SelectActionsOptions
+func (*SchematicsV1) NewSelectActionsOptions() *SelectActionsOptions {
+	return &SelectActionsOptions{}
+}
+
+// SetSelector : Allow user to set Selector
+func (options *SelectActionsOptions) SetSelector(selector *Selector) *SelectActionsOptions {
+	options.Selector = selector
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *SelectActionsOptions) SetHeaders(param map[string]string) *SelectActionsOptions {
+	options.Headers = param
+	return options
+}
+
+// SelectActions : List the actions that match a selector
+// Read every page of ListActions and return the actions that match the selector.
+func (schematics *SchematicsV1) SelectActions(selectActionsOptions *SelectActionsOptions) (result []ActionLite, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(selectActionsOptions, "selectActionsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+
+	var offset int64
+	for {
+		var page *ActionList
+		page, response, err = schematics.ListActions(&ListActionsOptions{
+			Offset:  core.Int64Ptr(offset),
+			Limit:   core.Int64Ptr(listPageSize),
+			Headers: selectActionsOptions.Headers,
+		})
+		if err != nil {
+			result = nil
+			return
+		}
+		for i := range page.Actions {
+			if selectActionsOptions.Selector.MatchesAction(&page.Actions[i]) {
+				result = append(result, page.Actions[i])
+			}
+		}
+		offset += int64(len(page.Actions))
+		if len(page.Actions) < listPageSize || (page.TotalCount != nil && offset >= *page.TotalCount) {
+			return
+		}
+	}
+}
+
+// SelectJobsOptions : The SelectJobs options.
+type SelectJobsOptions struct {
+	// The selector that the jobs must match. When nil, all jobs are returned.
+	Selector *Selector `json:"selector,omitempty"`
+
+	// Name of the resource (workspaces, actions or controls).
+	Resource *string `json:"resource,omitempty"`
+
+	// Action ID to get the list of jobs for.
+	ActionID *string `json:"action_id,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewSelectJobsOptions : Instantiate SelectJobsOptions
+func (*SchematicsV1) NewSelectJobsOptions() *SelectJobsOptions {
+	return &SelectJobsOptions{}
+}
+
+// SetSelector : Allow user to set Selector
+func (options *SelectJobsOptions) SetSelector(selector *Selector) *SelectJobsOptions {
+	options.Selector = selector
+	return options
+}
+
+// SetResource : Allow user to set Resource
+func (options *SelectJobsOptions) SetResource(resource string) *SelectJobsOptions {
+	options.Resource = core.StringPtr(resource)
+	return options
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *SelectJobsOptions) SetActionID(actionID string) *SelectJobsOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *SelectJobsOptions) SetHeaders(param map[string]string) *SelectJobsOptions {
+	options.Headers = param
+	return options
+}
+
+// SelectJobs : List the jobs that match a selector
+// Read every page of ListJobs and return the jobs that match the selector.
+func (schematics *SchematicsV1) SelectJobs(selectJobsOptions *SelectJobsOptions) (result []JobLite, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(selectJobsOptions, "selectJobsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+
+	var offset int64
+	for {
+		var page *JobList
+		page, response, err = schematics.ListJobs(&ListJobsOptions{
+			Offset:   core.Int64Ptr(offset),
+			Limit:    core.Int64Ptr(listPageSize),
+			Resource: selectJobsOptions.Resource,
+			ActionID: selectJobsOptions.ActionID,
+			Headers:  selectJobsOptions.Headers,
+		})
+		if err != nil {
+			result = nil
+			return
+		}
+		for i := range page.Jobs {
+			if selectJobsOptions.Selector.MatchesJob(&page.Jobs[i]) {
+				result = append(result, page.Jobs[i])
+			}
+		}
+		offset += int64(len(page.Jobs))
+		if len(page.Jobs) < listPageSize || (page.TotalCount != nil && offset >= *page.TotalCount) {
+			return
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	"github.com/go-openapi/strfmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Selectors`, func() {
+	var testServer *httptest.Server
+	Describe(`ParseSelector(expression string)`, func() {
+		It(`Parse a selector expression successfully`, func() {
+			selector, err := schematicsv1.ParseSelector("tag=env:prod, tag=team, status=ACTIVE|INACTIVE, resource_group=Default, name=app-*, location=us-south, type=terraform_v0.12, created_after=2021-01-01, updated_before=2021-03-01T00:00:00Z")
+			Expect(err).To(BeNil())
+			Expect(selector.Tags).To(Equal([]string{"env:prod", "team"}))
+			Expect(selector.Statuses).To(Equal([]string{"ACTIVE", "INACTIVE"}))
+			Expect(*selector.ResourceGroup).To(Equal("Default"))
+			Expect(*selector.NamePattern).To(Equal("app-*"))
+			Expect(selector.Locations).To(Equal([]string{"us-south"}))
+			Expect(selector.TemplateTypes).To(Equal([]string{"terraform_v0.12"}))
+			Expect(time.Time(*selector.CreatedAfter).Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(time.Time(*selector.UpdatedBefore).Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+		It(`Parse tag alternatives`, func() {
+			selector, err := schematicsv1.ParseSelector("tag=env:prod, tag=a|b")
+			Expect(err).To(BeNil())
+			Expect(selector.Tags).To(Equal([]string{"env:prod"}))
+			Expect(selector.TagAlternatives).To(Equal([][]string{{"a", "b"}}))
+
+			Expect(selector.MatchesWorkspace(&schematicsv1.WorkspaceResponse{Tags: []string{"env:prod", "a"}})).To(BeTrue())
+			Expect(selector.MatchesWorkspace(&schematicsv1.WorkspaceResponse{Tags: []string{"env:prod", "b:1"}})).To(BeTrue())
+			Expect(selector.MatchesWorkspace(&schematicsv1.WorkspaceResponse{Tags: []string{"env:prod", "c"}})).To(BeFalse())
+			Expect(selector.MatchesWorkspace(&schematicsv1.WorkspaceResponse{Tags: []string{"a", "b"}})).To(BeFalse())
+		})
+		It(`Parse a selector expression with error`, func() {
+			for _, expression := range []string{"status", "owner=me", "name=[", "created_after=yesterday", "tag=", "name=a|b", "resource_group=a|b"} {
+				selector, err := schematicsv1.ParseSelector(expression)
+				Expect(err).ToNot(BeNil(), expression)
+				Expect(selector).To(BeNil())
+			}
+		})
+	})
+	Describe(`Selector matching`, func() {
+		created := strfmt.DateTime(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+		workspace := &schematicsv1.WorkspaceResponse{
+			Name:          core.StringPtr("app-prod"),
+			ResourceGroup: core.StringPtr("Default"),
+			Status:        core.StringPtr("ACTIVE"),
+			Location:      core.StringPtr("us-south"),
+			Tags:          []string{"env:prod", "team:infra"},
+			Type:          []string{"terraform_v0.12"},
+			CreatedAt:     &created,
+		}
+		It(`Match workspaces`, func() {
+			for expression, expected := range map[string]bool{
+				"":                           true,
+				"tag=env:prod":               true,
+				"tag=ENV:PROD":               true,
+				"tag=team":                   true,
+				"tag=env:dev":                false,
+				"tag=env:prod,tag=owner":     false,
+				"status=active":              true,
+				"status=FAILED|INACTIVE":     false,
+				"name=app-*":                 true,
+				"name=db-*":                  false,
+				"resource_group=default":     true,
+				"location=eu-de":             false,
+				"type=terraform_v0.12":       true,
+				"type=terraform_v0.11":       false,
+				"created_after=2021-01-01":   true,
+				"created_after=2021-02-01":   true,
+				"created_before=2021-02-01":  false,
+				"updated_after=2021-01-01":   false,
+				"tag=env:prod,status=ACTIVE": true,
+				"tag=env:prod,status=FAILED": false,
+			} {
+				selector, err := schematicsv1.ParseSelector(expression)
+				Expect(err).To(BeNil())
+				Expect(selector.MatchesWorkspace(workspace)).To(Equal(expected), expression)
+			}
+			var selector *schematicsv1.Selector
+			Expect(selector.MatchesWorkspace(workspace)).To(BeTrue())
+		})
+		It(`Match actions and jobs`, func() {
+			action := &schematicsv1.ActionLite{
+				Name:  core.StringPtr("patch"),
+				Tags:  []string{"env:prod"},
+				State: &schematicsv1.ActionLiteState{StatusCode: core.StringPtr("normal")},
+			}
+			job := &schematicsv1.JobLite{
+				Name:        core.StringPtr("patch-job"),
+				SubmittedAt: &created,
+				Status: &schematicsv1.JobStatus{
+					ActionJobStatus: &schematicsv1.JobStatusAction{StatusCode: core.StringPtr("job_finished")},
+				},
+			}
+			selector, _ := schematicsv1.ParseSelector("tag=env,status=normal")
+			Expect(selector.MatchesAction(action)).To(BeTrue())
+			selector, _ = schematicsv1.ParseSelector("type=terraform_v0.12")
+			Expect(selector.MatchesAction(action)).To(BeFalse())
+			selector, _ = schematicsv1.ParseSelector("status=job_finished,created_before=2021-03-01")
+			Expect(selector.MatchesJob(job)).To(BeTrue())
+			selector, _ = schematicsv1.ParseSelector("status=job_failed")
+			Expect(selector.MatchesJob(job)).To(BeFalse())
+		})
+	})
+	Describe(`SelectActions and SelectJobs`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.Method).To(Equal("GET"))
+					res.Header().Set("Content-type", "application/json")
+					switch req.URL.EscapedPath() {
+					case "/v2/actions":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"total_count": 2, "limit": 100, "offset": 0, "actions": [{"id": "a-1", "name": "patch", "tags": ["env:prod"]}, {"id": "a-2", "name": "backup", "tags": ["env:dev"]}]}`)
+					case "/v2/jobs":
+						Expect(req.URL.Query()["action_id"]).To(Equal([]string{"a-1"}))
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"total_count": 2, "limit": 100, "offset": 0, "jobs": [{"id": "j-1", "status": {"action_job_status": {"status_code": "job_failed"}}}, {"id": "j-2", "status": {"action_job_status": {"status_code": "job_finished"}}}]}`)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke SelectActions and SelectJobs successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				selector, _ := schematicsv1.ParseSelector("tag=env:prod")
+				actions, response, operationErr := schematicsService.SelectActions(schematicsService.NewSelectActionsOptions().SetSelector(selector))
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(actions).To(HaveLen(1))
+				Expect(*actions[0].ID).To(Equal("a-1"))
+
+				selector, _ = schematicsv1.ParseSelector("status=job_failed")
+				jobs, _, operationErr := schematicsService.SelectJobs(schematicsService.NewSelectJobsOptions().SetSelector(selector).SetActionID("a-1"))
+				Expect(operationErr).To(BeNil())
+				Expect(jobs).To(HaveLen(1))
+				Expect(*jobs[0].ID).To(Equal("j-1"))
+
+				// Invoke operations with nil options model (negative test)
+				_, _, operationErr = schematicsService.SelectActions(nil)
+				Expect(operationErr).ToNot(BeNil())
+				_, _, operationErr = schematicsService.SelectJobs(nil)
+				Expect(operationErr).ToNot(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,546 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants associated with the SharedDatasetDataChange.Change property.
+// How the data changed between two snapshots.
+const (
+	SharedDatasetDataChange_Change_Added   = "added"
+	SharedDatasetDataChange_Change_Changed = "changed"
+	SharedDatasetDataChange_Change_Removed = "removed"
+)
+
+// sharedDatasetMetadataFields are the fields of a shared dataset that are not compared between snapshots.
+var sharedDatasetMetadataFields = []string{
+	"account", "created_at", "created_by", "shared_dataset_data", "shared_dataset_id", "updated_at", "updated_by", "version",
+}
+
+// SharedDatasetSnapshot : A copy of a shared dataset, taken before it was replaced.
+type SharedDatasetSnapshot struct {
+	// The shared dataset ID.
+	SharedDatasetID string `json:"shared_dataset_id"`
+
+	// The version of the shared dataset, or the time of the snapshot when the dataset has no version.
+	Version string `json:"version"`
+
+	// The time of the snapshot.
+	CreatedAt time.Time `json:"created_at"`
+
+	// The shared dataset.
+	Dataset *SharedDatasetResponse `json:"dataset"`
+}
+
+// NewSharedDatasetSnapshot returns a snapshot of the shared dataset taken now.
+func NewSharedDatasetSnapshot(dataset *SharedDatasetResponse) *SharedDatasetSnapshot {
+	snapshot := &SharedDatasetSnapshot{
+		SharedDatasetID: stringValue(dataset.SharedDatasetID),
+		Version:         stringValue(dataset.Version),
+		CreatedAt:       time.Now().UTC(),
+		Dataset:         dataset,
+	}
+	if snapshot.Version == "" {
+		snapshot.Version = snapshot.CreatedAt.Format("20060102T150405.000000000Z")
+	}
+	return snapshot
+}
+
+// SharedDatasetSnapshotNotFoundError is returned by a SharedDatasetSnapshotStore when it has no snapshot of a version.
+type SharedDatasetSnapshotNotFoundError struct {
+	// The shared dataset ID.
+	SharedDatasetID string
+
+	// The requested version.
+	Version string
+}
+
+// Error implements the error interface.
+func (e *SharedDatasetSnapshotNotFoundError) Error() string {
+	return fmt.Sprintf("no snapshot of version %s of shared dataset %s", e.Version, e.SharedDatasetID)
+}
+
+// SharedDatasetSnapshotStore keeps the snapshots of shared datasets, by shared dataset ID and version. Saving a
+// version again replaces its snapshot. DirectorySnapshotStore and MemorySnapshotStore are provided; an embedded
+// key-value store can implement it as well.
+type SharedDatasetSnapshotStore interface {
+	// SaveSnapshot saves the snapshot.
+	SaveSnapshot(snapshot *SharedDatasetSnapshot) error
+
+	// LoadSnapshot returns the snapshot of a version, or a *SharedDatasetSnapshotNotFoundError.
+	LoadSnapshot(sdID string, version string) (*SharedDatasetSnapshot, error)
+
+	// ListSnapshots returns the snapshots of a shared dataset, oldest first.
+	ListSnapshots(sdID string) ([]*SharedDatasetSnapshot, error)
+}
+
+// sortSnapshots sorts snapshots oldest first.
+func sortSnapshots(snapshots []*SharedDatasetSnapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].Version < snapshots[j].Version
+	})
+}
+
+// DirectorySnapshotStore : A SharedDatasetSnapshotStore that keeps each snapshot in a JSON file named
+// <Directory>/<shared dataset ID>/<version>.json. The files can hold secure values, so they are only readable by
+// their owner.
+type DirectorySnapshotStore struct {
+	// The directory of the snapshots. It is created when needed.
+	Directory string
+}
+
+func (store *DirectorySnapshotStore) datasetDirectory(sdID string) string {
+	return filepath.Join(store.Directory, url.PathEscape(sdID))
+}
+
+// SaveSnapshot implements the SharedDatasetSnapshotStore interface.
+func (store *DirectorySnapshotStore) SaveSnapshot(snapshot *SharedDatasetSnapshot) error {
+	body, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	directory := store.datasetDirectory(snapshot.SharedDatasetID)
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return err
+	}
+
+	// The snapshot is written to a temporary file first, so that a failed save does not leave a partial snapshot.
+	file, err := ioutil.TempFile(directory, ".snapshot-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(directory, url.PathEscape(snapshot.Version)+".json"))
+}
+
+// LoadSnapshot implements the SharedDatasetSnapshotStore interface.
+func (store *DirectorySnapshotStore) LoadSnapshot(sdID string, version string) (*SharedDatasetSnapshot, error) {
+	body, err := ioutil.ReadFile(filepath.Join(store.datasetDirectory(sdID), url.PathEscape(version)+".json"))
+	if os.IsNotExist(err) {
+		return nil, &SharedDatasetSnapshotNotFoundError{SharedDatasetID: sdID, Version: version}
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &SharedDatasetSnapshot{}
+	if err := json.Unmarshal(body, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots implements the SharedDatasetSnapshotStore interface.
+func (store *DirectorySnapshotStore) ListSnapshots(sdID string) ([]*SharedDatasetSnapshot, error) {
+	files, err := ioutil.ReadDir(store.datasetDirectory(sdID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*SharedDatasetSnapshot
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		version, err := url.PathUnescape(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		snapshot, err := store.LoadSnapshot(sdID, version)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+// MemorySnapshotStore : A SharedDatasetSnapshotStore that keeps the snapshots in memory. The zero value is ready to
+// use.
+type MemorySnapshotStore struct {
+	mutex     sync.Mutex
+	snapshots map[string]map[string][]byte
+}
+
+// SaveSnapshot implements the SharedDatasetSnapshotStore interface.
+func (store *MemorySnapshotStore) SaveSnapshot(snapshot *SharedDatasetSnapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.snapshots == nil {
+		store.snapshots = map[string]map[string][]byte{}
+	}
+	if store.snapshots[snapshot.SharedDatasetID] == nil {
+		store.snapshots[snapshot.SharedDatasetID] = map[string][]byte{}
+	}
+	store.snapshots[snapshot.SharedDatasetID][snapshot.Version] = body
+	return nil
+}
+
+// LoadSnapshot implements the SharedDatasetSnapshotStore interface.
+func (store *MemorySnapshotStore) LoadSnapshot(sdID string, version string) (*SharedDatasetSnapshot, error) {
+	store.mutex.Lock()
+	body, found := store.snapshots[sdID][version]
+	store.mutex.Unlock()
+	if !found {
+		return nil, &SharedDatasetSnapshotNotFoundError{SharedDatasetID: sdID, Version: version}
+	}
+	snapshot := &SharedDatasetSnapshot{}
+	if err := json.Unmarshal(body, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots implements the SharedDatasetSnapshotStore interface.
+func (store *MemorySnapshotStore) ListSnapshots(sdID string) ([]*SharedDatasetSnapshot, error) {
+	store.mutex.Lock()
+	versions := sortedKeys(store.snapshots[sdID])
+	store.mutex.Unlock()
+	var snapshots []*SharedDatasetSnapshot
+	for _, version := range versions {
+		snapshot, err := store.LoadSnapshot(sdID, version)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+// ReplaceSharedDatasetWithSnapshot : Snapshot a shared dataset and replace it
+// Get the current shared dataset with the headers of the options and save it to the store, then replace the dataset
+// with ReplaceSharedDataset. The replace is not sent when the snapshot fails. A shared dataset that does not exist
+// has nothing to save.
+func (schematics *SchematicsV1) ReplaceSharedDatasetWithSnapshot(replaceSharedDatasetOptions *ReplaceSharedDatasetOptions, store SharedDatasetSnapshotStore) (result *SharedDatasetResponse, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(replaceSharedDatasetOptions, "replaceSharedDatasetOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(replaceSharedDatasetOptions, "replaceSharedDatasetOptions")
+	if err != nil {
+		return
+	}
+	err = core.ValidateNotNil(store, "store cannot be nil")
+	if err != nil {
+		return
+	}
+
+	sdID := *replaceSharedDatasetOptions.SdID
+	dataset, response, err := schematics.GetSharedDataset(&GetSharedDatasetOptions{
+		SdID:    replaceSharedDatasetOptions.SdID,
+		Headers: replaceSharedDatasetOptions.Headers,
+	})
+	switch {
+	case err != nil && response != nil && response.StatusCode == http.StatusNotFound:
+		err = nil
+	case err == nil:
+		if dataset.SharedDatasetID == nil {
+			dataset.SharedDatasetID = core.StringPtr(sdID)
+		}
+		err = store.SaveSnapshot(NewSharedDatasetSnapshot(dataset))
+	}
+	if err != nil {
+		err = fmt.Errorf("can not snapshot shared dataset %s before replacing it: %s", sdID, err)
+		return
+	}
+	return schematics.ReplaceSharedDataset(replaceSharedDatasetOptions)
+}
+
+// SharedDatasetMaskedSecureDataError is returned by RollbackSharedDataset when the snapshot holds secure data whose
+// values were masked by the service. Restoring it would replace the secure values with the mask.
+type SharedDatasetMaskedSecureDataError struct {
+	// The shared dataset ID.
+	SharedDatasetID string
+
+	// The version of the snapshot.
+	Version string
+
+	// The variable names of the masked secure data.
+	VarNames []string
+}
+
+// Error implements the error interface.
+func (e *SharedDatasetMaskedSecureDataError) Error() string {
+	return fmt.Sprintf("can not roll back shared dataset %s to version %s: the snapshot masks the values of secure data %s",
+		e.SharedDatasetID, e.Version, strings.Join(e.VarNames, ", "))
+}
+
+// isMaskedSecureData reports whether the values of secure data were masked by the service. Secure data without any
+// value is taken as masked too, since the service may leave the values out.
+func isMaskedSecureData(data *SharedDatasetData) bool {
+	if !isTrue(data.Secure) {
+		return false
+	}
+	hasValue := false
+	for _, value := range []*string{data.DefaultValue, data.OverrideValue} {
+		if value == nil || *value == "" {
+			continue
+		}
+		if isMaskedSecureValue(value) {
+			return true
+		}
+		hasValue = true
+	}
+	return !hasValue
+}
+
+// RollbackSharedDatasetOptions : The RollbackSharedDataset options.
+type RollbackSharedDatasetOptions struct {
+	// The shared dataset ID Use the GET /shared_datasets to look up the shared dataset IDs  in your IBM Cloud account.
+	SdID *string `json:"sd_id" validate:"required,ne="`
+
+	// The version of the snapshot to restore.
+	Version *string `json:"version" validate:"required,ne="`
+
+	// The store of the snapshot. The current dataset is saved to it before the rollback.
+	Store SharedDatasetSnapshotStore `validate:"required"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRollbackSharedDatasetOptions : Instantiate RollbackSharedDatasetOptions
+func (*SchematicsV1) NewRollbackSharedDatasetOptions(sdID string, version string, store SharedDatasetSnapshotStore) *RollbackSharedDatasetOptions {
+	return &RollbackSharedDatasetOptions{
+		SdID:    core.StringPtr(sdID),
+		Version: core.StringPtr(version),
+		Store:   store,
+	}
+}
+
+// SetSdID : Allow user to set SdID
+func (options *RollbackSharedDatasetOptions) SetSdID(sdID string) *RollbackSharedDatasetOptions {
+	options.SdID = core.StringPtr(sdID)
+	return options
+}
+
+// SetVersion : Allow user to set Version
+func (options *RollbackSharedDatasetOptions) SetVersion(version string) *RollbackSharedDatasetOptions {
+	options.Version = core.StringPtr(version)
+	return options
+}
+
+// SetStore : Allow user to set Store
+func (options *RollbackSharedDatasetOptions) SetStore(store SharedDatasetSnapshotStore) *RollbackSharedDatasetOptions {
+	options.Store = store
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RollbackSharedDatasetOptions) SetHeaders(param map[string]string) *RollbackSharedDatasetOptions {
+	options.Headers = param
+	return options
+}
+
+// RollbackSharedDataset : Restore a snapshot of a shared dataset
+// Replace the shared dataset with a snapshot from the store of the options. The description, affected workspaces,
+// resource group, data, name, type and tags of the snapshot are restored; the service assigns the version. The current
+// dataset is saved to the store first, so a rollback can be rolled back. Snapshots whose secure data was masked by the
+// service are refused with a *SharedDatasetMaskedSecureDataError, since their secure values are not known.
+func (schematics *SchematicsV1) RollbackSharedDataset(rollbackSharedDatasetOptions *RollbackSharedDatasetOptions) (result *SharedDatasetResponse, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(rollbackSharedDatasetOptions, "rollbackSharedDatasetOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(rollbackSharedDatasetOptions, "rollbackSharedDatasetOptions")
+	if err != nil {
+		return
+	}
+	snapshot, err := rollbackSharedDatasetOptions.Store.LoadSnapshot(*rollbackSharedDatasetOptions.SdID, *rollbackSharedDatasetOptions.Version)
+	if err != nil {
+		return
+	}
+	dataset := snapshot.Dataset
+	if dataset == nil {
+		dataset = &SharedDatasetResponse{}
+	}
+	var masked []string
+	for i := range dataset.SharedDatasetData {
+		if isMaskedSecureData(&dataset.SharedDatasetData[i]) {
+			masked = append(masked, stringValue(dataset.SharedDatasetData[i].VarName))
+		}
+	}
+	if len(masked) > 0 {
+		err = &SharedDatasetMaskedSecureDataError{SharedDatasetID: snapshot.SharedDatasetID, Version: snapshot.Version, VarNames: masked}
+		return
+	}
+	return schematics.ReplaceSharedDatasetWithSnapshot(&ReplaceSharedDatasetOptions{
+		SdID:                 rollbackSharedDatasetOptions.SdID,
+		Description:          dataset.Description,
+		EffectedWorkspaceIds: dataset.EffectedWorkspaceIds,
+		ResourceGroup:        dataset.ResourceGroup,
+		SharedDatasetData:    dataset.SharedDatasetData,
+		SharedDatasetName:    dataset.SharedDatasetName,
+		SharedDatasetType:    dataset.SharedDatasetType,
+		Tags:                 dataset.Tags,
+		Headers:              rollbackSharedDatasetOptions.Headers,
+	}, rollbackSharedDatasetOptions.Store)
+}
+
+// SharedDatasetDataChange : The change of one shared dataset data between two snapshots.
+type SharedDatasetDataChange struct {
+	// The variable name of the data.
+	VarName string `json:"var_name"`
+
+	// How the data changed.
+	Change string `json:"change"`
+
+	// The changed fields, such as 'default_value'. Only set for changed data.
+	Fields []string `json:"fields,omitempty"`
+
+	// The data in the older snapshot. Not set for added data.
+	From *SharedDatasetData `json:"from,omitempty"`
+
+	// The data in the newer snapshot. Not set for removed data.
+	To *SharedDatasetData `json:"to,omitempty"`
+}
+
+// SharedDatasetSnapshotDiff : The changes of a shared dataset between two snapshots.
+type SharedDatasetSnapshotDiff struct {
+	// The shared dataset ID.
+	SharedDatasetID string `json:"shared_dataset_id"`
+
+	// The version of the older snapshot.
+	FromVersion string `json:"from_version"`
+
+	// The version of the newer snapshot.
+	ToVersion string `json:"to_version"`
+
+	// The changed dataset fields, such as 'tags'. Metadata such as the update time is not compared.
+	Fields []string `json:"fields,omitempty"`
+
+	// The changed data, by variable name.
+	Data []SharedDatasetDataChange `json:"data,omitempty"`
+}
+
+// HasChanges reports whether the snapshots differ.
+func (diff *SharedDatasetSnapshotDiff) HasChanges() bool {
+	return len(diff.Fields) > 0 || len(diff.Data) > 0
+}
+
+// DiffSharedDatasetSnapshots compares two snapshots of a shared dataset. Data is matched by variable name.
+func DiffSharedDatasetSnapshots(from *SharedDatasetSnapshot, to *SharedDatasetSnapshot) *SharedDatasetSnapshotDiff {
+	diff := &SharedDatasetSnapshotDiff{SharedDatasetID: to.SharedDatasetID, FromVersion: from.Version, ToVersion: to.Version}
+	fromDataset, toDataset := from.Dataset, to.Dataset
+	if fromDataset == nil {
+		fromDataset = &SharedDatasetResponse{}
+	}
+	if toDataset == nil {
+		toDataset = &SharedDatasetResponse{}
+	}
+	diff.Fields = changedJSONFields(fromDataset, toDataset, sharedDatasetMetadataFields)
+
+	fromData, toData := map[string]*SharedDatasetData{}, map[string]*SharedDatasetData{}
+	names := map[string]bool{}
+	for i := range fromDataset.SharedDatasetData {
+		name := stringValue(fromDataset.SharedDatasetData[i].VarName)
+		fromData[name], names[name] = &fromDataset.SharedDatasetData[i], true
+	}
+	for i := range toDataset.SharedDatasetData {
+		name := stringValue(toDataset.SharedDatasetData[i].VarName)
+		toData[name], names[name] = &toDataset.SharedDatasetData[i], true
+	}
+	for _, name := range sortedKeys(names) {
+		change := SharedDatasetDataChange{VarName: name, From: fromData[name], To: toData[name]}
+		switch {
+		case change.From == nil:
+			change.Change = SharedDatasetDataChange_Change_Added
+		case change.To == nil:
+			change.Change = SharedDatasetDataChange_Change_Removed
+		default:
+			change.Fields = changedJSONFields(change.From, change.To, nil)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Change = SharedDatasetDataChange_Change_Changed
+		}
+		diff.Data = append(diff.Data, change)
+	}
+	return diff
+}
+
+// DiffSharedDatasetVersions compares the snapshots of two versions of a shared dataset in the store.
+func DiffSharedDatasetVersions(store SharedDatasetSnapshotStore, sdID string, fromVersion string, toVersion string) (*SharedDatasetSnapshotDiff, error) {
+	from, err := store.LoadSnapshot(sdID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := store.LoadSnapshot(sdID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return DiffSharedDatasetSnapshots(from, to), nil
+}
+
+// changedJSONFields returns the JSON fields whose values differ between two models, in name order.
+func changedJSONFields(from interface{}, to interface{}, ignored []string) (fields []string) {
+	fromFields, toFields := jsonFields(from), jsonFields(to)
+	names := map[string]bool{}
+	for name := range fromFields {
+		names[name] = true
+	}
+	for name := range toFields {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		if !containsString(ignored, name) && !bytes.Equal(fromFields[name], toFields[name]) {
+			fields = append(fields, name)
+		}
+	}
+	return
+}
+
+func jsonFields(model interface{}) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	body, err := json.Marshal(model)
+	if err == nil {
+		_ = json.Unmarshal(body, &fields)
+	}
+	return fields
+}
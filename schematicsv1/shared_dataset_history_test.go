@@ -0,0 +1,293 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var _ = Describe(`Shared dataset history`, func() {
+	var testServer *httptest.Server
+	snapshot := func(version string, createdAt time.Time, data ...schematicsv1.SharedDatasetData) *schematicsv1.SharedDatasetSnapshot {
+		return &schematicsv1.SharedDatasetSnapshot{
+			SharedDatasetID: "sd-1",
+			Version:         version,
+			CreatedAt:       createdAt,
+			Dataset: &schematicsv1.SharedDatasetResponse{
+				SharedDatasetID:   core.StringPtr("sd-1"),
+				Version:           core.StringPtr(version),
+				SharedDatasetData: data,
+			},
+		}
+	}
+	data := func(name string, value string) schematicsv1.SharedDatasetData {
+		return schematicsv1.SharedDatasetData{VarName: core.StringPtr(name), DefaultValue: core.StringPtr(value)}
+	}
+
+	Describe(`DirectorySnapshotStore`, func() {
+		var directory string
+		BeforeEach(func() {
+			var err error
+			directory, err = ioutil.TempDir("", "snapshots")
+			Expect(err).To(BeNil())
+		})
+		AfterEach(func() {
+			os.RemoveAll(directory)
+		})
+		It(`Save, load and list snapshots`, func() {
+			store := &schematicsv1.DirectorySnapshotStore{Directory: filepath.Join(directory, "store")}
+			now := time.Now().UTC()
+			Expect(store.SaveSnapshot(snapshot("2", now, data("region", "eu-de")))).To(Succeed())
+			Expect(store.SaveSnapshot(snapshot("1", now.Add(-time.Hour), data("region", "us-south")))).To(Succeed())
+			Expect(store.SaveSnapshot(snapshot("a/b", now.Add(time.Hour)))).To(Succeed())
+
+			info, err := os.Stat(filepath.Join(directory, "store", "sd-1", "1.json"))
+			Expect(err).To(BeNil())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			loaded, err := store.LoadSnapshot("sd-1", "2")
+			Expect(err).To(BeNil())
+			Expect(loaded.CreatedAt.Equal(now)).To(BeTrue())
+			Expect(*loaded.Dataset.SharedDatasetData[0].DefaultValue).To(Equal("eu-de"))
+
+			snapshots, err := store.ListSnapshots("sd-1")
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(HaveLen(3))
+			Expect(snapshots[0].Version).To(Equal("1"))
+			Expect(snapshots[1].Version).To(Equal("2"))
+			Expect(snapshots[2].Version).To(Equal("a/b"))
+
+			_, err = store.LoadSnapshot("sd-1", "3")
+			Expect(err).To(BeAssignableToTypeOf(&schematicsv1.SharedDatasetSnapshotNotFoundError{}))
+			snapshots, err = store.ListSnapshots("sd-2")
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(BeEmpty())
+		})
+	})
+	Describe(`MemorySnapshotStore`, func() {
+		It(`Save, load and list snapshots`, func() {
+			store := &schematicsv1.MemorySnapshotStore{}
+			now := time.Now().UTC()
+			original := snapshot("2", now, data("region", "eu-de"))
+			Expect(store.SaveSnapshot(original)).To(Succeed())
+			Expect(store.SaveSnapshot(snapshot("10", now.Add(-time.Hour)))).To(Succeed())
+
+			// The store keeps a copy of the snapshot.
+			original.Dataset.SharedDatasetData[0].DefaultValue = core.StringPtr("us-east")
+			loaded, err := store.LoadSnapshot("sd-1", "2")
+			Expect(err).To(BeNil())
+			Expect(*loaded.Dataset.SharedDatasetData[0].DefaultValue).To(Equal("eu-de"))
+
+			snapshots, err := store.ListSnapshots("sd-1")
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(HaveLen(2))
+			Expect(snapshots[0].Version).To(Equal("10"))
+
+			_, err = store.LoadSnapshot("sd-2", "2")
+			Expect(err).To(MatchError("no snapshot of version 2 of shared dataset sd-2"))
+		})
+	})
+	Describe(`DiffSharedDatasetSnapshots(from *SharedDatasetSnapshot, to *SharedDatasetSnapshot)`, func() {
+		It(`Compare two snapshots`, func() {
+			from := snapshot("1", time.Now(), data("region", "us-south"), data("zone", "1"), data("workers", "3"))
+			to := snapshot("2", time.Now(), data("region", "eu-de"), data("workers", "3"), data("image", "ubuntu"))
+			to.Dataset.Tags = []string{"env:prod"}
+			to.Dataset.UpdatedBy = core.StringPtr("someone")
+
+			diff := schematicsv1.DiffSharedDatasetSnapshots(from, to)
+			Expect(diff.HasChanges()).To(BeTrue())
+			Expect(diff.FromVersion).To(Equal("1"))
+			Expect(diff.ToVersion).To(Equal("2"))
+			Expect(diff.Fields).To(Equal([]string{"tags"}))
+			Expect(diff.Data).To(HaveLen(3))
+			Expect(diff.Data[0].VarName).To(Equal("image"))
+			Expect(diff.Data[0].Change).To(Equal(schematicsv1.SharedDatasetDataChange_Change_Added))
+			Expect(diff.Data[0].From).To(BeNil())
+			Expect(diff.Data[1].VarName).To(Equal("region"))
+			Expect(diff.Data[1].Change).To(Equal(schematicsv1.SharedDatasetDataChange_Change_Changed))
+			Expect(diff.Data[1].Fields).To(Equal([]string{"default_value"}))
+			Expect(diff.Data[2].VarName).To(Equal("zone"))
+			Expect(diff.Data[2].Change).To(Equal(schematicsv1.SharedDatasetDataChange_Change_Removed))
+
+			Expect(schematicsv1.DiffSharedDatasetSnapshots(from, from).HasChanges()).To(BeFalse())
+		})
+		It(`Compare two versions in a store`, func() {
+			store := &schematicsv1.MemorySnapshotStore{}
+			Expect(store.SaveSnapshot(snapshot("1", time.Now(), data("region", "us-south")))).To(Succeed())
+			Expect(store.SaveSnapshot(snapshot("2", time.Now(), data("region", "eu-de")))).To(Succeed())
+
+			diff, err := schematicsv1.DiffSharedDatasetVersions(store, "sd-1", "1", "2")
+			Expect(err).To(BeNil())
+			Expect(diff.Data).To(HaveLen(1))
+			_, err = schematicsv1.DiffSharedDatasetVersions(store, "sd-1", "1", "3")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+	Describe(`ReplaceSharedDatasetWithSnapshot(replaceSharedDatasetOptions *ReplaceSharedDatasetOptions, store SharedDatasetSnapshotStore) and RollbackSharedDataset(rollbackSharedDatasetOptions *RollbackSharedDatasetOptions)`, func() {
+		var current map[string]interface{}
+		var puts int
+		var failGet bool
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				current = map[string]interface{}{
+					"shared_dataset_id":   "sd-1",
+					"shared_dataset_name": "network",
+					"version":             "1",
+					"shared_dataset_data": []interface{}{map[string]interface{}{"var_name": "region", "default_value": "us-south"}},
+				}
+				puts = 0
+				failGet = false
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					res.Header().Set("Content-type", "application/json")
+					Expect(req.URL.EscapedPath()).To(Equal("/v2/shared_datasets/sd-1"))
+					Expect(req.Header.Get("Authorization")).To(Equal("Bearer token"))
+					switch req.Method {
+					case "GET":
+						if failGet {
+							res.WriteHeader(500)
+							return
+						}
+					case "PUT":
+						puts++
+						body, _ := ioutil.ReadAll(req.Body)
+						var replacement map[string]interface{}
+						Expect(json.Unmarshal(body, &replacement)).To(Succeed())
+						Expect(replacement).ToNot(HaveKey("version"))
+						version, _ := strconv.Atoi(current["version"].(string))
+						current = replacement
+						current["shared_dataset_id"] = "sd-1"
+						current["version"] = strconv.Itoa(version + 1)
+					}
+					body, _ := json.Marshal(current)
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", body)
+				}))
+			})
+			It(`Invoke ReplaceSharedDatasetWithSnapshot and RollbackSharedDataset successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.BearerTokenAuthenticator{BearerToken: "token"},
+				})
+				Expect(serviceErr).To(BeNil())
+				store := &schematicsv1.MemorySnapshotStore{}
+
+				// Invoke operation with nil options model or store (negative test)
+				result, response, operationErr := schematicsService.RollbackSharedDataset(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+				_, _, operationErr = schematicsService.RollbackSharedDataset(schematicsService.NewRollbackSharedDatasetOptions("sd-1", "1", nil))
+				Expect(operationErr).NotTo(BeNil())
+				result, response, operationErr = schematicsService.ReplaceSharedDatasetWithSnapshot(nil, store)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+				_, _, operationErr = schematicsService.ReplaceSharedDatasetWithSnapshot(schematicsService.NewReplaceSharedDatasetOptions("sd-1"), nil)
+				Expect(operationErr).To(MatchError("store cannot be nil"))
+				Expect(puts).To(Equal(0))
+
+				replaceOptions := schematicsService.NewReplaceSharedDatasetOptions("sd-1").
+					SetSharedDatasetName("network").
+					SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+						{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("eu-de")},
+					})
+				result, _, operationErr = schematicsService.ReplaceSharedDatasetWithSnapshot(replaceOptions, store)
+				Expect(operationErr).To(BeNil())
+				Expect(*result.Version).To(Equal("2"))
+
+				snapshots, _ := store.ListSnapshots("sd-1")
+				Expect(snapshots).To(HaveLen(1))
+				Expect(snapshots[0].Version).To(Equal("1"))
+				Expect(*snapshots[0].Dataset.SharedDatasetData[0].DefaultValue).To(Equal("us-south"))
+
+				result, _, operationErr = schematicsService.RollbackSharedDataset(schematicsService.NewRollbackSharedDatasetOptions("sd-1", "1", store))
+				Expect(operationErr).To(BeNil())
+				Expect(*result.Version).To(Equal("3"))
+				Expect(*result.SharedDatasetName).To(Equal("network"))
+				Expect(*result.SharedDatasetData[0].DefaultValue).To(Equal("us-south"))
+
+				// The rollback saved the replaced version, so that it can be restored.
+				diff, err := schematicsv1.DiffSharedDatasetVersions(store, "sd-1", "1", "2")
+				Expect(err).To(BeNil())
+				Expect(diff.Data[0].Fields).To(Equal([]string{"default_value"}))
+
+				_, _, operationErr = schematicsService.RollbackSharedDataset(schematicsService.NewRollbackSharedDatasetOptions("sd-1", "9", store))
+				Expect(operationErr).To(BeAssignableToTypeOf(&schematicsv1.SharedDatasetSnapshotNotFoundError{}))
+				Expect(puts).To(Equal(2))
+
+				// The snapshots do not depend on the HTTP client of the service.
+				schematicsService.Service.EnableRetries(1, 0)
+				_, _, operationErr = schematicsService.ReplaceSharedDatasetWithSnapshot(replaceOptions, store)
+				Expect(operationErr).To(BeNil())
+				snapshots, _ = store.ListSnapshots("sd-1")
+				Expect(snapshots).To(HaveLen(3))
+			})
+			It(`Invoke RollbackSharedDataset with error: Masked secure data`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.BearerTokenAuthenticator{BearerToken: "token"},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// The service masks secure data in the dataset that the snapshot is taken from.
+				current["shared_dataset_data"] = []interface{}{
+					map[string]interface{}{"var_name": "region", "default_value": "us-south"},
+					map[string]interface{}{"var_name": "api_key", "default_value": "********", "secure": true},
+					map[string]interface{}{"var_name": "token", "secure": true},
+				}
+				store := &schematicsv1.MemorySnapshotStore{}
+				_, _, operationErr := schematicsService.ReplaceSharedDatasetWithSnapshot(schematicsService.NewReplaceSharedDatasetOptions("sd-1"), store)
+				Expect(operationErr).To(BeNil())
+				Expect(puts).To(Equal(1))
+
+				_, _, operationErr = schematicsService.RollbackSharedDataset(schematicsService.NewRollbackSharedDatasetOptions("sd-1", "1", store))
+				Expect(operationErr).To(Equal(&schematicsv1.SharedDatasetMaskedSecureDataError{SharedDatasetID: "sd-1", Version: "1", VarNames: []string{"api_key", "token"}}))
+				Expect(operationErr).To(MatchError("can not roll back shared dataset sd-1 to version 1: the snapshot masks the values of secure data api_key, token"))
+				Expect(puts).To(Equal(1))
+			})
+			It(`Invoke ReplaceSharedDatasetWithSnapshot with error: Snapshot failed`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.BearerTokenAuthenticator{BearerToken: "token"},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				failGet = true
+				_, _, operationErr := schematicsService.ReplaceSharedDatasetWithSnapshot(schematicsService.NewReplaceSharedDatasetOptions("sd-1"), &schematicsv1.MemorySnapshotStore{})
+				Expect(operationErr).To(MatchError(ContainSubstring("can not snapshot shared dataset sd-1 before replacing it")))
+				Expect(puts).To(Equal(0))
+			})
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,494 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+)
+
+// Constants associated with the SharedDatasetVariableDiff.Status property.
+// How the workspace variable compares with the shared dataset.
+const (
+	SharedDatasetVariableDiff_Status_Changed = "changed"
+	SharedDatasetVariableDiff_Status_InSync  = "in_sync"
+	SharedDatasetVariableDiff_Status_Secure  = "secure"
+)
+
+// Value returns the value of the shared dataset data: its override value, or its default value.
+func (data *SharedDatasetData) Value() string {
+	if data.OverrideValue != nil {
+		return *data.OverrideValue
+	}
+	return stringValue(data.DefaultValue)
+}
+
+// matchesVariable reports whether a workspace variable name is the name or one of the aliases of the data.
+func (data *SharedDatasetData) matchesVariable(name string) bool {
+	return name != "" && (name == stringValue(data.VarName) || containsString(data.VarAliases, name))
+}
+
+// SharedDatasetVariableDiff : How a workspace variable compares with the shared dataset data it comes from.
+type SharedDatasetVariableDiff struct {
+	// The name of the workspace variable.
+	Name string `json:"name"`
+
+	// The name of the shared dataset data, when the variable matches one of its aliases.
+	VarName string `json:"var_name,omitempty"`
+
+	// How the variable compares with the shared dataset.
+	Status string `json:"status"`
+
+	// The value in the shared dataset. Not set for secure variables.
+	DatasetValue string `json:"dataset_value,omitempty"`
+
+	// The value in the workspace. Not set for secure variables.
+	WorkspaceValue string `json:"workspace_value,omitempty"`
+}
+
+// SharedDatasetTemplateDiff : The shared dataset variables of a workspace template.
+type SharedDatasetTemplateDiff struct {
+	// The template ID.
+	TID string `json:"t_id"`
+
+	// The template variables that come from the shared dataset.
+	Variables []SharedDatasetVariableDiff `json:"variables,omitempty"`
+}
+
+// SharedDatasetWorkspaceDiff : How the variables of a workspace compare with a shared dataset.
+type SharedDatasetWorkspaceDiff struct {
+	// The workspace ID.
+	WID string `json:"w_id"`
+
+	// The templates of the workspace.
+	Templates []SharedDatasetTemplateDiff `json:"templates,omitempty"`
+
+	// The shared dataset data that no template of the workspace has a variable for.
+	MissingVariables []string `json:"missing_variables,omitempty"`
+
+	// The error that stopped the comparison, if any.
+	Error error `json:"-"`
+}
+
+// IsStale reports whether a variable of the workspace differs from the shared dataset.
+func (diff *SharedDatasetWorkspaceDiff) IsStale() bool {
+	for _, template := range diff.Templates {
+		for _, variable := range template.Variables {
+			if variable.Status == SharedDatasetVariableDiff_Status_Changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SharedDatasetDiff : How the affected workspaces of a shared dataset compare with it.
+type SharedDatasetDiff struct {
+	// The shared dataset ID.
+	SharedDatasetID string `json:"shared_dataset_id"`
+
+	// The shared dataset version that was compared.
+	Version string `json:"version,omitempty"`
+
+	// The workspaces, in the order they were compared.
+	Workspaces []SharedDatasetWorkspaceDiff `json:"workspaces,omitempty"`
+}
+
+// StaleWorkspaces returns the IDs of the workspaces that differ from the shared dataset.
+func (diff *SharedDatasetDiff) StaleWorkspaces() (wIDs []string) {
+	for i := range diff.Workspaces {
+		if diff.Workspaces[i].IsStale() {
+			wIDs = append(wIDs, diff.Workspaces[i].WID)
+		}
+	}
+	return
+}
+
+// CompareSharedDatasetWorkspace compares the template variables of a workspace, as returned by GetAllWorkspaceInputs,
+// with a shared dataset. A variable comes from the dataset when its name is the name or an alias of dataset data.
+// Secure variables are reported without their values and are never changed, because their values can not be read.
+func CompareSharedDatasetWorkspace(dataset *SharedDatasetResponse, wID string, inputs *WorkspaceTemplateValuesResponse) *SharedDatasetWorkspaceDiff {
+	diff := &SharedDatasetWorkspaceDiff{WID: wID}
+	found := map[string]bool{}
+	for _, template := range inputs.TemplateData {
+		templateDiff := SharedDatasetTemplateDiff{TID: stringValue(template.ID)}
+		for _, variable := range template.Variablestore {
+			name := stringValue(variable.Name)
+			data := findSharedDatasetData(dataset, name)
+			if data == nil {
+				continue
+			}
+			found[stringValue(data.VarName)] = true
+			variableDiff := SharedDatasetVariableDiff{Name: name, Status: SharedDatasetVariableDiff_Status_InSync}
+			if name != stringValue(data.VarName) {
+				variableDiff.VarName = stringValue(data.VarName)
+			}
+			switch {
+			case isTrue(variable.Secure) || isTrue(data.Secure):
+				variableDiff.Status = SharedDatasetVariableDiff_Status_Secure
+			default:
+				variableDiff.DatasetValue = data.Value()
+				variableDiff.WorkspaceValue = stringValue(variable.Value)
+				if variableDiff.DatasetValue != variableDiff.WorkspaceValue {
+					variableDiff.Status = SharedDatasetVariableDiff_Status_Changed
+				}
+			}
+			templateDiff.Variables = append(templateDiff.Variables, variableDiff)
+		}
+		diff.Templates = append(diff.Templates, templateDiff)
+	}
+	for _, data := range dataset.SharedDatasetData {
+		if name := stringValue(data.VarName); name != "" && !found[name] {
+			diff.MissingVariables = append(diff.MissingVariables, name)
+		}
+	}
+	return diff
+}
+
+func findSharedDatasetData(dataset *SharedDatasetResponse, name string) *SharedDatasetData {
+	for i := range dataset.SharedDatasetData {
+		if dataset.SharedDatasetData[i].matchesVariable(name) {
+			return &dataset.SharedDatasetData[i]
+		}
+	}
+	return nil
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// DiffSharedDatasetOptions : The DiffSharedDataset options.
+type DiffSharedDatasetOptions struct {
+	// The shared dataset ID Use the GET /shared_datasets to look up the shared dataset IDs  in your IBM Cloud account.
+	SdID *string `json:"sd_id" validate:"required,ne="`
+
+	// The shared dataset version to compare with, for example a saved copy. Defaults to the current dataset.
+	Dataset *SharedDatasetResponse `json:"dataset,omitempty"`
+
+	// The workspaces to compare. Defaults to the affected workspaces of the current dataset.
+	WIDs []string `json:"w_ids,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDiffSharedDatasetOptions : Instantiate DiffSharedDatasetOptions
+func (*SchematicsV1) NewDiffSharedDatasetOptions(sdID string) *DiffSharedDatasetOptions {
+	return &DiffSharedDatasetOptions{
+		SdID: core.StringPtr(sdID),
+	}
+}
+
+// SetSdID : Allow user to set SdID
+func (options *DiffSharedDatasetOptions) SetSdID(sdID string) *DiffSharedDatasetOptions {
+	options.SdID = core.StringPtr(sdID)
+	return options
+}
+
+// SetDataset : Allow user to set Dataset
+func (options *DiffSharedDatasetOptions) SetDataset(dataset *SharedDatasetResponse) *DiffSharedDatasetOptions {
+	options.Dataset = dataset
+	return options
+}
+
+// SetWIDs : Allow user to set WIDs
+func (options *DiffSharedDatasetOptions) SetWIDs(wIDs []string) *DiffSharedDatasetOptions {
+	options.WIDs = wIDs
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DiffSharedDatasetOptions) SetHeaders(param map[string]string) *DiffSharedDatasetOptions {
+	options.Headers = param
+	return options
+}
+
+// DiffSharedDataset : Compare a shared dataset with the workspaces that use it
+// Get the shared dataset and compare the template variables of each affected workspace with it, using
+// CompareSharedDatasetWorkspace. A failure on one workspace is recorded in its diff and does not stop the others.
+func (schematics *SchematicsV1) DiffSharedDataset(diffSharedDatasetOptions *DiffSharedDatasetOptions) (result *SharedDatasetDiff, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(diffSharedDatasetOptions, "diffSharedDatasetOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(diffSharedDatasetOptions, "diffSharedDatasetOptions")
+	if err != nil {
+		return
+	}
+
+	dataset := diffSharedDatasetOptions.Dataset
+	wIDs := diffSharedDatasetOptions.WIDs
+	if dataset == nil || wIDs == nil {
+		var current *SharedDatasetResponse
+		current, response, err = schematics.GetSharedDataset(&GetSharedDatasetOptions{
+			SdID:    diffSharedDatasetOptions.SdID,
+			Headers: diffSharedDatasetOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		if dataset == nil {
+			dataset = current
+		}
+		if wIDs == nil {
+			wIDs = current.EffectedWorkspaceIds
+		}
+	}
+
+	result = &SharedDatasetDiff{SharedDatasetID: *diffSharedDatasetOptions.SdID, Version: stringValue(dataset.Version)}
+	for _, wID := range wIDs {
+		inputs, inputsResponse, inputsErr := schematics.GetAllWorkspaceInputs(&GetAllWorkspaceInputsOptions{
+			WID:     core.StringPtr(wID),
+			Headers: diffSharedDatasetOptions.Headers,
+		})
+		if inputsResponse != nil {
+			response = inputsResponse
+		}
+		if inputsErr != nil {
+			result.Workspaces = append(result.Workspaces, SharedDatasetWorkspaceDiff{WID: wID, Error: inputsErr})
+			continue
+		}
+		result.Workspaces = append(result.Workspaces, *CompareSharedDatasetWorkspace(dataset, wID, inputs))
+	}
+	return
+}
+
+// SyncSharedDatasetOptions : The SyncSharedDataset options.
+type SyncSharedDatasetOptions struct {
+	// The shared dataset ID Use the GET /shared_datasets to look up the shared dataset IDs  in your IBM Cloud account.
+	SdID *string `json:"sd_id" validate:"required,ne="`
+
+	// The shared dataset version to push, for example a saved copy. Defaults to the current dataset.
+	Dataset *SharedDatasetResponse `json:"dataset,omitempty"`
+
+	// The workspaces to update. Defaults to the affected workspaces of the current dataset.
+	WIDs []string `json:"w_ids,omitempty"`
+
+	// Compute the ReplaceWorkspaceInputs requests without sending them.
+	DryRun *bool `json:"dry_run,omitempty"`
+
+	// Run a plan on each updated workspace.
+	Plan *bool `json:"plan,omitempty"`
+
+	// The IAM refresh token associated with the IBM Cloud account. Required to run a plan.
+	RefreshToken *string `json:"refresh_token,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewSyncSharedDatasetOptions : Instantiate SyncSharedDatasetOptions
+func (*SchematicsV1) NewSyncSharedDatasetOptions(sdID string) *SyncSharedDatasetOptions {
+	return &SyncSharedDatasetOptions{
+		SdID: core.StringPtr(sdID),
+	}
+}
+
+// SetSdID : Allow user to set SdID
+func (options *SyncSharedDatasetOptions) SetSdID(sdID string) *SyncSharedDatasetOptions {
+	options.SdID = core.StringPtr(sdID)
+	return options
+}
+
+// SetDataset : Allow user to set Dataset
+func (options *SyncSharedDatasetOptions) SetDataset(dataset *SharedDatasetResponse) *SyncSharedDatasetOptions {
+	options.Dataset = dataset
+	return options
+}
+
+// SetWIDs : Allow user to set WIDs
+func (options *SyncSharedDatasetOptions) SetWIDs(wIDs []string) *SyncSharedDatasetOptions {
+	options.WIDs = wIDs
+	return options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (options *SyncSharedDatasetOptions) SetDryRun(dryRun bool) *SyncSharedDatasetOptions {
+	options.DryRun = core.BoolPtr(dryRun)
+	return options
+}
+
+// SetPlan : Allow user to set Plan
+func (options *SyncSharedDatasetOptions) SetPlan(plan bool) *SyncSharedDatasetOptions {
+	options.Plan = core.BoolPtr(plan)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *SyncSharedDatasetOptions) SetRefreshToken(refreshToken string) *SyncSharedDatasetOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *SyncSharedDatasetOptions) SetHeaders(param map[string]string) *SyncSharedDatasetOptions {
+	options.Headers = param
+	return options
+}
+
+// SharedDatasetWorkspaceSync : The update of one workspace by SyncSharedDataset.
+type SharedDatasetWorkspaceSync struct {
+	// The workspace ID.
+	WID string `json:"w_id"`
+
+	// The ReplaceWorkspaceInputs requests for the stale templates, sent unless DryRun is set.
+	Replacements []*ReplaceWorkspaceInputsOptions `json:"replacements,omitempty"`
+
+	// The ID of the plan activity, when a plan was run.
+	PlanActivityID string `json:"plan_activity_id,omitempty"`
+
+	// The error that stopped the update, if any.
+	Error error `json:"-"`
+}
+
+// SharedDatasetSyncResult : The result of SyncSharedDataset.
+type SharedDatasetSyncResult struct {
+	// The comparison the updates are based on.
+	Diff *SharedDatasetDiff `json:"diff"`
+
+	// The stale workspaces, in the order they were updated.
+	Workspaces []SharedDatasetWorkspaceSync `json:"workspaces,omitempty"`
+}
+
+// SyncSharedDataset : Push a shared dataset to the workspaces that use it
+// Compare the shared dataset with its affected workspaces with DiffSharedDataset and replace the inputs of each stale
+// template with ReplaceWorkspaceInputs, keeping its other variables, environment values and values. With DryRun set
+// the requests are returned without being sent; with Plan set a plan is run on each updated workspace. A template
+// that has secure variables is not updated, because their values can not be read back and would be lost. A failure on
+// one workspace is recorded in its result and does not stop the others.
+func (schematics *SchematicsV1) SyncSharedDataset(syncSharedDatasetOptions *SyncSharedDatasetOptions) (result *SharedDatasetSyncResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(syncSharedDatasetOptions, "syncSharedDatasetOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(syncSharedDatasetOptions, "syncSharedDatasetOptions")
+	if err != nil {
+		return
+	}
+	dryRun, plan := isTrue(syncSharedDatasetOptions.DryRun), isTrue(syncSharedDatasetOptions.Plan)
+	if plan && !dryRun && stringValue(syncSharedDatasetOptions.RefreshToken) == "" {
+		err = fmt.Errorf("RefreshToken is required to run a plan")
+		return
+	}
+
+	diff, response, err := schematics.DiffSharedDataset(&DiffSharedDatasetOptions{
+		SdID:    syncSharedDatasetOptions.SdID,
+		Dataset: syncSharedDatasetOptions.Dataset,
+		WIDs:    syncSharedDatasetOptions.WIDs,
+		Headers: syncSharedDatasetOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	result = &SharedDatasetSyncResult{Diff: diff}
+	for i := range diff.Workspaces {
+		workspaceDiff := &diff.Workspaces[i]
+		if workspaceDiff.Error != nil || !workspaceDiff.IsStale() {
+			continue
+		}
+		sync := SharedDatasetWorkspaceSync{WID: workspaceDiff.WID}
+		sync.Replacements, response, sync.Error = schematics.sharedDatasetReplacements(workspaceDiff, syncSharedDatasetOptions.Headers)
+		if sync.Error == nil && !dryRun {
+			for _, replacement := range sync.Replacements {
+				_, response, sync.Error = schematics.ReplaceWorkspaceInputs(replacement)
+				if sync.Error != nil {
+					break
+				}
+			}
+		}
+		if sync.Error == nil && plan && !dryRun {
+			var activity *WorkspaceActivityPlanResult
+			activity, response, sync.Error = schematics.PlanWorkspaceCommand(&PlanWorkspaceCommandOptions{
+				WID:          core.StringPtr(workspaceDiff.WID),
+				RefreshToken: syncSharedDatasetOptions.RefreshToken,
+				Headers:      syncSharedDatasetOptions.Headers,
+			})
+			if sync.Error == nil {
+				sync.PlanActivityID = stringValue(activity.Activityid)
+			}
+		}
+		result.Workspaces = append(result.Workspaces, sync)
+	}
+	return
+}
+
+// sharedDatasetReplacements reads the inputs of a stale workspace again and builds the ReplaceWorkspaceInputs requests
+// that set the changed variables of its templates.
+func (schematics *SchematicsV1) sharedDatasetReplacements(workspaceDiff *SharedDatasetWorkspaceDiff, headers map[string]string) (replacements []*ReplaceWorkspaceInputsOptions, response *core.DetailedResponse, err error) {
+	inputs, response, err := schematics.GetAllWorkspaceInputs(&GetAllWorkspaceInputsOptions{
+		WID:     core.StringPtr(workspaceDiff.WID),
+		Headers: headers,
+	})
+	if err != nil {
+		return
+	}
+	for _, templateDiff := range workspaceDiff.Templates {
+		changed := map[string]string{}
+		for _, variable := range templateDiff.Variables {
+			if variable.Status == SharedDatasetVariableDiff_Status_Changed {
+				changed[variable.Name] = variable.DatasetValue
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		var template *TemplateSourceDataResponse
+		for i := range inputs.TemplateData {
+			if stringValue(inputs.TemplateData[i].ID) == templateDiff.TID {
+				template = &inputs.TemplateData[i]
+			}
+		}
+		if template == nil {
+			err = fmt.Errorf("template %s of workspace %s no longer exists", templateDiff.TID, workspaceDiff.WID)
+			return
+		}
+		var secure []string
+		for _, variable := range template.Variablestore {
+			if isTrue(variable.Secure) {
+				secure = append(secure, stringValue(variable.Name))
+			}
+		}
+		for _, env := range template.EnvValues {
+			if isTrue(env.Secure) {
+				secure = append(secure, stringValue(env.Name))
+			}
+		}
+		if len(secure) > 0 {
+			err = fmt.Errorf("template %s of workspace %s has secure variables %s that would be lost", templateDiff.TID, workspaceDiff.WID, strings.Join(secure, ", "))
+			return
+		}
+
+		request := newTemplateSourceDataRequest(*template, "")
+		for i := range request.Variablestore {
+			if value, ok := changed[stringValue(request.Variablestore[i].Name)]; ok {
+				request.Variablestore[i].Value = core.StringPtr(value)
+			}
+		}
+		replacements = append(replacements, &ReplaceWorkspaceInputsOptions{
+			WID:           core.StringPtr(workspaceDiff.WID),
+			TID:           core.StringPtr(templateDiff.TID),
+			EnvValues:     request.EnvValues,
+			Values:        request.Values,
+			Variablestore: request.Variablestore,
+			Headers:       headers,
+		})
+	}
+	return
+}
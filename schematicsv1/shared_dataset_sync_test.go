@@ -0,0 +1,237 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+var _ = Describe(`Shared dataset sync`, func() {
+	var testServer *httptest.Server
+	datasetJSON := `{
+		"shared_dataset_id": "sd-1",
+		"version": "2",
+		"effected_workspace_ids": ["ws-stale", "ws-synced", "ws-secure", "ws-missing"],
+		"shared_dataset_data": [
+			{"var_name": "region", "default_value": "us-south", "override_value": "eu-de"},
+			{"var_name": "cluster_name", "var_aliases": ["cluster"], "default_value": "prod"},
+			{"var_name": "api_key", "secure": true},
+			{"var_name": "zone", "default_value": "1"}
+		]
+	}`
+	inputsJSON := map[string]string{
+		"ws-stale": `{"template_data": [{"id": "t-1", "values": "a = 1", "env_values": [{"name": "TF_LOG", "value": "DEBUG"}], "variablestore": [
+			{"name": "region", "value": "us-south", "type": "string"},
+			{"name": "cluster", "value": "prod"},
+			{"name": "image", "value": "ubuntu"}
+		]}]}`,
+		"ws-synced": `{"template_data": [{"id": "t-2", "variablestore": [
+			{"name": "region", "value": "eu-de"},
+			{"name": "api_key", "secure": true}
+		]}]}`,
+		"ws-secure": `{"template_data": [{"id": "t-3", "variablestore": [
+			{"name": "region", "value": "us-east"},
+			{"name": "password", "secure": true}
+		]}]}`,
+	}
+
+	Describe(`CompareSharedDatasetWorkspace(dataset *SharedDatasetResponse, wID string, inputs *WorkspaceTemplateValuesResponse)`, func() {
+		It(`Compare the variables of a workspace with a shared dataset`, func() {
+			var dataset schematicsv1.SharedDatasetResponse
+			Expect(json.Unmarshal([]byte(datasetJSON), &dataset)).To(Succeed())
+			var inputs schematicsv1.WorkspaceTemplateValuesResponse
+			Expect(json.Unmarshal([]byte(inputsJSON["ws-stale"]), &inputs)).To(Succeed())
+
+			diff := schematicsv1.CompareSharedDatasetWorkspace(&dataset, "ws-stale", &inputs)
+			Expect(diff.WID).To(Equal("ws-stale"))
+			Expect(diff.IsStale()).To(BeTrue())
+			Expect(diff.MissingVariables).To(Equal([]string{"api_key", "zone"}))
+			Expect(diff.Templates).To(HaveLen(1))
+			Expect(diff.Templates[0].TID).To(Equal("t-1"))
+			Expect(diff.Templates[0].Variables).To(Equal([]schematicsv1.SharedDatasetVariableDiff{
+				{Name: "region", Status: schematicsv1.SharedDatasetVariableDiff_Status_Changed, DatasetValue: "eu-de", WorkspaceValue: "us-south"},
+				{Name: "cluster", VarName: "cluster_name", Status: schematicsv1.SharedDatasetVariableDiff_Status_InSync, DatasetValue: "prod", WorkspaceValue: "prod"},
+			}))
+
+			Expect(json.Unmarshal([]byte(inputsJSON["ws-synced"]), &inputs)).To(Succeed())
+			diff = schematicsv1.CompareSharedDatasetWorkspace(&dataset, "ws-synced", &inputs)
+			Expect(diff.IsStale()).To(BeFalse())
+			Expect(diff.Templates[0].Variables[1].Status).To(Equal(schematicsv1.SharedDatasetVariableDiff_Status_Secure))
+			Expect(diff.Templates[0].Variables[1].DatasetValue).To(BeEmpty())
+		})
+	})
+	Describe(`DiffSharedDataset and SyncSharedDataset`, func() {
+		var mutex sync.Mutex
+		var calls []string
+		var replaced map[string]map[string]interface{}
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				calls = nil
+				replaced = map[string]map[string]interface{}{}
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					mutex.Lock()
+					defer mutex.Unlock()
+					path := req.URL.EscapedPath()
+					calls = append(calls, req.Method+" "+path)
+					res.Header().Set("Content-type", "application/json")
+					parts := strings.Split(path, "/")
+					switch {
+					case req.Method == "GET" && path == "/v2/shared_datasets/sd-1":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", datasetJSON)
+					case req.Method == "GET" && strings.HasSuffix(path, "/templates/values"):
+						inputs, ok := inputsJSON[parts[3]]
+						if !ok {
+							res.WriteHeader(404)
+							return
+						}
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", inputs)
+					case req.Method == "PUT" && strings.HasSuffix(path, "/values"):
+						body, _ := ioutil.ReadAll(req.Body)
+						var request map[string]interface{}
+						Expect(json.Unmarshal(body, &request)).To(Succeed())
+						replaced[parts[3]+"/"+parts[5]] = request
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", body)
+					case req.Method == "POST" && strings.HasSuffix(path, "/plan"):
+						res.WriteHeader(202)
+						fmt.Fprintf(res, `{"activityid": "plan-%s"}`, parts[3])
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke DiffSharedDataset successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.DiffSharedDataset(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				result, response, operationErr = schematicsService.DiffSharedDataset(schematicsService.NewDiffSharedDatasetOptions("sd-1"))
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result.SharedDatasetID).To(Equal("sd-1"))
+				Expect(result.Version).To(Equal("2"))
+				Expect(result.Workspaces).To(HaveLen(4))
+				Expect(result.Workspaces[3].Error).ToNot(BeNil())
+				Expect(result.StaleWorkspaces()).To(Equal([]string{"ws-stale", "ws-secure"}))
+			})
+			It(`Invoke DiffSharedDataset with a given dataset and workspaces`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				dataset := &schematicsv1.SharedDatasetResponse{
+					Version: core.StringPtr("1"),
+					SharedDatasetData: []schematicsv1.SharedDatasetData{
+						{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("eu-de")},
+					},
+				}
+				diffOptions := schematicsService.NewDiffSharedDatasetOptions("sd-1").SetDataset(dataset).SetWIDs([]string{"ws-synced"})
+				result, _, operationErr := schematicsService.DiffSharedDataset(diffOptions)
+				Expect(operationErr).To(BeNil())
+				Expect(result.Version).To(Equal("1"))
+				Expect(result.StaleWorkspaces()).To(BeEmpty())
+				Expect(calls).To(Equal([]string{"GET /v1/workspaces/ws-synced/templates/values"}))
+			})
+			It(`Invoke SyncSharedDataset as a dry run`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				syncOptions := schematicsService.NewSyncSharedDatasetOptions("sd-1").SetDryRun(true).SetPlan(true)
+				result, _, operationErr := schematicsService.SyncSharedDataset(syncOptions)
+				Expect(operationErr).To(BeNil())
+				Expect(result.Diff.StaleWorkspaces()).To(Equal([]string{"ws-stale", "ws-secure"}))
+				Expect(result.Workspaces).To(HaveLen(2))
+
+				Expect(result.Workspaces[0].Error).To(BeNil())
+				Expect(result.Workspaces[0].PlanActivityID).To(BeEmpty())
+				Expect(result.Workspaces[0].Replacements).To(HaveLen(1))
+				replacement := result.Workspaces[0].Replacements[0]
+				Expect(*replacement.WID).To(Equal("ws-stale"))
+				Expect(*replacement.TID).To(Equal("t-1"))
+				Expect(*replacement.Values).To(Equal("a = 1"))
+				Expect(replacement.EnvValues).To(HaveLen(1))
+				Expect(replacement.Variablestore).To(HaveLen(3))
+				Expect(*replacement.Variablestore[0].Value).To(Equal("eu-de"))
+				Expect(*replacement.Variablestore[2].Value).To(Equal("ubuntu"))
+
+				Expect(result.Workspaces[1].Error).To(MatchError(ContainSubstring("secure variables password")))
+				for _, call := range calls {
+					Expect(call).ToNot(HavePrefix("PUT"))
+					Expect(call).ToNot(HavePrefix("POST"))
+				}
+			})
+			It(`Invoke SyncSharedDataset with a plan`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				syncOptions := schematicsService.NewSyncSharedDatasetOptions("sd-1").SetPlan(true)
+				result, _, operationErr := schematicsService.SyncSharedDataset(syncOptions)
+				Expect(operationErr).To(MatchError(ContainSubstring("RefreshToken")))
+				Expect(result).To(BeNil())
+				Expect(calls).To(BeEmpty())
+
+				result, _, operationErr = schematicsService.SyncSharedDataset(syncOptions.SetRefreshToken("testString"))
+				Expect(operationErr).To(BeNil())
+				Expect(result.Workspaces).To(HaveLen(2))
+				Expect(result.Workspaces[0].Error).To(BeNil())
+				Expect(result.Workspaces[0].PlanActivityID).To(Equal("plan-ws-stale"))
+				Expect(result.Workspaces[1].Error).ToNot(BeNil())
+				Expect(result.Workspaces[1].PlanActivityID).To(BeEmpty())
+
+				Expect(replaced).To(HaveLen(1))
+				variablestore := replaced["ws-stale/t-1"]["variablestore"].([]interface{})
+				Expect(variablestore[0]).To(HaveKeyWithValue("value", "eu-de"))
+				Expect(variablestore[1]).To(HaveKeyWithValue("value", "prod"))
+				Expect(calls).To(ContainElement("POST /v1/workspaces/ws-stale/plan"))
+				Expect(calls).ToNot(ContainElement("POST /v1/workspaces/ws-secure/plan"))
+			})
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
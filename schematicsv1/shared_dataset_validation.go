@@ -0,0 +1,283 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SharedDatasetViolation : A constraint of shared dataset data that is not respected.
+type SharedDatasetViolation struct {
+	// The variable name of the data.
+	VarName string `json:"var_name"`
+
+	// The field with the problem, such as 'shared_dataset_data[1].default_value'.
+	Field string `json:"field"`
+
+	// The description of the problem.
+	Message string `json:"message"`
+}
+
+// SharedDatasetValidationError is returned by ValidateCreateSharedDatasetOptions, ValidateReplaceSharedDatasetOptions
+// and ParseSharedDatasetConstraints with all the violations found in shared dataset data.
+type SharedDatasetValidationError struct {
+	// The violations, in data order.
+	Violations []SharedDatasetViolation `json:"violations"`
+}
+
+// Error implements the error interface.
+func (e *SharedDatasetValidationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.Field + ": " + violation.Message
+	}
+	return "invalid shared dataset: " + strings.Join(violations, "; ")
+}
+
+// ByVariable returns the violations grouped by variable name.
+func (e *SharedDatasetValidationError) ByVariable() map[string][]SharedDatasetViolation {
+	byVariable := map[string][]SharedDatasetViolation{}
+	for _, violation := range e.Violations {
+		byVariable[violation.VarName] = append(byVariable[violation.VarName], violation)
+	}
+	return byVariable
+}
+
+// SharedDatasetConstraints : The parsed constraints of shared dataset data.
+type SharedDatasetConstraints struct {
+	// The regular expression the whole value must match.
+	Matches *regexp.Regexp
+
+	// The minimum and maximum numeric value.
+	MinValue, MaxValue *float64
+
+	// The minimum and maximum length of the value, in characters.
+	MinValueLen, MaxValueLen *int
+
+	// The allowed values.
+	Options []string
+
+	// Whether the data can not be changed once created.
+	Immutable bool
+}
+
+// ParseSharedDatasetConstraints parses the Matches, MinValue, MaxValue, MinValueLen, MaxValueLen, Options and
+// Immutable fields of shared dataset data. Constraints that can not be parsed, such as a minimum that is not a number
+// or a minimum greater than the maximum, are returned in a *SharedDatasetValidationError.
+func ParseSharedDatasetConstraints(data *SharedDatasetData) (*SharedDatasetConstraints, error) {
+	var violations []SharedDatasetViolation
+	constraints := parseSharedDatasetConstraints(data, "", &violations)
+	if len(violations) > 0 {
+		return nil, &SharedDatasetValidationError{Violations: violations}
+	}
+	return constraints, nil
+}
+
+func parseSharedDatasetConstraints(data *SharedDatasetData, prefix string, violations *[]SharedDatasetViolation) *SharedDatasetConstraints {
+	varName := stringValue(data.VarName)
+	violation := func(field string, format string, args ...interface{}) {
+		*violations = append(*violations, SharedDatasetViolation{VarName: varName, Field: prefix + field, Message: fmt.Sprintf(format, args...)})
+	}
+	parseNumber := func(field string, value *string) *float64 {
+		if value == nil || *value == "" {
+			return nil
+		}
+		number, err := strconv.ParseFloat(*value, 64)
+		if err != nil {
+			violation(field, "%q is not a number", *value)
+			return nil
+		}
+		return &number
+	}
+	parseLength := func(field string, value *string) *int {
+		if value == nil || *value == "" {
+			return nil
+		}
+		length, err := strconv.Atoi(*value)
+		if err != nil || length < 0 {
+			violation(field, "%q is not a length", *value)
+			return nil
+		}
+		return &length
+	}
+
+	constraints := &SharedDatasetConstraints{
+		MinValue:    parseNumber("min_value", data.MinValue),
+		MaxValue:    parseNumber("max_value", data.MaxValue),
+		MinValueLen: parseLength("min_value_len", data.MinValueLen),
+		MaxValueLen: parseLength("max_value_len", data.MaxValueLen),
+		Options:     data.Options,
+		Immutable:   isTrue(data.Immutable),
+	}
+	if matches := stringValue(data.Matches); matches != "" {
+		var err error
+		constraints.Matches, err = regexp.Compile(`^(?:` + matches + `)$`)
+		if err != nil {
+			violation("matches", "%q is not a regular expression: %s", matches, err)
+		}
+	}
+	if constraints.MinValue != nil && constraints.MaxValue != nil && *constraints.MinValue > *constraints.MaxValue {
+		violation("min_value", "%s is greater than max_value %s", *data.MinValue, *data.MaxValue)
+	}
+	if constraints.MinValueLen != nil && constraints.MaxValueLen != nil && *constraints.MinValueLen > *constraints.MaxValueLen {
+		violation("min_value_len", "%s is greater than max_value_len %s", *data.MinValueLen, *data.MaxValueLen)
+	}
+	return constraints
+}
+
+// Check returns the constraints that the value does not respect, as messages.
+func (constraints *SharedDatasetConstraints) Check(value string) (problems []string) {
+	if constraints.Matches != nil && !constraints.Matches.MatchString(value) {
+		problems = append(problems, fmt.Sprintf("does not match %s", constraints.Matches))
+	}
+	if constraints.MinValue != nil || constraints.MaxValue != nil {
+		number, err := strconv.ParseFloat(value, 64)
+		switch {
+		case err != nil:
+			problems = append(problems, "is not a number")
+		case constraints.MinValue != nil && number < *constraints.MinValue:
+			problems = append(problems, fmt.Sprintf("is less than %v", *constraints.MinValue))
+		case constraints.MaxValue != nil && number > *constraints.MaxValue:
+			problems = append(problems, fmt.Sprintf("is greater than %v", *constraints.MaxValue))
+		}
+	}
+	length := utf8.RuneCountInString(value)
+	if constraints.MinValueLen != nil && length < *constraints.MinValueLen {
+		problems = append(problems, fmt.Sprintf("is shorter than %d characters", *constraints.MinValueLen))
+	}
+	if constraints.MaxValueLen != nil && length > *constraints.MaxValueLen {
+		problems = append(problems, fmt.Sprintf("is longer than %d characters", *constraints.MaxValueLen))
+	}
+	if len(constraints.Options) > 0 && !containsString(constraints.Options, value) {
+		problems = append(problems, fmt.Sprintf("is not one of %s", strings.Join(constraints.Options, ", ")))
+	}
+	return
+}
+
+// ValidateCreateSharedDatasetOptions checks the shared dataset data before it is created: each variable name is set
+// and unique, its constraints can be parsed, and its default and override values respect them. Values are not
+// quoted in the messages because they can be secure. All violations are returned in a single
+// *SharedDatasetValidationError.
+func ValidateCreateSharedDatasetOptions(createSharedDatasetOptions *CreateSharedDatasetOptions) error {
+	return validateSharedDatasetData(createSharedDatasetOptions.SharedDatasetData, nil)
+}
+
+// ValidateReplaceSharedDatasetOptions checks the shared dataset data with the rules of
+// ValidateCreateSharedDatasetOptions. When the current dataset is given, the data that it marks immutable must also be
+// kept unchanged.
+func ValidateReplaceSharedDatasetOptions(replaceSharedDatasetOptions *ReplaceSharedDatasetOptions, current *SharedDatasetResponse) error {
+	return validateSharedDatasetData(replaceSharedDatasetOptions.SharedDatasetData, current)
+}
+
+func validateSharedDatasetData(data []SharedDatasetData, current *SharedDatasetResponse) error {
+	var violations []SharedDatasetViolation
+	seen := map[string]int{}
+	for i := range data {
+		prefix := fmt.Sprintf("shared_dataset_data[%d].", i)
+		varName := stringValue(data[i].VarName)
+		violation := func(field string, format string, args ...interface{}) {
+			violations = append(violations, SharedDatasetViolation{VarName: varName, Field: prefix + field, Message: fmt.Sprintf(format, args...)})
+		}
+		if varName == "" {
+			violation("var_name", "is required")
+		} else if _, duplicate := seen[varName]; duplicate {
+			violation("var_name", "duplicate name %q", varName)
+		} else {
+			seen[varName] = i
+		}
+
+		constraints := parseSharedDatasetConstraints(&data[i], prefix, &violations)
+		for _, value := range []struct {
+			field string
+			value *string
+		}{{"default_value", data[i].DefaultValue}, {"override_value", data[i].OverrideValue}} {
+			if value.value == nil {
+				continue
+			}
+			for _, problem := range constraints.Check(*value.value) {
+				violation(value.field, "%s", problem)
+			}
+		}
+	}
+
+	if current != nil {
+		for _, immutable := range current.SharedDatasetData {
+			varName := stringValue(immutable.VarName)
+			if !isTrue(immutable.Immutable) || varName == "" {
+				continue
+			}
+			i, found := seen[varName]
+			switch {
+			case !found:
+				violations = append(violations, SharedDatasetViolation{VarName: varName, Field: "shared_dataset_data", Message: fmt.Sprintf("immutable data %q can not be removed", varName)})
+			default:
+				for _, field := range changedImmutableFields(&immutable, &data[i]) {
+					violations = append(violations, SharedDatasetViolation{VarName: varName, Field: fmt.Sprintf("shared_dataset_data[%d].%s", i, field), Message: fmt.Sprintf("immutable data %q can not be changed", varName)})
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &SharedDatasetValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// changedImmutableFields returns the value and constraint fields of immutable data that the replacement changes.
+// Other fields, such as the description, may be filled in by the service and are not compared. The values of secure
+// data are not compared when the service masked them.
+func changedImmutableFields(current *SharedDatasetData, replacement *SharedDatasetData) (fields []string) {
+	compare := func(field string, from interface{}, to interface{}) {
+		if !reflect.DeepEqual(from, to) {
+			fields = append(fields, field)
+		}
+	}
+	for _, value := range []struct {
+		field    string
+		from, to *string
+	}{
+		{"default_value", current.DefaultValue, replacement.DefaultValue},
+		{"override_value", current.OverrideValue, replacement.OverrideValue},
+	} {
+		if isTrue(current.Secure) && isMaskedSecureValue(value.from) {
+			continue
+		}
+		compare(value.field, stringValue(value.from), stringValue(value.to))
+	}
+	compare("immutable", isTrue(current.Immutable), isTrue(replacement.Immutable))
+	compare("matches", stringValue(current.Matches), stringValue(replacement.Matches))
+	compare("max_value", stringValue(current.MaxValue), stringValue(replacement.MaxValue))
+	compare("max_value_len", stringValue(current.MaxValueLen), stringValue(replacement.MaxValueLen))
+	compare("min_value", stringValue(current.MinValue), stringValue(replacement.MinValue))
+	compare("min_value_len", stringValue(current.MinValueLen), stringValue(replacement.MinValueLen))
+	if len(current.Options) > 0 || len(replacement.Options) > 0 {
+		compare("options", current.Options, replacement.Options)
+	}
+	compare("secure", isTrue(current.Secure), isTrue(replacement.Secure))
+	return
+}
+
+// isMaskedSecureValue reports whether a secure value, as returned by the service, hides the actual value.
+func isMaskedSecureValue(value *string) bool {
+	return value == nil || *value == "" || *value == RedactedValue || strings.Trim(*value, "*") == ""
+}
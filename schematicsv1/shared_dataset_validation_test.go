@@ -0,0 +1,176 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Shared dataset validation`, func() {
+	schematicsService, _ := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+		URL:           "http://schematicsv1modelgenerator.com",
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+
+	Describe(`ParseSharedDatasetConstraints(data *SharedDatasetData)`, func() {
+		It(`Parse and check constraints`, func() {
+			constraints, err := schematicsv1.ParseSharedDatasetConstraints(&schematicsv1.SharedDatasetData{
+				VarName:     core.StringPtr("workers"),
+				Matches:     core.StringPtr("[0-9]+"),
+				MinValue:    core.StringPtr("1"),
+				MaxValue:    core.StringPtr("10"),
+				MaxValueLen: core.StringPtr("2"),
+				Immutable:   core.BoolPtr(true),
+			})
+			Expect(err).To(BeNil())
+			Expect(constraints.Immutable).To(BeTrue())
+			Expect(constraints.Matches.String()).To(Equal("^(?:[0-9]+)$"))
+			Expect(*constraints.MinValue).To(Equal(1.0))
+			Expect(*constraints.MaxValue).To(Equal(10.0))
+			Expect(constraints.MinValueLen).To(BeNil())
+			Expect(*constraints.MaxValueLen).To(Equal(2))
+		})
+		It(`Check values against constraints`, func() {
+			constraints, err := schematicsv1.ParseSharedDatasetConstraints(&schematicsv1.SharedDatasetData{
+				Matches:     core.StringPtr("[0-9]+"),
+				MinValue:    core.StringPtr("1"),
+				MaxValue:    core.StringPtr("10"),
+				MinValueLen: core.StringPtr("1"),
+				Options:     []string{"1", "3", "12"},
+			})
+			Expect(err).To(BeNil())
+			Expect(constraints.Check("3")).To(BeEmpty())
+			Expect(constraints.Check("12")).To(Equal([]string{"is greater than 10"}))
+			Expect(constraints.Check("2")).To(Equal([]string{"is not one of 1, 3, 12"}))
+			Expect(constraints.Check("")).To(Equal([]string{
+				"does not match ^(?:[0-9]+)$",
+				"is not a number",
+				"is shorter than 1 characters",
+				"is not one of 1, 3, 12",
+			}))
+		})
+		It(`Reject constraints that can not be parsed`, func() {
+			constraints, err := schematicsv1.ParseSharedDatasetConstraints(&schematicsv1.SharedDatasetData{
+				VarName:     core.StringPtr("workers"),
+				Matches:     core.StringPtr("[0-9"),
+				MinValue:    core.StringPtr("five"),
+				MinValueLen: core.StringPtr("4"),
+				MaxValueLen: core.StringPtr("2"),
+			})
+			Expect(constraints).To(BeNil())
+			validationErr, ok := err.(*schematicsv1.SharedDatasetValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationErr.Violations).To(HaveLen(3))
+			Expect(validationErr.Violations[0].Field).To(Equal("min_value"))
+			Expect(validationErr.Violations[1].Field).To(Equal("matches"))
+			Expect(validationErr.Violations[2].Field).To(Equal("min_value_len"))
+			Expect(validationErr.ByVariable()).To(HaveKey("workers"))
+		})
+	})
+	Describe(`ValidateCreateSharedDatasetOptions(createSharedDatasetOptions *CreateSharedDatasetOptions)`, func() {
+		It(`Accept valid data`, func() {
+			options := schematicsService.NewCreateSharedDatasetOptions().SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), Options: []string{"us-south", "eu-de"}},
+				{VarName: core.StringPtr("workers"), DefaultValue: core.StringPtr("3"), OverrideValue: core.StringPtr("5"), MaxValue: core.StringPtr("5")},
+			})
+			Expect(schematicsv1.ValidateCreateSharedDatasetOptions(options)).To(Succeed())
+		})
+		It(`Report violations per variable`, func() {
+			options := schematicsService.NewCreateSharedDatasetOptions().SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-east"), Options: []string{"us-south", "eu-de"}},
+				{VarName: core.StringPtr("workers"), DefaultValue: core.StringPtr("3"), OverrideValue: core.StringPtr("8"), MaxValue: core.StringPtr("5")},
+				{VarName: core.StringPtr("api_key"), DefaultValue: core.StringPtr("secret"), Secure: core.BoolPtr(true), MinValueLen: core.StringPtr("32")},
+				{VarName: core.StringPtr("region")},
+				{DefaultValue: core.StringPtr("orphan")},
+			})
+			err := schematicsv1.ValidateCreateSharedDatasetOptions(options)
+			validationErr, ok := err.(*schematicsv1.SharedDatasetValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationErr.Violations).To(Equal([]schematicsv1.SharedDatasetViolation{
+				{VarName: "region", Field: "shared_dataset_data[0].default_value", Message: "is not one of us-south, eu-de"},
+				{VarName: "workers", Field: "shared_dataset_data[1].override_value", Message: "is greater than 5"},
+				{VarName: "api_key", Field: "shared_dataset_data[2].default_value", Message: "is shorter than 32 characters"},
+				{VarName: "region", Field: "shared_dataset_data[3].var_name", Message: `duplicate name "region"`},
+				{VarName: "", Field: "shared_dataset_data[4].var_name", Message: "is required"},
+			}))
+			Expect(validationErr.ByVariable()["region"]).To(HaveLen(2))
+			Expect(err.Error()).ToNot(ContainSubstring("secret"))
+		})
+	})
+	Describe(`ValidateReplaceSharedDatasetOptions(replaceSharedDatasetOptions *ReplaceSharedDatasetOptions, current *SharedDatasetResponse)`, func() {
+		current := &schematicsv1.SharedDatasetResponse{
+			SharedDatasetData: []schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("zone"), DefaultValue: core.StringPtr("1"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("workers"), DefaultValue: core.StringPtr("3")},
+			},
+		}
+		It(`Accept changes to mutable data`, func() {
+			options := schematicsService.NewReplaceSharedDatasetOptions("sd-1").SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("zone"), DefaultValue: core.StringPtr("1"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("workers"), DefaultValue: core.StringPtr("5")},
+			})
+			Expect(schematicsv1.ValidateReplaceSharedDatasetOptions(options, current)).To(Succeed())
+		})
+		It(`Reject changes to immutable data`, func() {
+			options := schematicsService.NewReplaceSharedDatasetOptions("sd-1").SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), OverrideValue: core.StringPtr("eu-de"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("workers"), DefaultValue: core.StringPtr("5")},
+			})
+			err := schematicsv1.ValidateReplaceSharedDatasetOptions(options, current)
+			validationErr, ok := err.(*schematicsv1.SharedDatasetValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationErr.Violations).To(Equal([]schematicsv1.SharedDatasetViolation{
+				{VarName: "region", Field: "shared_dataset_data[0].override_value", Message: `immutable data "region" can not be changed`},
+				{VarName: "zone", Field: "shared_dataset_data", Message: `immutable data "zone" can not be removed`},
+			}))
+
+			// Without the current dataset, only the constraints are checked.
+			Expect(schematicsv1.ValidateReplaceSharedDatasetOptions(options, nil)).To(Succeed())
+		})
+		It(`Compare only the values and constraints of immutable data`, func() {
+			// The service fills in the description and type, and masks secure values.
+			served := &schematicsv1.SharedDatasetResponse{
+				SharedDatasetData: []schematicsv1.SharedDatasetData{
+					{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), Description: core.StringPtr(""), VarType: core.StringPtr("string"), Options: []string{}, Immutable: core.BoolPtr(true)},
+					{VarName: core.StringPtr("api_key"), DefaultValue: core.StringPtr("********"), Secure: core.BoolPtr(true), Immutable: core.BoolPtr(true)},
+					{VarName: core.StringPtr("token"), Secure: core.BoolPtr(true), Immutable: core.BoolPtr(true)},
+				},
+			}
+			options := schematicsService.NewReplaceSharedDatasetOptions("sd-1").SetSharedDatasetData([]schematicsv1.SharedDatasetData{
+				{VarName: core.StringPtr("region"), DefaultValue: core.StringPtr("us-south"), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("api_key"), DefaultValue: core.StringPtr("secret"), Secure: core.BoolPtr(true), Immutable: core.BoolPtr(true)},
+				{VarName: core.StringPtr("token"), DefaultValue: core.StringPtr("secret"), Secure: core.BoolPtr(true), Immutable: core.BoolPtr(true)},
+			})
+			Expect(schematicsv1.ValidateReplaceSharedDatasetOptions(options, served)).To(Succeed())
+
+			options.SharedDatasetData[0].MaxValueLen = core.StringPtr("8")
+			options.SharedDatasetData[1].Secure = core.BoolPtr(false)
+			err := schematicsv1.ValidateReplaceSharedDatasetOptions(options, served)
+			validationErr, ok := err.(*schematicsv1.SharedDatasetValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationErr.Violations).To(Equal([]schematicsv1.SharedDatasetViolation{
+				{VarName: "region", Field: "shared_dataset_data[0].max_value_len", Message: `immutable data "region" can not be changed`},
+				{VarName: "api_key", Field: "shared_dataset_data[1].secure", Message: `immutable data "api_key" can not be changed`},
+			}))
+		})
+	})
+})
@@ -0,0 +1,324 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TerraformAddressWildcard matches any name or instance key in a Terraform address pattern.
+const TerraformAddressWildcard = "*"
+
+var terraformIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// TerraformAddressStep : A name of a Terraform address with its optional instance key.
+type TerraformAddressStep struct {
+	// The name.
+	Name string
+
+	// The instance key: a number, a quoted string or TerraformAddressWildcard. Empty when the step has no key.
+	Key string
+}
+
+// String returns the step as it is written in an address.
+func (step TerraformAddressStep) String() string {
+	if step.Key == "" {
+		return step.Name
+	}
+	return step.Name + "[" + step.Key + "]"
+}
+
+// matches reports whether the pattern step matches a concrete step. A pattern step without key matches every
+// instance.
+func (step TerraformAddressStep) matches(concrete TerraformAddressStep) bool {
+	if step.Name != TerraformAddressWildcard && step.Name != concrete.Name {
+		return false
+	}
+	switch step.Key {
+	case "":
+		return true
+	case TerraformAddressWildcard:
+		return concrete.Key != ""
+	default:
+		return step.Key == concrete.Key
+	}
+}
+
+// TerraformAddress : A parsed Terraform resource or module address.
+// Addresses have the form [module.NAME[KEY].]...[data.]TYPE.NAME[KEY] or module.NAME[KEY][.module.NAME[KEY]]...
+// Patterns may use TerraformAddressWildcard for a name or key, and may end with '.*' to select everything in a
+// module.
+type TerraformAddress struct {
+	// The module path.
+	Modules []TerraformAddressStep
+
+	// True for a data source.
+	Data bool
+
+	// The resource type. Empty for a module address.
+	Type string
+
+	// The resource name and instance key. Empty for a module address.
+	Resource TerraformAddressStep
+
+	// True if the pattern ends with '.*'.
+	AllInModule bool
+}
+
+// ParseTerraformAddress parses a Terraform resource or module address, or an address pattern with wildcards.
+func ParseTerraformAddress(address string) (*TerraformAddress, error) {
+	steps, err := splitTerraformAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid terraform address %q: %s", address, err.Error())
+	}
+
+	parsed := &TerraformAddress{}
+	i := 0
+	for i < len(steps) && steps[i].Name == "module" && steps[i].Key == "" {
+		if i+1 >= len(steps) {
+			return nil, fmt.Errorf("invalid terraform address %q: module name expected", address)
+		}
+		parsed.Modules = append(parsed.Modules, steps[i+1])
+		i += 2
+	}
+
+	rest := steps[i:]
+	switch {
+	case len(rest) == 0:
+		if len(parsed.Modules) == 0 {
+			return nil, fmt.Errorf("invalid terraform address %q: empty address", address)
+		}
+	case len(rest) == 1 && rest[0].Name == TerraformAddressWildcard && rest[0].Key == "":
+		parsed.AllInModule = true
+	default:
+		if rest[0].Name == "data" && rest[0].Key == "" {
+			parsed.Data = true
+			rest = rest[1:]
+		}
+		if len(rest) != 2 {
+			return nil, fmt.Errorf("invalid terraform address %q: expected TYPE.NAME", address)
+		}
+		if rest[0].Key != "" {
+			return nil, fmt.Errorf("invalid terraform address %q: unexpected key after resource type", address)
+		}
+		parsed.Type = rest[0].Name
+		parsed.Resource = rest[1]
+	}
+	return parsed, nil
+}
+
+// splitTerraformAddress splits an address into its dot separated steps and validates their names and keys.
+func splitTerraformAddress(address string) (steps []TerraformAddressStep, err error) {
+	for position := 0; position <= len(address); {
+		end := strings.IndexAny(address[position:], ".[")
+		if end < 0 {
+			end = len(address)
+		} else {
+			end += position
+		}
+		step := TerraformAddressStep{Name: address[position:end]}
+		if step.Name != TerraformAddressWildcard && !terraformIdentifierRegexp.MatchString(step.Name) {
+			return nil, fmt.Errorf("invalid name %q", step.Name)
+		}
+		position = end
+		if position < len(address) && address[position] == '[' {
+			closing := terraformKeyEnd(address, position+1)
+			if closing < 0 {
+				return nil, fmt.Errorf("unterminated instance key")
+			}
+			step.Key, err = normalizeTerraformKey(address[position+1 : closing])
+			if err != nil {
+				return nil, err
+			}
+			position = closing + 1
+		}
+		steps = append(steps, step)
+		if position == len(address) {
+			break
+		}
+		if address[position] != '.' {
+			return nil, fmt.Errorf("unexpected %q", address[position:position+1])
+		}
+		position++
+		if position == len(address) {
+			return nil, fmt.Errorf("trailing dot")
+		}
+	}
+	return
+}
+
+// terraformKeyEnd returns the position of the bracket that closes the instance key starting at start, skipping
+// quoted strings, or -1.
+func terraformKeyEnd(address string, start int) int {
+	quoted := false
+	for i := start; i < len(address); i++ {
+		switch {
+		case quoted && address[i] == '\\':
+			i++
+		case address[i] == '"':
+			quoted = !quoted
+		case !quoted && address[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeTerraformKey validates an instance key and returns it in the form Terraform writes it. Surrounding spaces
+// are dropped and single quoted strings are written with double quotes.
+func normalizeTerraformKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == TerraformAddressWildcard {
+		return key, nil
+	}
+	if index, err := strconv.Atoi(key); err == nil && index >= 0 {
+		return strconv.Itoa(index), nil
+	}
+	if value, err := strconv.Unquote(key); err == nil && strings.HasPrefix(key, `"`) {
+		return strconv.Quote(value), nil
+	}
+	if len(key) >= 2 && strings.HasPrefix(key, "'") && strings.HasSuffix(key, "'") && !strings.Contains(key[1:len(key)-1], "'") {
+		return strconv.Quote(key[1 : len(key)-1]), nil
+	}
+	return "", fmt.Errorf("invalid instance key %q", key)
+}
+
+// String returns the address as Terraform writes it.
+func (address *TerraformAddress) String() string {
+	var parts []string
+	for _, module := range address.Modules {
+		parts = append(parts, "module", module.String())
+	}
+	switch {
+	case address.AllInModule:
+		parts = append(parts, TerraformAddressWildcard)
+	case address.Type != "":
+		if address.Data {
+			parts = append(parts, "data")
+		}
+		parts = append(parts, address.Type, address.Resource.String())
+	}
+	return strings.Join(parts, ".")
+}
+
+// IsModule reports whether the address selects a module rather than a resource.
+func (address *TerraformAddress) IsModule() bool {
+	return address.Type == "" && !address.AllInModule
+}
+
+// HasWildcard reports whether the address is a pattern.
+func (address *TerraformAddress) HasWildcard() bool {
+	if address.AllInModule || address.Type == TerraformAddressWildcard ||
+		address.Resource.Name == TerraformAddressWildcard || address.Resource.Key == TerraformAddressWildcard {
+		return true
+	}
+	for _, module := range address.Modules {
+		if module.Name == TerraformAddressWildcard || module.Key == TerraformAddressWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether the address selects the resource instance at concrete: a module address or a pattern
+// ending with '.*' selects every resource in the module and its children, a resource address without key selects
+// every instance of the resource, and wildcards match any name or key.
+func (address *TerraformAddress) Contains(concrete *TerraformAddress) bool {
+	if len(address.Modules) > len(concrete.Modules) {
+		return false
+	}
+	for i, module := range address.Modules {
+		if !module.matches(concrete.Modules[i]) {
+			return false
+		}
+	}
+	if address.IsModule() || address.AllInModule {
+		return true
+	}
+	if len(address.Modules) != len(concrete.Modules) || address.Data != concrete.Data {
+		return false
+	}
+	if address.Type != TerraformAddressWildcard && address.Type != concrete.Type {
+		return false
+	}
+	return address.Resource.matches(concrete.Resource)
+}
+
+// ExpandTerraformAddresses resolves addresses and address patterns against the resource instance addresses of a
+// state, as returned by TerraformState.Addresses. Patterns are replaced by the instances they select and must select
+// at least one. Addresses without wildcard are returned in canonical form whether or not the state holds them, so
+// that resources that do not exist yet can be targeted. The result is sorted and free of duplicates. An error lists
+// the addresses that are invalid or the patterns that select nothing.
+func ExpandTerraformAddresses(addresses []string, stateAddresses []string) ([]string, error) {
+	return expandTerraformAddresses(addresses, stateAddresses, false)
+}
+
+// ExpandExistingTerraformAddresses resolves addresses like ExpandTerraformAddresses, but also requires addresses
+// without wildcard to select at least one instance of the state.
+func ExpandExistingTerraformAddresses(addresses []string, stateAddresses []string) ([]string, error) {
+	return expandTerraformAddresses(addresses, stateAddresses, true)
+}
+
+func expandTerraformAddresses(addresses []string, stateAddresses []string, requireExisting bool) ([]string, error) {
+	var concrete []*TerraformAddress
+	for _, stateAddress := range stateAddresses {
+		if parsed, err := ParseTerraformAddress(stateAddress); err == nil {
+			concrete = append(concrete, parsed)
+		}
+	}
+
+	var problems []string
+	selected := map[string]bool{}
+	for _, address := range addresses {
+		pattern, err := ParseTerraformAddress(address)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		var matches []string
+		for _, candidate := range concrete {
+			if pattern.Contains(candidate) {
+				matches = append(matches, candidate.String())
+			}
+		}
+		if len(matches) == 0 && (pattern.HasWildcard() || requireExisting) {
+			problems = append(problems, fmt.Sprintf("%s does not match any resource", address))
+			continue
+		}
+		if pattern.HasWildcard() {
+			for _, match := range matches {
+				selected[match] = true
+			}
+		} else {
+			selected[pattern.String()] = true
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid targets: %s", strings.Join(problems, "; "))
+	}
+
+	expanded := make([]string, 0, len(selected))
+	for address := range selected {
+		expanded = append(expanded, address)
+	}
+	sort.Strings(expanded)
+	return expanded, nil
+}
@@ -0,0 +1,130 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Terraform addresses`, func() {
+	stateAddresses := []string{
+		`data.ibm_is_image.image`,
+		`ibm_is_instance.vsi["web"]`,
+		`ibm_is_vpc.vpc`,
+		`module.network.ibm_is_subnet.subnet[0]`,
+		`module.network.ibm_is_subnet.subnet[1]`,
+		`module.network.module.acl["a.b"].ibm_is_network_acl.acl`,
+	}
+
+	Describe(`ParseTerraformAddress(address string)`, func() {
+		It(`Parse resource and module addresses`, func() {
+			address, err := schematicsv1.ParseTerraformAddress(`module.network.module.acl["a.b"].ibm_is_network_acl.acl[0]`)
+			Expect(err).To(BeNil())
+			Expect(address.Modules).To(Equal([]schematicsv1.TerraformAddressStep{{Name: "network"}, {Name: "acl", Key: `"a.b"`}}))
+			Expect(address.Type).To(Equal("ibm_is_network_acl"))
+			Expect(address.Resource).To(Equal(schematicsv1.TerraformAddressStep{Name: "acl", Key: "0"}))
+			Expect(address.IsModule()).To(BeFalse())
+			Expect(address.HasWildcard()).To(BeFalse())
+			Expect(address.String()).To(Equal(`module.network.module.acl["a.b"].ibm_is_network_acl.acl[0]`))
+
+			address, err = schematicsv1.ParseTerraformAddress(`data.ibm_is_image.image`)
+			Expect(err).To(BeNil())
+			Expect(address.Data).To(BeTrue())
+			Expect(address.String()).To(Equal(`data.ibm_is_image.image`))
+
+			address, err = schematicsv1.ParseTerraformAddress(`module.network[1]`)
+			Expect(err).To(BeNil())
+			Expect(address.IsModule()).To(BeTrue())
+
+			address, err = schematicsv1.ParseTerraformAddress(`module.network.*`)
+			Expect(err).To(BeNil())
+			Expect(address.AllInModule).To(BeTrue())
+			Expect(address.HasWildcard()).To(BeTrue())
+			Expect(address.String()).To(Equal(`module.network.*`))
+
+			address, err = schematicsv1.ParseTerraformAddress(`module.a['k'].ibm_is_vpc.vpc[ 0 ]`)
+			Expect(err).To(BeNil())
+			Expect(address.String()).To(Equal(`module.a["k"].ibm_is_vpc.vpc[0]`))
+		})
+		It(`Reject invalid addresses`, func() {
+			for _, invalid := range []string{``, `ibm_is_vpc`, `ibm_is_vpc.vpc.`, `module`, `ibm_is_vpc[0].vpc`, `ibm_is_vpc.vpc[x]`, `ibm_is_vpc.vpc["a"`, `1bad.vpc`, `a.b.c`} {
+				_, err := schematicsv1.ParseTerraformAddress(invalid)
+				Expect(err).ToNot(BeNil(), invalid)
+			}
+		})
+	})
+	Describe(`ExpandTerraformAddresses(addresses []string, stateAddresses []string)`, func() {
+		It(`Expand patterns against the state`, func() {
+			expanded, err := schematicsv1.ExpandTerraformAddresses([]string{`module.network.*`, `ibm_is_vpc.vpc`}, stateAddresses)
+			Expect(err).To(BeNil())
+			Expect(expanded).To(Equal([]string{
+				`ibm_is_vpc.vpc`,
+				`module.network.ibm_is_subnet.subnet[0]`,
+				`module.network.ibm_is_subnet.subnet[1]`,
+				`module.network.module.acl["a.b"].ibm_is_network_acl.acl`,
+			}))
+
+			expanded, err = schematicsv1.ExpandTerraformAddresses([]string{`ibm_is_instance.*`, `module.*.ibm_is_subnet.subnet[*]`}, stateAddresses)
+			Expect(err).To(BeNil())
+			Expect(expanded).To(Equal([]string{
+				`ibm_is_instance.vsi["web"]`,
+				`module.network.ibm_is_subnet.subnet[0]`,
+				`module.network.ibm_is_subnet.subnet[1]`,
+			}))
+		})
+		It(`Keep addresses without wildcards`, func() {
+			expanded, err := schematicsv1.ExpandTerraformAddresses([]string{`module.network.ibm_is_subnet.subnet`, `module.network`}, stateAddresses)
+			Expect(err).To(BeNil())
+			Expect(expanded).To(Equal([]string{`module.network`, `module.network.ibm_is_subnet.subnet`}))
+		})
+		It(`Keep addresses of resources that are not in the state yet`, func() {
+			expanded, err := schematicsv1.ExpandTerraformAddresses([]string{`ibm_is_vpc.new`, `ibm_is_vpc.vpc`}, stateAddresses)
+			Expect(err).To(BeNil())
+			Expect(expanded).To(Equal([]string{`ibm_is_vpc.new`, `ibm_is_vpc.vpc`}))
+		})
+		It(`Reject patterns that select nothing`, func() {
+			_, err := schematicsv1.ExpandTerraformAddresses([]string{`ibm_is_vpc.vcp`, `module.netwrk.*`, `ibm_is_vpc`}, stateAddresses)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).ToNot(ContainSubstring("ibm_is_vpc.vcp"))
+			Expect(err.Error()).To(ContainSubstring("module.netwrk.* does not match any resource"))
+			Expect(err.Error()).To(ContainSubstring(`invalid terraform address "ibm_is_vpc"`))
+		})
+	})
+	Describe(`ExpandExistingTerraformAddresses(addresses []string, stateAddresses []string)`, func() {
+		It(`Reject addresses that select nothing`, func() {
+			expanded, err := schematicsv1.ExpandExistingTerraformAddresses([]string{`module.network`, `ibm_is_vpc.vpc`}, stateAddresses)
+			Expect(err).To(BeNil())
+			Expect(expanded).To(Equal([]string{`ibm_is_vpc.vpc`, `module.network`}))
+
+			_, err = schematicsv1.ExpandExistingTerraformAddresses([]string{`ibm_is_vpc.vcp`, `module.netwrk.*`}, stateAddresses)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("ibm_is_vpc.vcp does not match any resource"))
+			Expect(err.Error()).To(ContainSubstring("module.netwrk.* does not match any resource"))
+		})
+	})
+	Describe(`ValidateTerraformVars(tfVars []string)`, func() {
+		It(`Validate variable values`, func() {
+			Expect(schematicsv1.ValidateTerraformVars([]string{"region=us-south", "tags=[\"a=b\"]", "empty="})).To(Succeed())
+			err := schematicsv1.ValidateTerraformVars([]string{"region", "1x=y"})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"region" is not of the form NAME=VALUE`))
+			Expect(err.Error()).To(ContainSubstring(`invalid variable name "1x"`))
+		})
+	})
+})
@@ -0,0 +1,285 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Constants associated with the TerraformStateResource.Mode property.
+// The mode of the resource.
+const (
+	TerraformStateResource_Mode_Data    = "data"
+	TerraformStateResource_Mode_Managed = "managed"
+)
+
+// TerraformState : The resources recorded in a Terraform state file.
+// TemplateStateStore only exposes the modules of the legacy state format. TerraformState reads the resources of both
+// the legacy format and the format used since Terraform 0.12.
+type TerraformState struct {
+	// The version of the state format.
+	Version *float64 `json:"version,omitempty"`
+
+	// The Terraform version that wrote the state.
+	TerraformVersion *string `json:"terraform_version,omitempty"`
+
+	// The serial of the state.
+	Serial *float64 `json:"serial,omitempty"`
+
+	// The lineage of the state.
+	Lineage *string `json:"lineage,omitempty"`
+
+	// The resources of the state.
+	Resources []TerraformStateResource `json:"resources,omitempty"`
+}
+
+// TerraformStateResource : A resource of a Terraform state.
+type TerraformStateResource struct {
+	// The module path of the resource, such as 'module.network'. Empty for the root module.
+	Module string `json:"module,omitempty"`
+
+	// The mode of the resource.
+	Mode string `json:"mode"`
+
+	// The resource type.
+	Type string `json:"type"`
+
+	// The resource name.
+	Name string `json:"name"`
+
+	// The instances of the resource.
+	Instances []TerraformStateInstance `json:"instances,omitempty"`
+}
+
+// TerraformStateInstance : An instance of a resource of a Terraform state.
+type TerraformStateInstance struct {
+	// The count index (a number) or for_each key (a string) of the instance, nil for a single instance.
+	IndexKey interface{} `json:"index_key,omitempty"`
+
+	// The attributes of the instance.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Address returns the address of the resource, without instance key.
+func (resource *TerraformStateResource) Address() string {
+	address := resource.Type + "." + resource.Name
+	if resource.Mode == TerraformStateResource_Mode_Data {
+		address = "data." + address
+	}
+	if resource.Module != "" {
+		address = resource.Module + "." + address
+	}
+	return address
+}
+
+// InstanceAddress returns the address of an instance of the resource.
+func (resource *TerraformStateResource) InstanceAddress(instance TerraformStateInstance) string {
+	switch key := instance.IndexKey.(type) {
+	case nil:
+		return resource.Address()
+	case string:
+		return fmt.Sprintf("%s[%q]", resource.Address(), key)
+	case float64:
+		return fmt.Sprintf("%s[%s]", resource.Address(), strconv.FormatFloat(key, 'f', -1, 64))
+	default:
+		return fmt.Sprintf("%s[%v]", resource.Address(), key)
+	}
+}
+
+// Addresses returns the sorted addresses of every resource instance of the state.
+func (state *TerraformState) Addresses() []string {
+	var addresses []string
+	for i := range state.Resources {
+		resource := &state.Resources[i]
+		if len(resource.Instances) == 0 {
+			addresses = append(addresses, resource.Address())
+		}
+		for _, instance := range resource.Instances {
+			addresses = append(addresses, resource.InstanceAddress(instance))
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// DecodeTerraformState decodes a Terraform state file. Both the resources list of state version 4 and the modules
+// list of earlier versions are supported.
+func DecodeTerraformState(data []byte) (*TerraformState, error) {
+	var raw struct {
+		TerraformState
+		Modules []legacyStateModule `json:"modules,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decoding terraform state: %s", err.Error())
+	}
+
+	state := raw.TerraformState
+	for _, module := range raw.Modules {
+		state.Resources = append(state.Resources, module.resources()...)
+	}
+	return &state, nil
+}
+
+// legacyStateModule is a module of a state written by Terraform 0.11 and earlier.
+type legacyStateModule struct {
+	Path      []string                       `json:"path"`
+	Resources map[string]legacyStateResource `json:"resources"`
+}
+
+type legacyStateResource struct {
+	Primary struct {
+		Attributes map[string]interface{} `json:"attributes"`
+	} `json:"primary"`
+}
+
+// resources converts the resources of a legacy module, keyed by 'type.name' or 'type.name.index' with an optional
+// 'data.' prefix, to the current format.
+func (module legacyStateModule) resources() []TerraformStateResource {
+	var path []string
+	for _, name := range module.Path {
+		if name != "root" {
+			path = append(path, "module."+name)
+		}
+	}
+
+	byAddress := map[string]*TerraformStateResource{}
+	var addresses []string
+	for key, legacy := range module.Resources {
+		mode := TerraformStateResource_Mode_Managed
+		if strings.HasPrefix(key, "data.") {
+			mode = TerraformStateResource_Mode_Data
+			key = strings.TrimPrefix(key, "data.")
+		}
+		parts := strings.Split(key, ".")
+		if len(parts) < 2 {
+			continue
+		}
+		resource := TerraformStateResource{
+			Module: strings.Join(path, "."),
+			Mode:   mode,
+			Type:   parts[0],
+			Name:   parts[1],
+		}
+		instance := TerraformStateInstance{Attributes: legacy.Primary.Attributes}
+		if len(parts) > 2 {
+			if index, err := strconv.Atoi(parts[2]); err == nil {
+				instance.IndexKey = float64(index)
+			}
+		}
+
+		address := resource.Address()
+		if byAddress[address] == nil {
+			byAddress[address] = &resource
+			addresses = append(addresses, address)
+		}
+		byAddress[address].Instances = append(byAddress[address].Instances, instance)
+	}
+
+	sort.Strings(addresses)
+	resources := make([]TerraformStateResource, 0, len(addresses))
+	for _, address := range addresses {
+		resource := byAddress[address]
+		sort.SliceStable(resource.Instances, func(i, j int) bool {
+			a, _ := resource.Instances[i].IndexKey.(float64)
+			b, _ := resource.Instances[j].IndexKey.(float64)
+			return a < b
+		})
+		resources = append(resources, *resource)
+	}
+	return resources
+}
+
+// GetWorkspaceTerraformState : Get the decoded Terraform state of a workspace template
+// Get the Terraform state file of a workspace template with GetWorkspaceTemplateState and decode its resources with
+// DecodeTerraformState. The state file is decoded from the response body, since TemplateStateStore drops the resources
+// of the format used since Terraform 0.12.
+func (schematics *SchematicsV1) GetWorkspaceTerraformState(getWorkspaceTemplateStateOptions *GetWorkspaceTemplateStateOptions) (result *TerraformState, response *core.DetailedResponse, err error) {
+	client := core.DefaultHTTPClient()
+	if schematics.Service.Client != nil {
+		owned := *schematics.Service.Client
+		client = &owned
+	}
+	transport := &responseCapturingTransport{next: client.Transport}
+	if transport.next == nil {
+		transport.next = http.DefaultTransport
+	}
+	client.Transport = transport
+	service := *schematics.Service
+	service.Client = client
+
+	_, response, err = (&SchematicsV1{Service: &service}).GetWorkspaceTemplateState(getWorkspaceTemplateStateOptions)
+	if err != nil {
+		return
+	}
+	result, err = DecodeTerraformState(transport.body)
+	if err != nil {
+		return
+	}
+	response.Result = result
+
+	return
+}
+
+// responseCapturingTransport keeps the body of the last response it received.
+type responseCapturingTransport struct {
+	next http.RoundTripper
+	body []byte
+}
+
+func (transport *responseCapturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := transport.next.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	transport.body, err = readLoggedBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// getWorkspaceTerraformState returns the decoded state of the template tID of a workspace, or of its first template
+// when tID is nil.
+func (schematics *SchematicsV1) getWorkspaceTerraformState(wID *string, tID *string, headers map[string]string) (result *TerraformState, response *core.DetailedResponse, err error) {
+	if tID == nil {
+		var workspace *WorkspaceResponse
+		workspace, response, err = schematics.GetWorkspace(&GetWorkspaceOptions{
+			WID:     wID,
+			Headers: headers,
+		})
+		if err != nil {
+			return
+		}
+		if len(workspace.TemplateData) == 0 || workspace.TemplateData[0].ID == nil {
+			err = fmt.Errorf("workspace %s has no template", *wID)
+			return
+		}
+		tID = workspace.TemplateData[0].ID
+	}
+
+	return schematics.GetWorkspaceTerraformState(&GetWorkspaceTemplateStateOptions{
+		WID:     wID,
+		TID:     tID,
+		Headers: headers,
+	})
+}
@@ -0,0 +1,119 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Terraform state`, func() {
+	var testServer *httptest.Server
+	stateJSON := `{"version": 4, "terraform_version": "0.13.5", "serial": 7, "lineage": "abc", "resources": [
+		{"mode": "managed", "type": "ibm_is_vpc", "name": "vpc", "instances": [{"attributes": {"id": "r006-vpc"}}]},
+		{"module": "module.network", "mode": "managed", "type": "ibm_is_subnet", "name": "subnet", "instances": [{"index_key": 0}, {"index_key": 1}]},
+		{"mode": "managed", "type": "ibm_is_instance", "name": "vsi", "instances": [{"index_key": "web"}]},
+		{"mode": "data", "type": "ibm_is_image", "name": "image", "instances": [{}]}
+	]}`
+	legacyStateJSON := `{"version": 3, "terraform_version": "0.11.14", "modules": [
+		{"path": ["root"], "resources": {"ibm_is_vpc.vpc": {"primary": {"attributes": {"id": "r006-vpc"}}}, "data.ibm_is_image.image": {}}},
+		{"path": ["root", "network"], "resources": {"ibm_is_subnet.subnet.1": {}, "ibm_is_subnet.subnet.0": {}}}
+	]}`
+
+	Describe(`DecodeTerraformState(data []byte)`, func() {
+		It(`Decode a version 4 state`, func() {
+			state, err := schematicsv1.DecodeTerraformState([]byte(stateJSON))
+			Expect(err).To(BeNil())
+			Expect(*state.TerraformVersion).To(Equal("0.13.5"))
+			Expect(state.Resources).To(HaveLen(4))
+			Expect(state.Resources[0].Instances[0].Attributes["id"]).To(Equal("r006-vpc"))
+			Expect(state.Addresses()).To(Equal([]string{
+				"data.ibm_is_image.image",
+				"ibm_is_instance.vsi[\"web\"]",
+				"ibm_is_vpc.vpc",
+				"module.network.ibm_is_subnet.subnet[0]",
+				"module.network.ibm_is_subnet.subnet[1]",
+			}))
+		})
+		It(`Decode a legacy state`, func() {
+			state, err := schematicsv1.DecodeTerraformState([]byte(legacyStateJSON))
+			Expect(err).To(BeNil())
+			Expect(state.Addresses()).To(Equal([]string{
+				"data.ibm_is_image.image",
+				"ibm_is_vpc.vpc",
+				"module.network.ibm_is_subnet.subnet[0]",
+				"module.network.ibm_is_subnet.subnet[1]",
+			}))
+			Expect(state.Resources[1].Instances[0].Attributes["id"]).To(Equal("r006-vpc"))
+		})
+		It(`Reject a state that is not JSON`, func() {
+			_, err := schematicsv1.DecodeTerraformState([]byte("not json"))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+	Describe(`GetWorkspaceTerraformState(getWorkspaceTemplateStateOptions *GetWorkspaceTemplateStateOptions)`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.URL.EscapedPath()).To(Equal("/v1/workspaces/testString/runtime_data/testString/state_store"))
+					Expect(req.Method).To(Equal("GET"))
+					res.Header().Set("Content-type", "application/json")
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", stateJSON)
+				}))
+			})
+			It(`Invoke GetWorkspaceTerraformState successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.GetWorkspaceTerraformState(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				result, response, operationErr = schematicsService.GetWorkspaceTerraformState(schematicsService.NewGetWorkspaceTemplateStateOptions("testString", "testString"))
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result.Resources).To(HaveLen(4))
+				Expect(response.Result).To(Equal(result))
+
+				// The response body is captured without replacing the HTTP client of the service.
+				client := &http.Client{}
+				schematicsService.Service.SetHTTPClient(client)
+				result, _, operationErr = schematicsService.GetWorkspaceTerraformState(schematicsService.NewGetWorkspaceTemplateStateOptions("testString", "testString"))
+				Expect(operationErr).To(BeNil())
+				Expect(result.Resources).To(HaveLen(4))
+				Expect(schematicsService.Service.Client).To(BeIdenticalTo(client))
+				Expect(client.Transport).To(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,246 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+	"time"
+)
+
+// Constants associated with the WorkspaceActivity.Name property.
+// WorkspaceActivityAction activity action type.
+const (
+	WorkspaceActivity_Name_Apply    = "APPLY"
+	WorkspaceActivity_Name_Destroy  = "DESTROY"
+	WorkspaceActivity_Name_Plan     = "PLAN"
+	WorkspaceActivity_Name_Refresh  = "REFRESH"
+	WorkspaceActivity_Name_Commands = "COMMANDS"
+)
+
+// Constants associated with the WorkspaceActivity.Status property.
+// WorkspaceActivityStatus activity status type.
+const (
+	WorkspaceActivity_Status_Completed  = "COMPLETED"
+	WorkspaceActivity_Status_Failed     = "FAILED"
+	WorkspaceActivity_Status_InProgress = "INPROGRESS"
+	WorkspaceActivity_Status_Pending    = "PENDING"
+)
+
+// ListAllWorkspaceActivitiesOptions : The ListAllWorkspaceActivities options.
+type ListAllWorkspaceActivitiesOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewListAllWorkspaceActivitiesOptions : Instantiate ListAllWorkspaceActivitiesOptions
+func (*SchematicsV1) NewListAllWorkspaceActivitiesOptions(wID string) *ListAllWorkspaceActivitiesOptions {
+	return &ListAllWorkspaceActivitiesOptions{
+		WID: core.StringPtr(wID),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *ListAllWorkspaceActivitiesOptions) SetWID(wID string) *ListAllWorkspaceActivitiesOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ListAllWorkspaceActivitiesOptions) SetHeaders(param map[string]string) *ListAllWorkspaceActivitiesOptions {
+	options.Headers = param
+	return options
+}
+
+// ListAllWorkspaceActivities : List all workspace activities
+// Read every page of ListWorkspaceActivities and return the activities of the workspace in a single result.
+func (schematics *SchematicsV1) ListAllWorkspaceActivities(listAllWorkspaceActivitiesOptions *ListAllWorkspaceActivitiesOptions) (result *WorkspaceActivities, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listAllWorkspaceActivitiesOptions, "listAllWorkspaceActivitiesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(listAllWorkspaceActivitiesOptions, "listAllWorkspaceActivitiesOptions")
+	if err != nil {
+		return
+	}
+
+	var offset int64
+	for {
+		var page *WorkspaceActivities
+		page, response, err = schematics.ListWorkspaceActivities(&ListWorkspaceActivitiesOptions{
+			WID:     listAllWorkspaceActivitiesOptions.WID,
+			Offset:  core.Int64Ptr(offset),
+			Limit:   core.Int64Ptr(listPageSize),
+			Headers: listAllWorkspaceActivitiesOptions.Headers,
+		})
+		if err != nil {
+			result = nil
+			return
+		}
+		if result == nil {
+			result = &WorkspaceActivities{
+				WorkspaceID:   page.WorkspaceID,
+				WorkspaceName: page.WorkspaceName,
+			}
+		}
+		result.Actions = append(result.Actions, page.Actions...)
+		offset += int64(len(page.Actions))
+		if len(page.Actions) < listPageSize {
+			return
+		}
+	}
+}
+
+// WorkspaceActivityFailedError is returned by WaitForWorkspaceActivity when an activity finished without completing.
+type WorkspaceActivityFailedError struct {
+	// The workspace ID.
+	WID string
+
+	// The activity ID.
+	ActivityID string
+
+	// The final status of the activity.
+	Status string
+
+	// The status messages of the activity.
+	Messages []string
+}
+
+// Error implements the error interface.
+func (e *WorkspaceActivityFailedError) Error() string {
+	msg := fmt.Sprintf("activity %s of workspace %s finished with status %s", e.ActivityID, e.WID, e.Status)
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	return msg
+}
+
+// WaitForWorkspaceActivityOptions : The WaitForWorkspaceActivity options.
+type WaitForWorkspaceActivityOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The ID of the activity.
+	ActivityID *string `json:"activity_id" validate:"required,ne="`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewWaitForWorkspaceActivityOptions : Instantiate WaitForWorkspaceActivityOptions
+func (*SchematicsV1) NewWaitForWorkspaceActivityOptions(wID string, activityID string) *WaitForWorkspaceActivityOptions {
+	return &WaitForWorkspaceActivityOptions{
+		WID:        core.StringPtr(wID),
+		ActivityID: core.StringPtr(activityID),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *WaitForWorkspaceActivityOptions) SetWID(wID string) *WaitForWorkspaceActivityOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetActivityID : Allow user to set ActivityID
+func (options *WaitForWorkspaceActivityOptions) SetActivityID(activityID string) *WaitForWorkspaceActivityOptions {
+	options.ActivityID = core.StringPtr(activityID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *WaitForWorkspaceActivityOptions) SetPollInterval(pollInterval time.Duration) *WaitForWorkspaceActivityOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *WaitForWorkspaceActivityOptions) SetTimeout(timeout time.Duration) *WaitForWorkspaceActivityOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *WaitForWorkspaceActivityOptions) SetHeaders(param map[string]string) *WaitForWorkspaceActivityOptions {
+	options.Headers = param
+	return options
+}
+
+// WaitForWorkspaceActivity : Wait for a workspace activity to finish
+// Poll GetWorkspaceActivity until the activity is neither pending nor in progress. The final activity is returned; a
+// *WorkspaceActivityFailedError is returned with it when the activity did not complete.
+func (schematics *SchematicsV1) WaitForWorkspaceActivity(waitForWorkspaceActivityOptions *WaitForWorkspaceActivityOptions) (result *WorkspaceActivity, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(waitForWorkspaceActivityOptions, "waitForWorkspaceActivityOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(waitForWorkspaceActivityOptions, "waitForWorkspaceActivityOptions")
+	if err != nil {
+		return
+	}
+
+	err = pollUntil(waitForWorkspaceActivityOptions.PollInterval, waitForWorkspaceActivityOptions.Timeout, func() (bool, error) {
+		var pollErr error
+		result, response, pollErr = schematics.GetWorkspaceActivity(&GetWorkspaceActivityOptions{
+			WID:        waitForWorkspaceActivityOptions.WID,
+			ActivityID: waitForWorkspaceActivityOptions.ActivityID,
+			Headers:    waitForWorkspaceActivityOptions.Headers,
+		})
+		if pollErr != nil {
+			return false, pollErr
+		}
+		return isActivityFinished(result.Status), nil
+	})
+	if err != nil {
+		return
+	}
+
+	if !isActivityStatus(result.Status, WorkspaceActivity_Status_Completed) {
+		status := ""
+		if result.Status != nil {
+			status = *result.Status
+		}
+		err = &WorkspaceActivityFailedError{
+			WID:        *waitForWorkspaceActivityOptions.WID,
+			ActivityID: *waitForWorkspaceActivityOptions.ActivityID,
+			Status:     status,
+			Messages:   result.Message,
+		}
+	}
+	return
+}
+
+// isActivityFinished reports whether an activity with the given status is neither pending nor in progress.
+func isActivityFinished(status *string) bool {
+	return status != nil && *status != "" &&
+		!isActivityStatus(status, WorkspaceActivity_Status_Pending) &&
+		!isActivityStatus(status, WorkspaceActivity_Status_InProgress)
+}
+
+// isActivityStatus reports whether the status of an activity equals status, ignoring case.
+func isActivityStatus(activityStatus *string, status string) bool {
+	return activityStatus != nil && strings.EqualFold(*activityStatus, status)
+}
@@ -0,0 +1,365 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/go-openapi/strfmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AnalyzeWorkspaceActivitiesOptions : The AnalyzeWorkspaceActivities options.
+type AnalyzeWorkspaceActivitiesOptions struct {
+	// The IDs of the workspaces whose activity history is analyzed.
+	WIDs []string `json:"w_ids" validate:"required,min=1"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewAnalyzeWorkspaceActivitiesOptions : Instantiate AnalyzeWorkspaceActivitiesOptions
+func (*SchematicsV1) NewAnalyzeWorkspaceActivitiesOptions(wIDs []string) *AnalyzeWorkspaceActivitiesOptions {
+	return &AnalyzeWorkspaceActivitiesOptions{
+		WIDs: wIDs,
+	}
+}
+
+// SetWIDs : Allow user to set WIDs
+func (options *AnalyzeWorkspaceActivitiesOptions) SetWIDs(wIDs []string) *AnalyzeWorkspaceActivitiesOptions {
+	options.WIDs = wIDs
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *AnalyzeWorkspaceActivitiesOptions) SetHeaders(param map[string]string) *AnalyzeWorkspaceActivitiesOptions {
+	options.Headers = param
+	return options
+}
+
+// ActivityAnalytics : Aggregated activity history of one or many workspaces.
+type ActivityAnalytics struct {
+	// The time the analytics were computed.
+	GeneratedAt *strfmt.DateTime `json:"generated_at,omitempty"`
+
+	// The number of activities analyzed.
+	TotalActivities int64 `json:"total_activities"`
+
+	// Statistics per activity type, sorted by activity name.
+	ActivityTypes []ActivityTypeStats `json:"activity_types"`
+
+	// The users that performed activities, most frequent first.
+	Performers []ActivityCount `json:"performers"`
+
+	// The errors reported in the log summaries of the templates, most frequent first.
+	Errors []ActivityCount `json:"errors"`
+
+	// The last successful apply of each workspace.
+	Workspaces []WorkspaceApplyStats `json:"workspaces"`
+}
+
+// ActivityTypeStats : Statistics of one activity type.
+type ActivityTypeStats struct {
+	// The activity name, for example APPLY.
+	Name string `json:"name"`
+
+	// The number of activities of this type.
+	Total int64 `json:"total"`
+
+	// The number of completed activities.
+	Succeeded int64 `json:"succeeded"`
+
+	// The number of failed activities.
+	Failed int64 `json:"failed"`
+
+	// Succeeded divided by the number of finished activities.
+	SuccessRate float64 `json:"success_rate"`
+
+	// Failed divided by the number of finished activities.
+	FailureRate float64 `json:"failure_rate"`
+
+	// The median duration in seconds of the activities with a known duration.
+	P50Duration *float64 `json:"p50_duration,omitempty"`
+
+	// The 95th percentile duration in seconds of the activities with a known duration.
+	P95Duration *float64 `json:"p95_duration,omitempty"`
+}
+
+// ActivityCount : A value and the number of times it occurs.
+type ActivityCount struct {
+	// The counted value.
+	Key string `json:"key"`
+
+	// The number of occurrences.
+	Count int64 `json:"count"`
+}
+
+// WorkspaceApplyStats : The last successful apply of a workspace.
+type WorkspaceApplyStats struct {
+	// The workspace ID.
+	WorkspaceID string `json:"workspace_id"`
+
+	// The workspace name.
+	WorkspaceName string `json:"workspace_name,omitempty"`
+
+	// The time of the last successful apply, if any.
+	LastSuccessfulApplyAt *strfmt.DateTime `json:"last_successful_apply_at,omitempty"`
+
+	// The number of seconds between the last successful apply and GeneratedAt.
+	SecondsSinceLastSuccessfulApply *float64 `json:"seconds_since_last_successful_apply,omitempty"`
+}
+
+// AnalyzeWorkspaceActivities : Aggregate the activity history of workspaces
+// Read all the activities of the workspaces and aggregate them with NewActivityAnalytics.
+func (schematics *SchematicsV1) AnalyzeWorkspaceActivities(analyzeWorkspaceActivitiesOptions *AnalyzeWorkspaceActivitiesOptions) (result *ActivityAnalytics, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(analyzeWorkspaceActivitiesOptions, "analyzeWorkspaceActivitiesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(analyzeWorkspaceActivitiesOptions, "analyzeWorkspaceActivitiesOptions")
+	if err != nil {
+		return
+	}
+
+	var histories []WorkspaceActivities
+	for _, wID := range analyzeWorkspaceActivitiesOptions.WIDs {
+		var activities *WorkspaceActivities
+		activities, response, err = schematics.ListAllWorkspaceActivities(&ListAllWorkspaceActivitiesOptions{
+			WID:     core.StringPtr(wID),
+			Headers: analyzeWorkspaceActivitiesOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		if activities.WorkspaceID == nil {
+			activities.WorkspaceID = core.StringPtr(wID)
+		}
+		histories = append(histories, *activities)
+	}
+
+	result = NewActivityAnalytics(time.Now(), histories...)
+	return
+}
+
+// NewActivityAnalytics aggregates the activity histories of workspaces. The time since the last successful apply is
+// computed relative to now.
+func NewActivityAnalytics(now time.Time, histories ...WorkspaceActivities) *ActivityAnalytics {
+	generatedAt := strfmt.DateTime(now)
+	analytics := &ActivityAnalytics{
+		GeneratedAt:   &generatedAt,
+		ActivityTypes: []ActivityTypeStats{},
+		Performers:    []ActivityCount{},
+		Errors:        []ActivityCount{},
+		Workspaces:    []WorkspaceApplyStats{},
+	}
+
+	types := map[string]*ActivityTypeStats{}
+	durations := map[string][]float64{}
+	performers := map[string]int64{}
+	errors := map[string]int64{}
+
+	for _, history := range histories {
+		workspace := WorkspaceApplyStats{}
+		if history.WorkspaceID != nil {
+			workspace.WorkspaceID = *history.WorkspaceID
+		}
+		if history.WorkspaceName != nil {
+			workspace.WorkspaceName = *history.WorkspaceName
+		}
+
+		for _, activity := range history.Actions {
+			analytics.TotalActivities++
+
+			name := ""
+			if activity.Name != nil {
+				name = strings.ToUpper(*activity.Name)
+			}
+			stats, ok := types[name]
+			if !ok {
+				stats = &ActivityTypeStats{Name: name}
+				types[name] = stats
+			}
+			stats.Total++
+			succeeded := isActivityStatus(activity.Status, WorkspaceActivity_Status_Completed)
+			if succeeded {
+				stats.Succeeded++
+			} else if isActivityStatus(activity.Status, WorkspaceActivity_Status_Failed) {
+				stats.Failed++
+			}
+
+			if duration, ok := activityDuration(activity); ok {
+				durations[name] = append(durations[name], duration)
+			}
+			if activity.PerformedBy != nil && *activity.PerformedBy != "" {
+				performers[*activity.PerformedBy]++
+			}
+			for _, template := range activity.Templates {
+				if template.LogSummary != nil && template.LogSummary.Error != nil && *template.LogSummary.Error != "" {
+					errors[*template.LogSummary.Error]++
+				}
+			}
+
+			if succeeded && name == WorkspaceActivity_Name_Apply && activity.PerformedAt != nil {
+				if workspace.LastSuccessfulApplyAt == nil || time.Time(*activity.PerformedAt).After(time.Time(*workspace.LastSuccessfulApplyAt)) {
+					performedAt := *activity.PerformedAt
+					workspace.LastSuccessfulApplyAt = &performedAt
+				}
+			}
+		}
+
+		if workspace.LastSuccessfulApplyAt != nil {
+			seconds := now.Sub(time.Time(*workspace.LastSuccessfulApplyAt)).Seconds()
+			workspace.SecondsSinceLastSuccessfulApply = &seconds
+		}
+		analytics.Workspaces = append(analytics.Workspaces, workspace)
+	}
+
+	for name, stats := range types {
+		if finished := stats.Succeeded + stats.Failed; finished > 0 {
+			stats.SuccessRate = float64(stats.Succeeded) / float64(finished)
+			stats.FailureRate = float64(stats.Failed) / float64(finished)
+		}
+		stats.P50Duration = percentile(durations[name], 50)
+		stats.P95Duration = percentile(durations[name], 95)
+		analytics.ActivityTypes = append(analytics.ActivityTypes, *stats)
+	}
+	sort.Slice(analytics.ActivityTypes, func(i, j int) bool {
+		return analytics.ActivityTypes[i].Name < analytics.ActivityTypes[j].Name
+	})
+	analytics.Performers = sortedCounts(performers)
+	analytics.Errors = sortedCounts(errors)
+
+	return analytics
+}
+
+// WriteJSON writes the analytics to w as indented JSON.
+func (analytics *ActivityAnalytics) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analytics)
+}
+
+// WriteCSV writes the analytics to w as a single CSV table. The section column tells which part of the analytics a
+// row belongs to: activity, performer, error or workspace. Columns that do not apply to a section are left empty.
+func (analytics *ActivityAnalytics) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{
+		"section", "key", "count", "succeeded", "failed", "success_rate", "failure_rate",
+		"p50_duration", "p95_duration", "last_successful_apply_at", "seconds_since_last_successful_apply",
+	}}
+	for _, stats := range analytics.ActivityTypes {
+		rows = append(rows, []string{
+			"activity", stats.Name, formatInt(stats.Total), formatInt(stats.Succeeded), formatInt(stats.Failed),
+			formatFloat(&stats.SuccessRate), formatFloat(&stats.FailureRate),
+			formatFloat(stats.P50Duration), formatFloat(stats.P95Duration), "", "",
+		})
+	}
+	for _, performer := range analytics.Performers {
+		rows = append(rows, []string{"performer", performer.Key, formatInt(performer.Count), "", "", "", "", "", "", "", ""})
+	}
+	for _, e := range analytics.Errors {
+		rows = append(rows, []string{"error", e.Key, formatInt(e.Count), "", "", "", "", "", "", "", ""})
+	}
+	for _, workspace := range analytics.Workspaces {
+		lastApply := ""
+		if workspace.LastSuccessfulApplyAt != nil {
+			lastApply = workspace.LastSuccessfulApplyAt.String()
+		}
+		rows = append(rows, []string{
+			"workspace", workspace.WorkspaceID, "", "", "", "", "", "", "",
+			lastApply, formatFloat(workspace.SecondsSinceLastSuccessfulApply),
+		})
+	}
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// activityDuration returns the duration of an activity in seconds: the sum of the time taken by its templates, or the
+// time between the first template start and the last template end when the log summaries do not have it.
+func activityDuration(activity WorkspaceActivity) (float64, bool) {
+	var total float64
+	var known bool
+	var start, end time.Time
+	for _, template := range activity.Templates {
+		if template.LogSummary != nil && template.LogSummary.TimeTaken != nil {
+			total += *template.LogSummary.TimeTaken
+			known = true
+		}
+		if template.StartTime != nil && (start.IsZero() || time.Time(*template.StartTime).Before(start)) {
+			start = time.Time(*template.StartTime)
+		}
+		if template.EndTime != nil && time.Time(*template.EndTime).After(end) {
+			end = time.Time(*template.EndTime)
+		}
+	}
+	if known {
+		return total, true
+	}
+	if !start.IsZero() && end.After(start) {
+		return end.Sub(start).Seconds(), true
+	}
+	return 0, false
+}
+
+// percentile returns the p-th percentile of values using the nearest-rank method, or nil if values is empty.
+func percentile(values []float64, p float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	value := sorted[rank-1]
+	return &value
+}
+
+func sortedCounts(counts map[string]int64) []ActivityCount {
+	result := []ActivityCount{}
+	for key, count := range counts {
+		result = append(result, ActivityCount{Key: key, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func formatInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
@@ -0,0 +1,147 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Workspace activity analytics`, func() {
+	var testServer *httptest.Server
+	activitiesJSON := `{"workspace_id": "ws-1", "workspace_name": "app", "actions": [
+		{"action_id": "1", "name": "APPLY", "status": "COMPLETED", "performed_by": "alice", "performed_at": "2021-03-01T10:00:00.000Z", "templates": [{"log_summary": {"time_taken": 100}}]},
+		{"action_id": "2", "name": "APPLY", "status": "FAILED", "performed_by": "bob", "performed_at": "2021-03-02T10:00:00.000Z", "templates": [{"log_summary": {"time_taken": 20, "error": "quota exceeded"}}]},
+		{"action_id": "3", "name": "APPLY", "status": "COMPLETED", "performed_by": "alice", "performed_at": "2021-03-03T10:00:00.000Z", "templates": [{"log_summary": {"time_taken": 300}}]},
+		{"action_id": "4", "name": "PLAN", "status": "COMPLETED", "performed_by": "alice", "performed_at": "2021-03-04T10:00:00.000Z", "templates": [{"start_time": "2021-03-04T10:00:00.000Z", "end_time": "2021-03-04T10:00:30.000Z"}]},
+		{"action_id": "5", "name": "PLAN", "status": "FAILED", "performed_by": "bob", "performed_at": "2021-03-05T10:00:00.000Z", "templates": [{"log_summary": {"error": "quota exceeded"}}]}
+	]}`
+	now := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	Describe(`NewActivityAnalytics(now time.Time, histories ...WorkspaceActivities)`, func() {
+		It(`Aggregate an activity history`, func() {
+			var history schematicsv1.WorkspaceActivities
+			Expect(json.Unmarshal([]byte(activitiesJSON), &history)).To(Succeed())
+
+			analytics := schematicsv1.NewActivityAnalytics(now, history)
+			Expect(analytics.TotalActivities).To(Equal(int64(5)))
+			Expect(analytics.ActivityTypes).To(HaveLen(2))
+
+			apply := analytics.ActivityTypes[0]
+			Expect(apply.Name).To(Equal("APPLY"))
+			Expect(apply.Total).To(Equal(int64(3)))
+			Expect(apply.Succeeded).To(Equal(int64(2)))
+			Expect(apply.Failed).To(Equal(int64(1)))
+			Expect(apply.SuccessRate).To(BeNumerically("~", 2.0/3.0))
+			Expect(*apply.P50Duration).To(Equal(100.0))
+			Expect(*apply.P95Duration).To(Equal(300.0))
+
+			plan := analytics.ActivityTypes[1]
+			Expect(plan.FailureRate).To(Equal(0.5))
+			Expect(*plan.P50Duration).To(Equal(30.0))
+
+			Expect(analytics.Performers[0]).To(Equal(schematicsv1.ActivityCount{Key: "alice", Count: 3}))
+			Expect(analytics.Errors).To(Equal([]schematicsv1.ActivityCount{{Key: "quota exceeded", Count: 2}}))
+
+			Expect(analytics.Workspaces).To(HaveLen(1))
+			Expect(analytics.Workspaces[0].WorkspaceName).To(Equal("app"))
+			Expect(time.Time(*analytics.Workspaces[0].LastSuccessfulApplyAt).Equal(time.Date(2021, 3, 3, 10, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(*analytics.Workspaces[0].SecondsSinceLastSuccessfulApply).To(Equal(86400.0))
+		})
+		It(`Export the analytics as JSON and CSV`, func() {
+			var history schematicsv1.WorkspaceActivities
+			Expect(json.Unmarshal([]byte(activitiesJSON), &history)).To(Succeed())
+			analytics := schematicsv1.NewActivityAnalytics(now, history)
+
+			var jsonBuf bytes.Buffer
+			Expect(analytics.WriteJSON(&jsonBuf)).To(Succeed())
+			var decoded schematicsv1.ActivityAnalytics
+			Expect(json.Unmarshal(jsonBuf.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded.ActivityTypes).To(HaveLen(2))
+
+			var csvBuf bytes.Buffer
+			Expect(analytics.WriteCSV(&csvBuf)).To(Succeed())
+			rows, err := csv.NewReader(&csvBuf).ReadAll()
+			Expect(err).To(BeNil())
+			Expect(rows).To(HaveLen(1 + 2 + 2 + 1 + 1))
+			Expect(rows[0][0]).To(Equal("section"))
+			Expect(rows[1][:5]).To(Equal([]string{"activity", "APPLY", "3", "2", "1"}))
+			Expect(rows[6][0]).To(Equal("workspace"))
+			Expect(rows[6][10]).To(Equal("86400"))
+		})
+		It(`Aggregate an empty history`, func() {
+			analytics := schematicsv1.NewActivityAnalytics(now)
+			Expect(analytics.TotalActivities).To(BeZero())
+			Expect(analytics.ActivityTypes).To(BeEmpty())
+		})
+	})
+	Describe(`AnalyzeWorkspaceActivities(analyzeWorkspaceActivitiesOptions *AnalyzeWorkspaceActivitiesOptions)`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.Method).To(Equal("GET"))
+					res.Header().Set("Content-type", "application/json")
+					switch req.URL.EscapedPath() {
+					case "/v1/workspaces/ws-1/actions":
+						Expect(req.URL.Query()["limit"]).To(Equal([]string{"100"}))
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", activitiesJSON)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke AnalyzeWorkspaceActivities successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.AnalyzeWorkspaceActivities(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				result, response, operationErr = schematicsService.AnalyzeWorkspaceActivities(schematicsService.NewAnalyzeWorkspaceActivitiesOptions([]string{"ws-1"}))
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result.TotalActivities).To(Equal(int64(5)))
+
+				result, response, operationErr = schematicsService.AnalyzeWorkspaceActivities(schematicsService.NewAnalyzeWorkspaceActivitiesOptions([]string{"ws-1", "missing"}))
+				Expect(operationErr).ToNot(BeNil())
+				Expect(response.StatusCode).To(Equal(404))
+				Expect(result).To(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
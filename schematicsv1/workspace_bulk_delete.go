@@ -0,0 +1,324 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"time"
+)
+
+// DefaultBulkDeleteBatchSize is the number of workspaces submitted per deletion job by BulkDeleteWorkspaces.
+const DefaultBulkDeleteBatchSize = 50
+
+// BulkDeleteWorkspacesOptions : The BulkDeleteWorkspaces options.
+type BulkDeleteWorkspacesOptions struct {
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The IDs of the workspaces to delete.
+	WorkspaceIDs []string `json:"workspace_ids,omitempty"`
+
+	// Selects additional workspaces to delete. An empty selector is rejected, because it matches every workspace.
+	Selector *Selector `json:"selector,omitempty"`
+
+	// True to destroy the resources managed by the workspaces before deleting them. Requires ConfirmDestroy.
+	DestroyResources *bool `json:"destroy_resources,omitempty"`
+
+	// Must be true when DestroyResources is true.
+	ConfirmDestroy *bool `json:"confirm_destroy,omitempty"`
+
+	// The name of the deletion jobs.
+	JobName *string `json:"job_name,omitempty"`
+
+	// The number of workspaces submitted per deletion job. Defaults to DefaultBulkDeleteBatchSize.
+	BatchSize *int64 `json:"batch_size,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting for each deletion job. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Called with the status of a deletion job every time it is read.
+	OnProgress func(jobID string, status *JobStatusType) `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewBulkDeleteWorkspacesOptions : Instantiate BulkDeleteWorkspacesOptions
+func (*SchematicsV1) NewBulkDeleteWorkspacesOptions(refreshToken string) *BulkDeleteWorkspacesOptions {
+	return &BulkDeleteWorkspacesOptions{
+		RefreshToken: core.StringPtr(refreshToken),
+	}
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *BulkDeleteWorkspacesOptions) SetRefreshToken(refreshToken string) *BulkDeleteWorkspacesOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetWorkspaceIDs : Allow user to set WorkspaceIDs
+func (options *BulkDeleteWorkspacesOptions) SetWorkspaceIDs(workspaceIDs []string) *BulkDeleteWorkspacesOptions {
+	options.WorkspaceIDs = workspaceIDs
+	return options
+}
+
+// SetSelector : Allow user to set Selector
+func (options *BulkDeleteWorkspacesOptions) SetSelector(selector *Selector) *BulkDeleteWorkspacesOptions {
+	options.Selector = selector
+	return options
+}
+
+// SetDestroyResources : Allow user to set DestroyResources
+func (options *BulkDeleteWorkspacesOptions) SetDestroyResources(destroyResources bool) *BulkDeleteWorkspacesOptions {
+	options.DestroyResources = core.BoolPtr(destroyResources)
+	return options
+}
+
+// SetConfirmDestroy : Allow user to set ConfirmDestroy
+func (options *BulkDeleteWorkspacesOptions) SetConfirmDestroy(confirmDestroy bool) *BulkDeleteWorkspacesOptions {
+	options.ConfirmDestroy = core.BoolPtr(confirmDestroy)
+	return options
+}
+
+// SetJobName : Allow user to set JobName
+func (options *BulkDeleteWorkspacesOptions) SetJobName(jobName string) *BulkDeleteWorkspacesOptions {
+	options.JobName = core.StringPtr(jobName)
+	return options
+}
+
+// SetBatchSize : Allow user to set BatchSize
+func (options *BulkDeleteWorkspacesOptions) SetBatchSize(batchSize int64) *BulkDeleteWorkspacesOptions {
+	options.BatchSize = core.Int64Ptr(batchSize)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *BulkDeleteWorkspacesOptions) SetPollInterval(pollInterval time.Duration) *BulkDeleteWorkspacesOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *BulkDeleteWorkspacesOptions) SetTimeout(timeout time.Duration) *BulkDeleteWorkspacesOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetOnProgress : Allow user to set OnProgress
+func (options *BulkDeleteWorkspacesOptions) SetOnProgress(onProgress func(jobID string, status *JobStatusType)) *BulkDeleteWorkspacesOptions {
+	options.OnProgress = onProgress
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *BulkDeleteWorkspacesOptions) SetHeaders(param map[string]string) *BulkDeleteWorkspacesOptions {
+	options.Headers = param
+	return options
+}
+
+// WorkspaceDeletionOutcome : The outcome of the deletion of one workspace.
+type WorkspaceDeletionOutcome struct {
+	// The workspace ID.
+	WID *string `json:"w_id,omitempty"`
+
+	// The ID of the deletion job the workspace was submitted in.
+	JobID *string `json:"job_id,omitempty"`
+
+	// The deletion status of the workspace.
+	Status *string `json:"status,omitempty"`
+}
+
+// Constants associated with the WorkspaceDeletionOutcome.Status property.
+// The deletion status of the workspace.
+const (
+	WorkspaceDeletionOutcome_Status_Failed     = "failed"
+	WorkspaceDeletionOutcome_Status_InProgress = "in_progress"
+	WorkspaceDeletionOutcome_Status_Success    = "success"
+	WorkspaceDeletionOutcome_Status_Unknown    = "unknown"
+)
+
+// BulkDeleteWorkspacesResult : The result of BulkDeleteWorkspaces.
+type BulkDeleteWorkspacesResult struct {
+	// The submitted deletion jobs.
+	Jobs []WorkspaceBulkDeleteResponse `json:"jobs,omitempty"`
+
+	// The outcome of each workspace, in submission order.
+	Outcomes []WorkspaceDeletionOutcome `json:"outcomes,omitempty"`
+}
+
+// Failed returns the outcomes of the workspaces that were not deleted successfully.
+func (result *BulkDeleteWorkspacesResult) Failed() (failed []WorkspaceDeletionOutcome) {
+	for _, outcome := range result.Outcomes {
+		if outcome.Status == nil || *outcome.Status != WorkspaceDeletionOutcome_Status_Success {
+			failed = append(failed, outcome)
+		}
+	}
+	return
+}
+
+// BulkDeleteWorkspaces : Delete many workspaces
+// Delete the workspaces listed in the options and those matching the selector. The workspaces are submitted in batches
+// with CreateWorkspaceDeletionJob and each job is polled until every workspace of its batch is reported as deleted
+// or failed. Destroying the resources of the workspaces must be confirmed explicitly with ConfirmDestroy.
+func (schematics *SchematicsV1) BulkDeleteWorkspaces(bulkDeleteWorkspacesOptions *BulkDeleteWorkspacesOptions) (result *BulkDeleteWorkspacesResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(bulkDeleteWorkspacesOptions, "bulkDeleteWorkspacesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(bulkDeleteWorkspacesOptions, "bulkDeleteWorkspacesOptions")
+	if err != nil {
+		return
+	}
+	destroy := bulkDeleteWorkspacesOptions.DestroyResources != nil && *bulkDeleteWorkspacesOptions.DestroyResources
+	if destroy && (bulkDeleteWorkspacesOptions.ConfirmDestroy == nil || !*bulkDeleteWorkspacesOptions.ConfirmDestroy) {
+		err = fmt.Errorf("destroying the resources of the workspaces must be confirmed with ConfirmDestroy")
+		return
+	}
+	batchSize := int64(DefaultBulkDeleteBatchSize)
+	if bulkDeleteWorkspacesOptions.BatchSize != nil {
+		batchSize = *bulkDeleteWorkspacesOptions.BatchSize
+		if batchSize <= 0 {
+			err = fmt.Errorf("BatchSize must be greater than zero")
+			return
+		}
+	}
+
+	if bulkDeleteWorkspacesOptions.Selector != nil && bulkDeleteWorkspacesOptions.Selector.isEmpty() {
+		err = fmt.Errorf("an empty Selector matches every workspace and is not accepted")
+		return
+	}
+
+	workspaceIDs := bulkDeleteWorkspacesOptions.WorkspaceIDs
+	if bulkDeleteWorkspacesOptions.Selector != nil {
+		var selected []WorkspaceResponse
+		selected, response, err = schematics.SelectWorkspaces(&SelectWorkspacesOptions{
+			Selector: bulkDeleteWorkspacesOptions.Selector,
+			Headers:  bulkDeleteWorkspacesOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		for _, workspace := range selected {
+			if workspace.ID != nil {
+				workspaceIDs = append(workspaceIDs, *workspace.ID)
+			}
+		}
+	}
+	workspaceIDs = uniqueStrings(workspaceIDs)
+
+	result = &BulkDeleteWorkspacesResult{}
+	for start := 0; start < len(workspaceIDs); start += int(batchSize) {
+		end := start + int(batchSize)
+		if end > len(workspaceIDs) {
+			end = len(workspaceIDs)
+		}
+		batch := workspaceIDs[start:end]
+
+		createOptions := &CreateWorkspaceDeletionJobOptions{
+			RefreshToken:        bulkDeleteWorkspacesOptions.RefreshToken,
+			NewDeleteWorkspaces: core.BoolPtr(true),
+			NewDestroyResources: core.BoolPtr(destroy),
+			NewJob:              bulkDeleteWorkspacesOptions.JobName,
+			NewWorkspaces:       batch,
+			Headers:             bulkDeleteWorkspacesOptions.Headers,
+		}
+		if destroy {
+			createOptions.DestroyResources = core.StringPtr("true")
+		}
+		var job *WorkspaceBulkDeleteResponse
+		job, response, err = schematics.CreateWorkspaceDeletionJob(createOptions)
+		if err != nil {
+			return
+		}
+		result.Jobs = append(result.Jobs, *job)
+		if job.JobID == nil {
+			err = fmt.Errorf("workspace deletion job was submitted without a job ID")
+			return
+		}
+
+		var status *JobStatusType
+		err = pollUntil(bulkDeleteWorkspacesOptions.PollInterval, bulkDeleteWorkspacesOptions.Timeout, func() (bool, error) {
+			var jobResponse *WorkspaceJobResponse
+			jobResponse, response, err = schematics.GetWorkspaceDeletionJobStatus(&GetWorkspaceDeletionJobStatusOptions{
+				WjID:    job.JobID,
+				Headers: bulkDeleteWorkspacesOptions.Headers,
+			})
+			if err != nil {
+				return false, err
+			}
+			status = jobResponse.JobStatus
+			if bulkDeleteWorkspacesOptions.OnProgress != nil {
+				bulkDeleteWorkspacesOptions.OnProgress(*job.JobID, status)
+			}
+			return status != nil && workspaceDeletionFinished(batch, status), nil
+		})
+		result.Outcomes = append(result.Outcomes, workspaceDeletionOutcomes(batch, job.JobID, status)...)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// workspaceDeletionFinished reports whether every workspace of a deletion job was either deleted or failed.
+func workspaceDeletionFinished(workspaceIDs []string, status *JobStatusType) bool {
+	for _, workspaceID := range workspaceIDs {
+		if !containsString(status.Success, workspaceID) && !containsString(status.Failed, workspaceID) {
+			return false
+		}
+	}
+	return true
+}
+
+// workspaceDeletionOutcomes returns the outcome of each workspace of a deletion job.
+func workspaceDeletionOutcomes(workspaceIDs []string, jobID *string, status *JobStatusType) (outcomes []WorkspaceDeletionOutcome) {
+	for _, workspaceID := range workspaceIDs {
+		outcome := WorkspaceDeletionOutcome{
+			WID:    core.StringPtr(workspaceID),
+			JobID:  jobID,
+			Status: core.StringPtr(WorkspaceDeletionOutcome_Status_Unknown),
+		}
+		if status != nil {
+			switch {
+			case containsString(status.Success, workspaceID):
+				outcome.Status = core.StringPtr(WorkspaceDeletionOutcome_Status_Success)
+			case containsString(status.Failed, workspaceID):
+				outcome.Status = core.StringPtr(WorkspaceDeletionOutcome_Status_Failed)
+			case containsString(status.InProgress, workspaceID):
+				outcome.Status = core.StringPtr(WorkspaceDeletionOutcome_Status_InProgress)
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return
+}
+
+func uniqueStrings(list []string) (unique []string) {
+	seen := map[string]bool{}
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return
+}
@@ -0,0 +1,171 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Bulk workspace deletion`, func() {
+	var testServer *httptest.Server
+	Describe(`BulkDeleteWorkspaces(bulkDeleteWorkspacesOptions *BulkDeleteWorkspacesOptions)`, func() {
+		var submitted [][]string
+		var statusCalls int
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				submitted = nil
+				statusCalls = 0
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					res.Header().Set("Content-type", "application/json")
+					switch {
+					case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspaces":
+						Expect(req.URL.Query()["limit"]).To(Equal([]string{"100"}))
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"count": 3, "limit": 100, "offset": 0, "workspaces": [{"id": "ws-1", "name": "app-dev", "tags": ["env:dev"]}, {"id": "ws-2", "name": "app-prod", "tags": ["env:prod"]}, {"id": "ws-3", "name": "db-dev", "tags": ["env:dev"]}]}`)
+					case req.Method == "POST" && req.URL.EscapedPath() == "/v1/workspace_jobs":
+						var body map[string]interface{}
+						Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+						Expect(req.Header.Get("refresh_token")).To(Equal("testString"))
+						var workspaces []string
+						for _, w := range body["workspaces"].([]interface{}) {
+							workspaces = append(workspaces, w.(string))
+						}
+						submitted = append(submitted, workspaces)
+						res.WriteHeader(200)
+						fmt.Fprintf(res, `{"job": "Job", "job_id": "job-%d"}`, len(submitted))
+					case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspace_jobs/job-1/status":
+						statusCalls++
+						res.WriteHeader(200)
+						if statusCalls == 1 {
+							fmt.Fprintf(res, "%s", `{"job_status": {"in_progress": ["ws-9", "ws-1"]}}`)
+						} else if statusCalls == 2 {
+							// Workspaces of the batch that are not listed yet are still being deleted.
+							fmt.Fprintf(res, "%s", `{"job_status": {"success": ["ws-1"]}}`)
+						} else {
+							fmt.Fprintf(res, "%s", `{"job_status": {"success": ["ws-1"], "failed": ["ws-9"]}}`)
+						}
+					case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspace_jobs/job-2/status":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"job_status": {"success": ["ws-3"]}}`)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke BulkDeleteWorkspaces successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+				Expect(schematicsService).ToNot(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.BulkDeleteWorkspaces(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				var progress []string
+				bulkDeleteWorkspacesOptionsModel := schematicsService.NewBulkDeleteWorkspacesOptions("testString").
+					SetWorkspaceIDs([]string{"ws-9"}).
+					SetSelector(&schematicsv1.Selector{NamePattern: core.StringPtr("*-dev")}).
+					SetBatchSize(2).
+					SetPollInterval(time.Millisecond).
+					SetOnProgress(func(jobID string, status *schematicsv1.JobStatusType) {
+						progress = append(progress, jobID)
+					})
+				result, response, operationErr = schematicsService.BulkDeleteWorkspaces(bulkDeleteWorkspacesOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(submitted).To(Equal([][]string{{"ws-9", "ws-1"}, {"ws-3"}}))
+				Expect(progress).To(Equal([]string{"job-1", "job-1", "job-1", "job-2"}))
+				Expect(result.Jobs).To(HaveLen(2))
+				Expect(result.Outcomes).To(HaveLen(3))
+				Expect(*result.Outcomes[0].Status).To(Equal(schematicsv1.WorkspaceDeletionOutcome_Status_Failed))
+				Expect(*result.Outcomes[1].Status).To(Equal(schematicsv1.WorkspaceDeletionOutcome_Status_Success))
+				Expect(*result.Outcomes[2].JobID).To(Equal("job-2"))
+				Expect(*result.Outcomes[2].Status).To(Equal(schematicsv1.WorkspaceDeletionOutcome_Status_Success))
+				Expect(result.Failed()).To(HaveLen(1))
+			})
+			It(`Invoke BulkDeleteWorkspaces with error: Destroy not confirmed`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				bulkDeleteWorkspacesOptionsModel := schematicsService.NewBulkDeleteWorkspacesOptions("testString").
+					SetWorkspaceIDs([]string{"ws-1"}).
+					SetDestroyResources(true)
+				result, response, operationErr := schematicsService.BulkDeleteWorkspaces(bulkDeleteWorkspacesOptionsModel)
+				Expect(operationErr).ToNot(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+				Expect(submitted).To(BeEmpty())
+			})
+			It(`Invoke BulkDeleteWorkspaces with error: Empty selector`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				emptySelector, err := schematicsv1.ParseSelector("")
+				Expect(err).To(BeNil())
+				for _, selector := range []*schematicsv1.Selector{{}, emptySelector, {Tags: []string{}}} {
+					bulkDeleteWorkspacesOptionsModel := schematicsService.NewBulkDeleteWorkspacesOptions("testString").SetSelector(selector)
+					result, response, operationErr := schematicsService.BulkDeleteWorkspaces(bulkDeleteWorkspacesOptionsModel)
+					Expect(operationErr).To(MatchError(ContainSubstring("empty Selector")))
+					Expect(response).To(BeNil())
+					Expect(result).To(BeNil())
+				}
+				Expect(submitted).To(BeEmpty())
+			})
+			It(`Invoke BulkDeleteWorkspaces with error: Timeout`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				bulkDeleteWorkspacesOptionsModel := schematicsService.NewBulkDeleteWorkspacesOptions("testString").
+					SetWorkspaceIDs([]string{"ws-1", "ws-3"}).
+					SetDestroyResources(true).
+					SetConfirmDestroy(true).
+					SetPollInterval(time.Millisecond).
+					SetTimeout(time.Millisecond)
+				result, _, operationErr := schematicsService.BulkDeleteWorkspaces(bulkDeleteWorkspacesOptionsModel)
+				Expect(operationErr).To(Equal(schematicsv1.ErrWaitTimeout))
+				Expect(*result.Outcomes[0].Status).To(Equal(schematicsv1.WorkspaceDeletionOutcome_Status_InProgress))
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,284 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"io"
+	"strings"
+)
+
+// CloneWorkspaceOptions : The CloneWorkspace options.
+type CloneWorkspaceOptions struct {
+	// The ID of the workspace to copy.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The name of the new workspace. Defaults to the name of the source workspace.
+	Name *string `json:"name,omitempty"`
+
+	// The description of the new workspace.
+	Description *string `json:"description,omitempty"`
+
+	// The location of the new workspace.
+	Location *string `json:"location,omitempty"`
+
+	// The resource group of the new workspace.
+	ResourceGroup *string `json:"resource_group,omitempty"`
+
+	// The tags of the new workspace. When set, they replace the tags of the source workspace.
+	Tags []string `json:"tags,omitempty"`
+
+	// Variable and environment values to override, keyed by name. Overrides apply to every template of the workspace,
+	// and a name that no template has is an error.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// An optional tar file with the template to upload to the first template of the new workspace. When set, the
+	// template repository of the source workspace is not copied.
+	TemplateTar io.ReadCloser `json:"template_tar,omitempty"`
+
+	// The content type of TemplateTar.
+	TemplateTarContentType *string `json:"template_tar_content_type,omitempty"`
+
+	// The personal access token to authenticate with your private GitHub or GitLab repository.
+	XGithubToken *string `json:"X-Github-token,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCloneWorkspaceOptions : Instantiate CloneWorkspaceOptions
+func (*SchematicsV1) NewCloneWorkspaceOptions(wID string) *CloneWorkspaceOptions {
+	return &CloneWorkspaceOptions{
+		WID: core.StringPtr(wID),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *CloneWorkspaceOptions) SetWID(wID string) *CloneWorkspaceOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetName : Allow user to set Name
+func (options *CloneWorkspaceOptions) SetName(name string) *CloneWorkspaceOptions {
+	options.Name = core.StringPtr(name)
+	return options
+}
+
+// SetDescription : Allow user to set Description
+func (options *CloneWorkspaceOptions) SetDescription(description string) *CloneWorkspaceOptions {
+	options.Description = core.StringPtr(description)
+	return options
+}
+
+// SetLocation : Allow user to set Location
+func (options *CloneWorkspaceOptions) SetLocation(location string) *CloneWorkspaceOptions {
+	options.Location = core.StringPtr(location)
+	return options
+}
+
+// SetResourceGroup : Allow user to set ResourceGroup
+func (options *CloneWorkspaceOptions) SetResourceGroup(resourceGroup string) *CloneWorkspaceOptions {
+	options.ResourceGroup = core.StringPtr(resourceGroup)
+	return options
+}
+
+// SetTags : Allow user to set Tags
+func (options *CloneWorkspaceOptions) SetTags(tags []string) *CloneWorkspaceOptions {
+	options.Tags = tags
+	return options
+}
+
+// SetVariables : Allow user to set Variables
+func (options *CloneWorkspaceOptions) SetVariables(variables map[string]string) *CloneWorkspaceOptions {
+	options.Variables = variables
+	return options
+}
+
+// SetTemplateTar : Allow user to set TemplateTar
+func (options *CloneWorkspaceOptions) SetTemplateTar(templateTar io.ReadCloser) *CloneWorkspaceOptions {
+	options.TemplateTar = templateTar
+	return options
+}
+
+// SetTemplateTarContentType : Allow user to set TemplateTarContentType
+func (options *CloneWorkspaceOptions) SetTemplateTarContentType(templateTarContentType string) *CloneWorkspaceOptions {
+	options.TemplateTarContentType = core.StringPtr(templateTarContentType)
+	return options
+}
+
+// SetXGithubToken : Allow user to set XGithubToken
+func (options *CloneWorkspaceOptions) SetXGithubToken(xGithubToken string) *CloneWorkspaceOptions {
+	options.XGithubToken = core.StringPtr(xGithubToken)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CloneWorkspaceOptions) SetHeaders(param map[string]string) *CloneWorkspaceOptions {
+	options.Headers = param
+	return options
+}
+
+// CloneWorkspaceResult : The result of CloneWorkspace.
+type CloneWorkspaceResult struct {
+	// The new workspace.
+	Workspace *WorkspaceResponse `json:"workspace,omitempty"`
+
+	// The response of the template tar upload, if a tar file was uploaded.
+	TemplateTarUpload *TemplateRepoTarUploadResponse `json:"template_tar_upload,omitempty"`
+
+	// The names of the secure variables that were not overridden. Their values cannot be read from the source workspace
+	// and must be supplied again on the new workspace.
+	SecureVariables []string `json:"secure_variables,omitempty"`
+}
+
+// CloneWorkspace : Copy a workspace
+// Read a workspace and its template inputs, apply the overrides from the options and create a new workspace from the
+// result. Secure variables and environment values that are not overridden are created without a value and are listed
+// in the result so that they can be supplied again. An override of a variable that the source workspace does not have
+// is an error, and no workspace is created.
+func (schematics *SchematicsV1) CloneWorkspace(cloneWorkspaceOptions *CloneWorkspaceOptions) (result *CloneWorkspaceResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(cloneWorkspaceOptions, "cloneWorkspaceOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(cloneWorkspaceOptions, "cloneWorkspaceOptions")
+	if err != nil {
+		return
+	}
+
+	createWorkspaceOptions, response, err := schematics.ExportWorkspace(&ExportWorkspaceOptions{
+		WID:     cloneWorkspaceOptions.WID,
+		Headers: cloneWorkspaceOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	secureVariables, err := applyCloneOverrides(createWorkspaceOptions, cloneWorkspaceOptions)
+	if err != nil {
+		return
+	}
+
+	workspace, response, err := schematics.CreateWorkspace(createWorkspaceOptions)
+	if err != nil {
+		return
+	}
+
+	result = &CloneWorkspaceResult{
+		Workspace:       workspace,
+		SecureVariables: secureVariables,
+	}
+
+	if cloneWorkspaceOptions.TemplateTar != nil {
+		if workspace.ID == nil || len(workspace.TemplateData) == 0 || workspace.TemplateData[0].ID == nil {
+			err = fmt.Errorf("workspace was created but has no template to upload the tar file to")
+			return
+		}
+		result.TemplateTarUpload, response, err = schematics.UploadTemplateTar(&UploadTemplateTarOptions{
+			WID:             workspace.ID,
+			TID:             workspace.TemplateData[0].ID,
+			File:            cloneWorkspaceOptions.TemplateTar,
+			FileContentType: cloneWorkspaceOptions.TemplateTarContentType,
+			Headers:         cloneWorkspaceOptions.Headers,
+		})
+	}
+
+	return
+}
+
+// applyCloneOverrides applies the overrides of cloneWorkspaceOptions to createWorkspaceOptions and returns the names of
+// the secure variables that are left without a value. Overrides of variables that no template has are returned as an
+// error.
+func applyCloneOverrides(createWorkspaceOptions *CreateWorkspaceOptions, cloneWorkspaceOptions *CloneWorkspaceOptions) (secureVariables []string, err error) {
+	if cloneWorkspaceOptions.Name != nil {
+		createWorkspaceOptions.Name = cloneWorkspaceOptions.Name
+	}
+	if cloneWorkspaceOptions.Description != nil {
+		createWorkspaceOptions.Description = cloneWorkspaceOptions.Description
+	}
+	if cloneWorkspaceOptions.Location != nil {
+		createWorkspaceOptions.Location = cloneWorkspaceOptions.Location
+	}
+	if cloneWorkspaceOptions.ResourceGroup != nil {
+		createWorkspaceOptions.ResourceGroup = cloneWorkspaceOptions.ResourceGroup
+	}
+	if cloneWorkspaceOptions.Tags != nil {
+		createWorkspaceOptions.Tags = copyStrings(cloneWorkspaceOptions.Tags)
+	}
+	if cloneWorkspaceOptions.TemplateTar != nil {
+		createWorkspaceOptions.TemplateRepo = nil
+	}
+	createWorkspaceOptions.XGithubToken = cloneWorkspaceOptions.XGithubToken
+	createWorkspaceOptions.Headers = cloneWorkspaceOptions.Headers
+
+	seen, overridden := map[string]bool{}, map[string]bool{}
+	addSecure := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			secureVariables = append(secureVariables, name)
+		}
+	}
+	for i := range createWorkspaceOptions.TemplateData {
+		for _, envValue := range createWorkspaceOptions.TemplateData[i].EnvValues {
+			env, ok := envValue.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, named := env["name"].(string)
+			if value, ok := cloneWorkspaceOptions.Variables[name]; named && ok {
+				env["value"] = value
+				overridden[name] = true
+				continue
+			}
+			if secure, _ := env["secure"].(bool); secure {
+				delete(env, "value")
+				if named {
+					addSecure(name)
+				}
+			}
+		}
+		variablestore := createWorkspaceOptions.TemplateData[i].Variablestore
+		for j := range variablestore {
+			variable := &variablestore[j]
+			if variable.Name == nil {
+				continue
+			}
+			if value, ok := cloneWorkspaceOptions.Variables[*variable.Name]; ok {
+				variable.Value = core.StringPtr(value)
+				overridden[*variable.Name] = true
+				continue
+			}
+			if variable.Secure != nil && *variable.Secure {
+				variable.Value = nil
+				addSecure(*variable.Name)
+			}
+		}
+	}
+
+	var unknown []string
+	for _, name := range sortedKeys(cloneWorkspaceOptions.Variables) {
+		if !overridden[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		err = fmt.Errorf("the source workspace has no variables %s to override", strings.Join(unknown, ", "))
+	}
+	return
+}
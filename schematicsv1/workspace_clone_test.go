@@ -0,0 +1,167 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Workspace cloning`, func() {
+	var testServer *httptest.Server
+	Describe(`CloneWorkspace(cloneWorkspaceOptions *CloneWorkspaceOptions)`, func() {
+		var createBody map[string]interface{}
+		var uploaded bool
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				createBody = nil
+				uploaded = false
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					res.Header().Set("Content-type", "application/json")
+					switch {
+					case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspaces/testString":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"id": "testString", "name": "dev", "location": "us-south", "resource_group": "dev-rg", "tags": ["env:dev"], "type": ["terraform_v0.12"], "template_repo": {"url": "URL"}, "template_data": [{"id": "ID", "folder": ".", "type": "terraform_v0.12"}]}`)
+					case req.Method == "GET" && req.URL.EscapedPath() == "/v1/workspaces/testString/templates/values":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"template_data": [{"id": "ID", "folder": ".", "type": "terraform_v0.12", "env_values": [{"name": "TOKEN", "value": "xyz", "secure": true}], "variablestore": [{"name": "region", "value": "us-south"}, {"name": "api_key", "value": "****", "secure": true}, {"name": "password", "value": "****", "secure": true}]}]}`)
+					case req.Method == "POST" && req.URL.EscapedPath() == "/v1/workspaces":
+						Expect(json.NewDecoder(req.Body).Decode(&createBody)).To(Succeed())
+						res.WriteHeader(201)
+						fmt.Fprintf(res, "%s", `{"id": "newID", "name": "prod", "template_data": [{"id": "newTID"}]}`)
+					case req.Method == "PUT" && req.URL.EscapedPath() == "/v1/workspaces/newID/template_data/newTID/template_repo_upload":
+						uploaded = true
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"file_value": "FileValue", "has_received_file": true, "id": "newTID"}`)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke CloneWorkspace successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+				Expect(schematicsService).ToNot(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.CloneWorkspace(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				cloneWorkspaceOptionsModel := schematicsService.NewCloneWorkspaceOptions("testString").
+					SetName("prod").
+					SetLocation("eu-de").
+					SetResourceGroup("prod-rg").
+					SetTags([]string{"env:prod"}).
+					SetVariables(map[string]string{"region": "eu-de", "api_key": "new-key"})
+				result, response, operationErr = schematicsService.CloneWorkspace(cloneWorkspaceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(*result.Workspace.ID).To(Equal("newID"))
+				Expect(result.TemplateTarUpload).To(BeNil())
+				Expect(result.SecureVariables).To(Equal([]string{"TOKEN", "password"}))
+
+				Expect(createBody).To(HaveKeyWithValue("name", "prod"))
+				Expect(createBody).To(HaveKeyWithValue("location", "eu-de"))
+				Expect(createBody).To(HaveKeyWithValue("resource_group", "prod-rg"))
+				Expect(createBody).To(HaveKey("template_repo"))
+				templateData := createBody["template_data"].([]interface{})[0].(map[string]interface{})
+				variablestore := templateData["variablestore"].([]interface{})
+				Expect(variablestore[0]).To(HaveKeyWithValue("value", "eu-de"))
+				Expect(variablestore[1]).To(HaveKeyWithValue("value", "new-key"))
+				Expect(variablestore[2]).ToNot(HaveKey("value"))
+				Expect(templateData["env_values"].([]interface{})[0]).ToNot(HaveKey("value"))
+			})
+			It(`Invoke CloneWorkspace with overrides of environment values`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				cloneWorkspaceOptionsModel := schematicsService.NewCloneWorkspaceOptions("testString").
+					SetVariables(map[string]string{"TOKEN": "new-token", "password": "new-password"})
+				result, _, operationErr := schematicsService.CloneWorkspace(cloneWorkspaceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(result.SecureVariables).To(Equal([]string{"api_key"}))
+				templateData := createBody["template_data"].([]interface{})[0].(map[string]interface{})
+				Expect(templateData["env_values"].([]interface{})[0]).To(HaveKeyWithValue("value", "new-token"))
+				Expect(templateData["variablestore"].([]interface{})[2]).To(HaveKeyWithValue("value", "new-password"))
+			})
+			It(`Invoke CloneWorkspace with error: Unknown variables`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				cloneWorkspaceOptionsModel := schematicsService.NewCloneWorkspaceOptions("testString").
+					SetVariables(map[string]string{"region": "eu-de", "zone": "2", "regoin": "eu-de"})
+				result, _, operationErr := schematicsService.CloneWorkspace(cloneWorkspaceOptionsModel)
+				Expect(operationErr).To(MatchError("the source workspace has no variables regoin, zone to override"))
+				Expect(result).To(BeNil())
+				Expect(createBody).To(BeNil())
+			})
+			It(`Invoke CloneWorkspace with a template tar`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				cloneWorkspaceOptionsModel := schematicsService.NewCloneWorkspaceOptions("testString").
+					SetTemplateTar(ioutil.NopCloser(bytes.NewBufferString("This is a mock file."))).
+					SetTemplateTarContentType("application/x-tar")
+				result, _, operationErr := schematicsService.CloneWorkspace(cloneWorkspaceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(uploaded).To(BeTrue())
+				Expect(*result.TemplateTarUpload.HasReceivedFile).To(BeTrue())
+				Expect(createBody).ToNot(HaveKey("template_repo"))
+			})
+			It(`Invoke CloneWorkspace with error: Operation request error`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				result, response, operationErr := schematicsService.CloneWorkspace(schematicsService.NewCloneWorkspaceOptions("missing"))
+				Expect(operationErr).ToNot(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result).To(BeNil())
+				Expect(createBody).To(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,613 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+	"time"
+)
+
+// Constants associated with the TerraformCommand.Command property.
+// The Terraform commands supported by RunWorkspaceCommands.
+const (
+	TerraformCommand_Command_Import  = "import"
+	TerraformCommand_Command_Output  = "output"
+	TerraformCommand_Command_StateMv = "state mv"
+	TerraformCommand_Command_StateRm = "state rm"
+	TerraformCommand_Command_Taint   = "taint"
+	TerraformCommand_Command_Untaint = "untaint"
+)
+
+// Constants associated with the TerraformCommand.CommandOnError property.
+// Instruction to continue or break in case of error.
+const (
+	TerraformCommand_CommandOnError_Break    = "break"
+	TerraformCommand_CommandOnError_Continue = "continue"
+)
+
+// Constants associated with the TerraformCommandResult.Status property.
+// The status of a command of a commands activity.
+const (
+	TerraformCommandResult_Status_Completed = "completed"
+	TerraformCommandResult_Status_Failed    = "failed"
+	TerraformCommandResult_Status_NotRun    = "not_run"
+)
+
+// TerraformCommandsBuilder : Build a validated sequence of Terraform commands for RunWorkspaceCommands.
+// Every command is added with a name that other commands can depend on. The modifiers DependsOn, OnError and
+// Description apply to the command added last.
+type TerraformCommandsBuilder struct {
+	commands []TerraformCommand
+}
+
+// NewTerraformCommandsBuilder : Instantiate TerraformCommandsBuilder
+func (*SchematicsV1) NewTerraformCommandsBuilder() *TerraformCommandsBuilder {
+	return &TerraformCommandsBuilder{}
+}
+
+// Add : Add a command built by hand
+func (builder *TerraformCommandsBuilder) Add(command TerraformCommand) *TerraformCommandsBuilder {
+	builder.commands = append(builder.commands, command)
+	return builder
+}
+
+// StateMv : Add a 'state mv' command that moves source to destination
+func (builder *TerraformCommandsBuilder) StateMv(name string, source string, destination string) *TerraformCommandsBuilder {
+	return builder.add(name, TerraformCommand_Command_StateMv, source, destination)
+}
+
+// StateRm : Add a 'state rm' command that removes the addresses from the state
+func (builder *TerraformCommandsBuilder) StateRm(name string, addresses ...string) *TerraformCommandsBuilder {
+	return builder.add(name, TerraformCommand_Command_StateRm, addresses...)
+}
+
+// Import : Add an 'import' command that imports the cloud resource id at address
+func (builder *TerraformCommandsBuilder) Import(name string, address string, id string) *TerraformCommandsBuilder {
+	return builder.add(name, TerraformCommand_Command_Import, address, id)
+}
+
+// Taint : Add a 'taint' command
+func (builder *TerraformCommandsBuilder) Taint(name string, address string) *TerraformCommandsBuilder {
+	return builder.add(name, TerraformCommand_Command_Taint, address)
+}
+
+// Untaint : Add an 'untaint' command
+func (builder *TerraformCommandsBuilder) Untaint(name string, address string) *TerraformCommandsBuilder {
+	return builder.add(name, TerraformCommand_Command_Untaint, address)
+}
+
+// Output : Add an 'output' command. An empty outputName shows every output.
+func (builder *TerraformCommandsBuilder) Output(name string, outputName string) *TerraformCommandsBuilder {
+	if outputName == "" {
+		return builder.add(name, TerraformCommand_Command_Output)
+	}
+	return builder.add(name, TerraformCommand_Command_Output, outputName)
+}
+
+// DependsOn : Make the last command depend on the named commands
+func (builder *TerraformCommandsBuilder) DependsOn(names ...string) *TerraformCommandsBuilder {
+	if last := builder.last(); last != nil {
+		last.CommandDependsOn = core.StringPtr(strings.Join(names, ","))
+	}
+	return builder
+}
+
+// OnError : Set the instruction to continue or break when the last command fails
+func (builder *TerraformCommandsBuilder) OnError(onError string) *TerraformCommandsBuilder {
+	if last := builder.last(); last != nil {
+		last.CommandOnError = core.StringPtr(onError)
+	}
+	return builder
+}
+
+// Description : Set the description of the last command
+func (builder *TerraformCommandsBuilder) Description(description string) *TerraformCommandsBuilder {
+	if last := builder.last(); last != nil {
+		last.CommandDesc = core.StringPtr(description)
+	}
+	return builder
+}
+
+// Build : Validate the commands and return them in dependency order
+func (builder *TerraformCommandsBuilder) Build() ([]TerraformCommand, error) {
+	return ValidateTerraformCommands(builder.commands)
+}
+
+func (builder *TerraformCommandsBuilder) add(name string, command string, params ...string) *TerraformCommandsBuilder {
+	return builder.Add(TerraformCommand{
+		Command:       core.StringPtr(command),
+		CommandName:   core.StringPtr(name),
+		CommandParams: core.StringPtr(strings.Join(params, " ")),
+	})
+}
+
+func (builder *TerraformCommandsBuilder) last() *TerraformCommand {
+	if len(builder.commands) == 0 {
+		return nil
+	}
+	return &builder.commands[len(builder.commands)-1]
+}
+
+// ValidateTerraformCommands validates a sequence of Terraform commands: the command must be supported, its parameters
+// must be well formed, names must be unique and CommandDependsOn must name other commands of the sequence without
+// forming a cycle. The commands are returned in an order where every command follows its dependencies; commands that
+// do not depend on each other keep their relative order. All problems found are reported in a single error.
+func ValidateTerraformCommands(commands []TerraformCommand) ([]TerraformCommand, error) {
+	var problems []string
+	indexes := map[string]int{}
+	for i, command := range commands {
+		name := commandName(command, i)
+		if command.CommandName == nil || *command.CommandName == "" {
+			problems = append(problems, fmt.Sprintf("%s: name is required", name))
+		} else if _, found := indexes[name]; found {
+			problems = append(problems, fmt.Sprintf("%s: name is not unique", name))
+		} else {
+			indexes[name] = i
+		}
+		if err := validateTerraformCommandParams(command); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+		if command.CommandOnError != nil && *command.CommandOnError != "" &&
+			*command.CommandOnError != TerraformCommand_CommandOnError_Break &&
+			*command.CommandOnError != TerraformCommand_CommandOnError_Continue {
+			problems = append(problems, fmt.Sprintf("%s: unsupported command_onError %q", name, *command.CommandOnError))
+		}
+	}
+
+	dependencies := make([][]int, len(commands))
+	for i, command := range commands {
+		for _, dependency := range commandDependencies(command) {
+			j, found := indexes[dependency]
+			switch {
+			case !found:
+				problems = append(problems, fmt.Sprintf("%s: depends on unknown command %q", commandName(command, i), dependency))
+			case j == i:
+				problems = append(problems, fmt.Sprintf("%s: depends on itself", commandName(command, i)))
+			default:
+				dependencies[i] = append(dependencies[i], j)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid terraform commands: %s", strings.Join(problems, "; "))
+	}
+
+	// Repeatedly pick the first command whose dependencies are all placed.
+	ordered := make([]TerraformCommand, 0, len(commands))
+	placed := make([]bool, len(commands))
+	for len(ordered) < len(commands) {
+		next := -1
+		for i := range commands {
+			if !placed[i] && dependenciesPlaced(dependencies[i], placed) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i := range commands {
+				if !placed[i] {
+					cycle = append(cycle, commandName(commands[i], i))
+				}
+			}
+			return nil, fmt.Errorf("invalid terraform commands: dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+		placed[next] = true
+		ordered = append(ordered, commands[next])
+	}
+	return ordered, nil
+}
+
+// validateTerraformCommandParams checks the parameters of a command against the syntax of the command. Parameters
+// starting with '-' are flags and are not checked.
+func validateTerraformCommandParams(command TerraformCommand) error {
+	if command.Command == nil || *command.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+	var params []string
+	if command.CommandParams != nil {
+		for _, param := range splitTerraformCommandParams(*command.CommandParams) {
+			if !strings.HasPrefix(param, "-") {
+				params = append(params, param)
+			}
+		}
+	}
+
+	switch *command.Command {
+	case TerraformCommand_Command_StateMv:
+		if len(params) != 2 {
+			return fmt.Errorf("state mv expects a source and a destination address")
+		}
+		for _, param := range params {
+			if _, err := parseConcreteTerraformAddress(param); err != nil {
+				return err
+			}
+		}
+	case TerraformCommand_Command_StateRm:
+		if len(params) == 0 {
+			return fmt.Errorf("state rm expects at least one address")
+		}
+		for _, param := range params {
+			if _, err := parseConcreteTerraformAddress(param); err != nil {
+				return err
+			}
+		}
+	case TerraformCommand_Command_Import:
+		if len(params) != 2 {
+			return fmt.Errorf("import expects an address and a resource ID")
+		}
+		address, err := parseConcreteTerraformAddress(params[0])
+		if err != nil {
+			return err
+		}
+		if address.IsModule() || address.Data {
+			return fmt.Errorf("%s is not a managed resource address", params[0])
+		}
+	case TerraformCommand_Command_Taint, TerraformCommand_Command_Untaint:
+		if len(params) != 1 {
+			return fmt.Errorf("%s expects a single address", *command.Command)
+		}
+		address, err := parseConcreteTerraformAddress(params[0])
+		if err != nil {
+			return err
+		}
+		if address.IsModule() {
+			return fmt.Errorf("%s is not a resource address", params[0])
+		}
+	case TerraformCommand_Command_Output:
+		if len(params) > 1 {
+			return fmt.Errorf("output expects at most one output name")
+		}
+		if len(params) == 1 && !terraformIdentifierRegexp.MatchString(params[0]) {
+			return fmt.Errorf("invalid output name %q", params[0])
+		}
+	default:
+		return fmt.Errorf("unsupported command %q", *command.Command)
+	}
+	return nil
+}
+
+// parseConcreteTerraformAddress parses an address that must not contain wildcards.
+func parseConcreteTerraformAddress(address string) (*TerraformAddress, error) {
+	parsed, err := ParseTerraformAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.HasWildcard() {
+		return nil, fmt.Errorf("terraform address %q must not contain wildcards", address)
+	}
+	return parsed, nil
+}
+
+// splitTerraformCommandParams splits command parameters at the spaces that are not within the instance key of an
+// address, so that keys such as ["a b"] or [ 0 ] stay in their parameter.
+func splitTerraformCommandParams(params string) (fields []string) {
+	var field strings.Builder
+	depth, quoted := 0, false
+	for i := 0; i < len(params); i++ {
+		c := params[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(params):
+			field.WriteByte(c)
+			i++
+			c = params[i]
+		case depth > 0 && c == '"':
+			quoted = !quoted
+		case !quoted && c == '[':
+			depth++
+		case !quoted && c == ']' && depth > 0:
+			depth--
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteByte(c)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return
+}
+
+// commandName returns the name of a command, or its position when it has none.
+func commandName(command TerraformCommand, index int) string {
+	if command.CommandName == nil || *command.CommandName == "" {
+		return fmt.Sprintf("command %d", index)
+	}
+	return *command.CommandName
+}
+
+// commandDependencies splits the comma separated CommandDependsOn of a command.
+func commandDependencies(command TerraformCommand) (names []string) {
+	if command.CommandDependsOn == nil {
+		return
+	}
+	for _, name := range strings.Split(*command.CommandDependsOn, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+func dependenciesPlaced(dependencies []int, placed []bool) bool {
+	for _, dependency := range dependencies {
+		if !placed[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+// TerraformCommandResult : The result of a command of a commands activity.
+type TerraformCommandResult struct {
+	// The command name.
+	CommandName *string `json:"command_name,omitempty"`
+
+	// The command.
+	Command *string `json:"command,omitempty"`
+
+	// The command parameters.
+	CommandParams *string `json:"command_params,omitempty"`
+
+	// The status of the command.
+	Status *string `json:"status,omitempty"`
+
+	// The log lines written by the command.
+	Output *string `json:"output,omitempty"`
+}
+
+// TerraformCommandsResult : The result of a commands activity.
+type TerraformCommandsResult struct {
+	// The ID of the commands activity.
+	ActivityID *string `json:"activity_id,omitempty"`
+
+	// The final status of the activity.
+	Status *string `json:"status,omitempty"`
+
+	// The result of each command, in the order they were submitted.
+	Commands []TerraformCommandResult `json:"commands,omitempty"`
+}
+
+// RunTerraformCommandsOptions : The RunTerraformCommands options.
+type RunTerraformCommandsOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// List of commands.
+	Commands []TerraformCommand `json:"commands" validate:"required,min=1"`
+
+	// Command name.
+	OperationName *string `json:"operation_name,omitempty"`
+
+	// Command description.
+	Description *string `json:"description,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRunTerraformCommandsOptions : Instantiate RunTerraformCommandsOptions
+func (*SchematicsV1) NewRunTerraformCommandsOptions(wID string, refreshToken string, commands []TerraformCommand) *RunTerraformCommandsOptions {
+	return &RunTerraformCommandsOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Commands:     commands,
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *RunTerraformCommandsOptions) SetWID(wID string) *RunTerraformCommandsOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *RunTerraformCommandsOptions) SetRefreshToken(refreshToken string) *RunTerraformCommandsOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetCommands : Allow user to set Commands
+func (options *RunTerraformCommandsOptions) SetCommands(commands []TerraformCommand) *RunTerraformCommandsOptions {
+	options.Commands = commands
+	return options
+}
+
+// SetOperationName : Allow user to set OperationName
+func (options *RunTerraformCommandsOptions) SetOperationName(operationName string) *RunTerraformCommandsOptions {
+	options.OperationName = core.StringPtr(operationName)
+	return options
+}
+
+// SetDescription : Allow user to set Description
+func (options *RunTerraformCommandsOptions) SetDescription(description string) *RunTerraformCommandsOptions {
+	options.Description = core.StringPtr(description)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *RunTerraformCommandsOptions) SetPollInterval(pollInterval time.Duration) *RunTerraformCommandsOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *RunTerraformCommandsOptions) SetTimeout(timeout time.Duration) *RunTerraformCommandsOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RunTerraformCommandsOptions) SetHeaders(param map[string]string) *RunTerraformCommandsOptions {
+	options.Headers = param
+	return options
+}
+
+// RunTerraformCommands : Run validated Terraform commands and wait for them
+// Validate the commands with ValidateTerraformCommands, submit them in dependency order with RunWorkspaceCommands and
+// wait for the activity to finish. The result of each command is read from the activity log. When the activity did not
+// complete, the result is returned with a *WorkspaceActivityFailedError.
+func (schematics *SchematicsV1) RunTerraformCommands(runTerraformCommandsOptions *RunTerraformCommandsOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(runTerraformCommandsOptions, "runTerraformCommandsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(runTerraformCommandsOptions, "runTerraformCommandsOptions")
+	if err != nil {
+		return
+	}
+	commands, err := ValidateTerraformCommands(runTerraformCommandsOptions.Commands)
+	if err != nil {
+		return
+	}
+
+	run, response, err := schematics.RunWorkspaceCommands(&RunWorkspaceCommandsOptions{
+		WID:           runTerraformCommandsOptions.WID,
+		RefreshToken:  runTerraformCommandsOptions.RefreshToken,
+		Commands:      commands,
+		OperationName: runTerraformCommandsOptions.OperationName,
+		Description:   runTerraformCommandsOptions.Description,
+		Headers:       runTerraformCommandsOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	if run.Activityid == nil {
+		err = fmt.Errorf("commands of workspace %s did not return an activity ID", *runTerraformCommandsOptions.WID)
+		return
+	}
+
+	activity, response, waitErr := schematics.WaitForWorkspaceActivity(&WaitForWorkspaceActivityOptions{
+		WID:          runTerraformCommandsOptions.WID,
+		ActivityID:   run.Activityid,
+		PollInterval: runTerraformCommandsOptions.PollInterval,
+		Timeout:      runTerraformCommandsOptions.Timeout,
+		Headers:      runTerraformCommandsOptions.Headers,
+	})
+	if _, failed := waitErr.(*WorkspaceActivityFailedError); waitErr != nil && !failed {
+		err = waitErr
+		return
+	}
+
+	var logs []string
+	for _, template := range activity.Templates {
+		if template.TemplateID == nil {
+			continue
+		}
+		var log *string
+		log, response, err = schematics.GetTemplateActivityLog(&GetTemplateActivityLogOptions{
+			WID:        runTerraformCommandsOptions.WID,
+			TID:        template.TemplateID,
+			ActivityID: run.Activityid,
+			Headers:    runTerraformCommandsOptions.Headers,
+		})
+		if err != nil {
+			return
+		}
+		if log != nil {
+			logs = append(logs, *log)
+		}
+	}
+
+	result = &TerraformCommandsResult{
+		ActivityID: run.Activityid,
+		Status:     activity.Status,
+		Commands:   ParseTerraformCommandResults(commands, strings.Join(logs, "\n")),
+	}
+	err = waitErr
+	return
+}
+
+// ParseTerraformCommandResults reads the result of each command from the log of a commands activity. The output of a
+// command starts at the first line, after the output of the previous command, that runs 'terraform <command>' with
+// the first parameter of the command. A command whose output contains a Terraform 'Error:' line failed; a command
+// whose output was not found did not run.
+func ParseTerraformCommandResults(commands []TerraformCommand, log string) []TerraformCommandResult {
+	lines := strings.Split(log, "\n")
+	starts := make([]int, len(commands))
+	position := 0
+	for i, command := range commands {
+		starts[i] = -1
+		marker := terraformCommandMarker(command)
+		for j := position; j < len(lines); j++ {
+			if strings.Contains(lines[j], marker) {
+				starts[i] = j
+				position = j + 1
+				break
+			}
+		}
+	}
+
+	results := make([]TerraformCommandResult, len(commands))
+	for i, command := range commands {
+		results[i] = TerraformCommandResult{
+			CommandName:   command.CommandName,
+			Command:       command.Command,
+			CommandParams: command.CommandParams,
+			Status:        core.StringPtr(TerraformCommandResult_Status_NotRun),
+		}
+		if starts[i] < 0 {
+			continue
+		}
+		end := len(lines)
+		for _, start := range starts[i+1:] {
+			if start >= 0 {
+				end = start
+				break
+			}
+		}
+		output := lines[starts[i]:end]
+		results[i].Output = core.StringPtr(strings.TrimRight(strings.Join(output, "\n"), "\n"))
+		results[i].Status = core.StringPtr(TerraformCommandResult_Status_Completed)
+		for _, line := range output {
+			if strings.Contains(line, "Error:") {
+				results[i].Status = core.StringPtr(TerraformCommandResult_Status_Failed)
+				break
+			}
+		}
+	}
+	return results
+}
+
+// terraformCommandMarker returns the text that the log line starting a command contains.
+func terraformCommandMarker(command TerraformCommand) string {
+	marker := "terraform"
+	if command.Command != nil {
+		marker += " " + *command.Command
+	}
+	if command.CommandParams != nil {
+		if params := splitTerraformCommandParams(*command.CommandParams); len(params) > 0 {
+			marker += " " + params[0]
+		}
+	}
+	return marker
+}
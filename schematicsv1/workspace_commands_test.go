@@ -0,0 +1,197 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+var _ = Describe(`Workspace commands`, func() {
+	var testServer *httptest.Server
+	commandsLog := `
+2021/03/01 10:00:00 Starting command
+2021/03/01 10:00:01 Running terraform state mv ibm_is_vpc.old ibm_is_vpc.new
+2021/03/01 10:00:02 Move "ibm_is_vpc.old" to "ibm_is_vpc.new"
+2021/03/01 10:00:03 Running terraform import ibm_is_subnet.subnet 0717-abc
+2021/03/01 10:00:04 Error: resource already managed by Terraform
+`
+
+	Describe(`TerraformCommandsBuilder`, func() {
+		schematicsService, _ := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+			URL:           "http://schematicsv1modelgenerator.com",
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		It(`Build commands in dependency order`, func() {
+			commands, err := schematicsService.NewTerraformCommandsBuilder().
+				Taint("taint", "module.app.ibm_is_instance.vsi[0]").DependsOn("import").
+				StateMv("move", "ibm_is_vpc.old", "module.network.ibm_is_vpc.vpc").
+				Import("import", "ibm_is_subnet.subnet", "0717-abc").DependsOn("move").OnError(schematicsv1.TerraformCommand_CommandOnError_Break).
+				Output("output", "").Description("Show the outputs").
+				Build()
+			Expect(err).To(BeNil())
+			Expect(commands).To(HaveLen(4))
+			Expect(*commands[0].CommandName).To(Equal("move"))
+			Expect(*commands[0].Command).To(Equal("state mv"))
+			Expect(*commands[0].CommandParams).To(Equal("ibm_is_vpc.old module.network.ibm_is_vpc.vpc"))
+			Expect(*commands[1].CommandName).To(Equal("import"))
+			Expect(*commands[1].CommandDependsOn).To(Equal("move"))
+			Expect(*commands[1].CommandOnError).To(Equal("break"))
+			Expect(*commands[2].CommandName).To(Equal("taint"))
+			Expect(*commands[3].CommandName).To(Equal("output"))
+			Expect(*commands[3].CommandDesc).To(Equal("Show the outputs"))
+		})
+		It(`Reject invalid commands`, func() {
+			_, err := schematicsService.NewTerraformCommandsBuilder().
+				StateMv("move", "ibm_is_vpc", "ibm_is_vpc.new").
+				StateRm("move").
+				Import("import", "data.ibm_is_image.image", "r006").
+				Taint("taint", "ibm_is_vpc.vpc").DependsOn("missing").OnError("retry").
+				Add(schematicsv1.TerraformCommand{CommandName: core.StringPtr("apply"), Command: core.StringPtr("apply")}).
+				Build()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`move: invalid terraform address "ibm_is_vpc"`))
+			Expect(err.Error()).To(ContainSubstring(`move: name is not unique`))
+			Expect(err.Error()).To(ContainSubstring(`state rm expects at least one address`))
+			Expect(err.Error()).To(ContainSubstring(`data.ibm_is_image.image is not a managed resource address`))
+			Expect(err.Error()).To(ContainSubstring(`taint: depends on unknown command "missing"`))
+			Expect(err.Error()).To(ContainSubstring(`unsupported command_onError "retry"`))
+			Expect(err.Error()).To(ContainSubstring(`apply: unsupported command "apply"`))
+		})
+		It(`Reject dependency cycles`, func() {
+			_, err := schematicsService.NewTerraformCommandsBuilder().
+				Taint("a", "ibm_is_vpc.vpc").DependsOn("c").
+				Untaint("b", "ibm_is_vpc.vpc").DependsOn("a").
+				Output("c", "vpc_id").DependsOn("b").
+				Output("d", "").
+				Build()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("dependency cycle between a, b, c"))
+
+			_, err = schematicsService.NewTerraformCommandsBuilder().Output("a", "").DependsOn("a").Build()
+			Expect(err.Error()).To(ContainSubstring("a: depends on itself"))
+		})
+	})
+	Describe(`ParseTerraformCommandResults(commands []TerraformCommand, log string)`, func() {
+		It(`Read the result of each command from the log`, func() {
+			commands, err := new(schematicsv1.TerraformCommandsBuilder).
+				StateMv("move", "ibm_is_vpc.old", "ibm_is_vpc.new").
+				Import("import", "ibm_is_subnet.subnet", "0717-abc").
+				Output("output", "").
+				Build()
+			Expect(err).To(BeNil())
+
+			results := schematicsv1.ParseTerraformCommandResults(commands, commandsLog)
+			Expect(results).To(HaveLen(3))
+			Expect(*results[0].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_Completed))
+			Expect(*results[0].Output).To(ContainSubstring(`Move "ibm_is_vpc.old"`))
+			Expect(*results[0].Output).ToNot(ContainSubstring("import"))
+			Expect(*results[1].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_Failed))
+			Expect(*results[2].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_NotRun))
+			Expect(results[2].Output).To(BeNil())
+		})
+	})
+	Describe(`RunTerraformCommands(runTerraformCommandsOptions *RunTerraformCommandsOptions)`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				polls := 0
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					res.Header().Set("Content-type", "application/json")
+					path := req.URL.EscapedPath()
+					switch {
+					case req.Method == "PUT" && path == "/v1/workspaces/ws-1/commands":
+						Expect(req.Header.Get("refresh_token")).To(Equal("testString"))
+						body, _ := ioutil.ReadAll(req.Body)
+						var submitted schematicsv1.RunWorkspaceCommandsOptions
+						Expect(json.Unmarshal(body, &submitted)).To(Succeed())
+						Expect(*submitted.OperationName).To(Equal("surgery"))
+						Expect(submitted.Commands).To(HaveLen(2))
+						Expect(*submitted.Commands[0].CommandName).To(Equal("move"))
+						res.WriteHeader(202)
+						fmt.Fprintf(res, "%s", `{"activityid": "cmd-1"}`)
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1/runtime_data/t-1/log_store/actions/cmd-1":
+						log, _ := json.Marshal(commandsLog)
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", log)
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1/actions/cmd-1":
+						polls++
+						status := "INPROGRESS"
+						if polls > 1 {
+							status = "FAILED"
+						}
+						res.WriteHeader(200)
+						fmt.Fprintf(res, `{"action_id": "cmd-1", "status": "%s", "message": ["import failed"], "templates": [{"template_id": "t-1"}]}`, status)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke RunTerraformCommands successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.RunTerraformCommands(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				// Invalid commands are not submitted
+				invalid := []schematicsv1.TerraformCommand{{CommandName: core.StringPtr("x"), Command: core.StringPtr("apply")}}
+				result, response, operationErr = schematicsService.RunTerraformCommands(schematicsService.NewRunTerraformCommandsOptions("ws-1", "testString", invalid))
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+
+				commands := []schematicsv1.TerraformCommand{
+					{CommandName: core.StringPtr("import"), Command: core.StringPtr("import"), CommandParams: core.StringPtr("ibm_is_subnet.subnet 0717-abc"), CommandDependsOn: core.StringPtr("move")},
+					{CommandName: core.StringPtr("move"), Command: core.StringPtr("state mv"), CommandParams: core.StringPtr("ibm_is_vpc.old ibm_is_vpc.new")},
+				}
+				runTerraformCommandsOptionsModel := schematicsService.NewRunTerraformCommandsOptions("ws-1", "testString", commands).
+					SetOperationName("surgery").
+					SetPollInterval(time.Millisecond)
+				result, response, operationErr = schematicsService.RunTerraformCommands(runTerraformCommandsOptionsModel)
+				Expect(response).ToNot(BeNil())
+				activityErr, ok := operationErr.(*schematicsv1.WorkspaceActivityFailedError)
+				Expect(ok).To(BeTrue())
+				Expect(strings.Join(activityErr.Messages, "")).To(Equal("import failed"))
+				Expect(*result.ActivityID).To(Equal("cmd-1"))
+				Expect(*result.Status).To(Equal("FAILED"))
+				Expect(result.Commands).To(HaveLen(2))
+				Expect(*result.Commands[0].CommandName).To(Equal("move"))
+				Expect(*result.Commands[0].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_Completed))
+				Expect(*result.Commands[1].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_Failed))
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
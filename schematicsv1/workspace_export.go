@@ -0,0 +1,434 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"io"
+)
+
+// DefaultSecurePlaceholder is the value written in place of secure variable values when a workspace or an action is
+// exported.
+const DefaultSecurePlaceholder = "<secure-value>"
+
+// ExportWorkspaceOptions : The ExportWorkspace options.
+type ExportWorkspaceOptions struct {
+	// The ID of the workspace to export.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The value used in place of secure variable values. Defaults to DefaultSecurePlaceholder.
+	SecurePlaceholder *string `json:"secure_placeholder,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewExportWorkspaceOptions : Instantiate ExportWorkspaceOptions
+func (*SchematicsV1) NewExportWorkspaceOptions(wID string) *ExportWorkspaceOptions {
+	return &ExportWorkspaceOptions{
+		WID: core.StringPtr(wID),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *ExportWorkspaceOptions) SetWID(wID string) *ExportWorkspaceOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetSecurePlaceholder : Allow user to set SecurePlaceholder
+func (options *ExportWorkspaceOptions) SetSecurePlaceholder(securePlaceholder string) *ExportWorkspaceOptions {
+	options.SecurePlaceholder = core.StringPtr(securePlaceholder)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ExportWorkspaceOptions) SetHeaders(param map[string]string) *ExportWorkspaceOptions {
+	options.Headers = param
+	return options
+}
+
+// ExportActionOptions : The ExportAction options.
+type ExportActionOptions struct {
+	// The ID of the action to export.
+	ActionID *string `json:"action_id" validate:"required,ne="`
+
+	// The value used in place of secure values. Defaults to DefaultSecurePlaceholder.
+	SecurePlaceholder *string `json:"secure_placeholder,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewExportActionOptions : Instantiate ExportActionOptions
+func (*SchematicsV1) NewExportActionOptions(actionID string) *ExportActionOptions {
+	return &ExportActionOptions{
+		ActionID: core.StringPtr(actionID),
+	}
+}
+
+// SetActionID : Allow user to set ActionID
+func (options *ExportActionOptions) SetActionID(actionID string) *ExportActionOptions {
+	options.ActionID = core.StringPtr(actionID)
+	return options
+}
+
+// SetSecurePlaceholder : Allow user to set SecurePlaceholder
+func (options *ExportActionOptions) SetSecurePlaceholder(securePlaceholder string) *ExportActionOptions {
+	options.SecurePlaceholder = core.StringPtr(securePlaceholder)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ExportActionOptions) SetHeaders(param map[string]string) *ExportActionOptions {
+	options.Headers = param
+	return options
+}
+
+// ExportWorkspace : Export a workspace as create options
+// Read a workspace and all of its template inputs and convert them into a CreateWorkspaceOptions payload that can be
+// used to recreate the workspace, for example in another account or region. Server-managed fields are dropped and
+// secure variable values are replaced with a placeholder.
+func (schematics *SchematicsV1) ExportWorkspace(exportWorkspaceOptions *ExportWorkspaceOptions) (result *CreateWorkspaceOptions, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(exportWorkspaceOptions, "exportWorkspaceOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(exportWorkspaceOptions, "exportWorkspaceOptions")
+	if err != nil {
+		return
+	}
+
+	workspace, response, err := schematics.GetWorkspace(&GetWorkspaceOptions{
+		WID:     exportWorkspaceOptions.WID,
+		Headers: exportWorkspaceOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	inputs, response, err := schematics.GetAllWorkspaceInputs(&GetAllWorkspaceInputsOptions{
+		WID:     exportWorkspaceOptions.WID,
+		Headers: exportWorkspaceOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	placeholder := DefaultSecurePlaceholder
+	if exportWorkspaceOptions.SecurePlaceholder != nil {
+		placeholder = *exportWorkspaceOptions.SecurePlaceholder
+	}
+	result = NewCreateWorkspaceOptionsFromWorkspace(workspace, inputs, placeholder)
+	return
+}
+
+// ExportAction : Export an action as create options
+// Read an action and convert it into a CreateActionOptions payload that can be used to recreate the action. Server-managed
+// fields are dropped and secure values are replaced with a placeholder.
+func (schematics *SchematicsV1) ExportAction(exportActionOptions *ExportActionOptions) (result *CreateActionOptions, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(exportActionOptions, "exportActionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(exportActionOptions, "exportActionOptions")
+	if err != nil {
+		return
+	}
+
+	action, response, err := schematics.GetAction(&GetActionOptions{
+		ActionID: exportActionOptions.ActionID,
+		Headers:  exportActionOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+
+	placeholder := DefaultSecurePlaceholder
+	if exportActionOptions.SecurePlaceholder != nil {
+		placeholder = *exportActionOptions.SecurePlaceholder
+	}
+	result = NewCreateActionOptionsFromAction(action, placeholder)
+	return
+}
+
+// NewCreateWorkspaceOptionsFromWorkspace converts a workspace, and optionally the template inputs returned by
+// GetAllWorkspaceInputs, into create options. When inputs is nil the template data of the workspace itself is used.
+// Secure variable values are replaced with placeholder.
+func NewCreateWorkspaceOptionsFromWorkspace(workspace *WorkspaceResponse, inputs *WorkspaceTemplateValuesResponse, placeholder string) *CreateWorkspaceOptions {
+	if workspace == nil {
+		return nil
+	}
+
+	options := &CreateWorkspaceOptions{
+		AppliedShareddataIds: copyStrings(workspace.AppliedShareddataIds),
+		Description:          copyString(workspace.Description),
+		Location:             copyString(workspace.Location),
+		Name:                 copyString(workspace.Name),
+		ResourceGroup:        copyString(workspace.ResourceGroup),
+		Tags:                 copyStrings(workspace.Tags),
+		TemplateRef:          copyString(workspace.TemplateRef),
+		Type:                 copyStrings(workspace.Type),
+	}
+
+	if workspace.CatalogRef != nil {
+		catalogRef := *workspace.CatalogRef
+		options.CatalogRef = &catalogRef
+	}
+
+	if workspace.SharedData != nil {
+		options.SharedData = &SharedTargetData{
+			ClusterID:       copyString(workspace.SharedData.ClusterID),
+			ClusterName:     copyString(workspace.SharedData.ClusterName),
+			EntitlementKeys: workspace.SharedData.EntitlementKeys,
+			Namespace:       copyString(workspace.SharedData.Namespace),
+			Region:          copyString(workspace.SharedData.Region),
+			ResourceGroupID: copyString(workspace.SharedData.ResourceGroupID),
+		}
+	}
+
+	if workspace.TemplateRepo != nil {
+		options.TemplateRepo = &TemplateRepoRequest{
+			Branch:       copyString(workspace.TemplateRepo.Branch),
+			Release:      copyString(workspace.TemplateRepo.Release),
+			RepoShaValue: copyString(workspace.TemplateRepo.RepoShaValue),
+			RepoURL:      copyString(workspace.TemplateRepo.RepoURL),
+			URL:          copyString(workspace.TemplateRepo.URL),
+		}
+	}
+
+	templateData := workspace.TemplateData
+	if inputs != nil && len(inputs.TemplateData) > 0 {
+		templateData = inputs.TemplateData
+	}
+	for _, template := range templateData {
+		options.TemplateData = append(options.TemplateData, newTemplateSourceDataRequest(template, placeholder))
+	}
+
+	return options
+}
+
+// NewCreateActionOptionsFromAction converts an action into create options. Secure values and the git token of the
+// action source are replaced with placeholder.
+func NewCreateActionOptionsFromAction(action *Action, placeholder string) *CreateActionOptions {
+	if action == nil {
+		return nil
+	}
+
+	options := &CreateActionOptions{
+		Name:              copyString(action.Name),
+		Description:       copyString(action.Description),
+		Location:          copyString(action.Location),
+		ResourceGroup:     copyString(action.ResourceGroup),
+		Tags:              copyStrings(action.Tags),
+		SourceReadmeURL:   copyString(action.SourceReadmeURL),
+		SourceType:        copyString(action.SourceType),
+		CommandParameter:  copyString(action.CommandParameter),
+		Inventory:         copyString(action.Inventory),
+		BastionCredential: exportVariableData(action.BastionCredential, placeholder),
+		Credentials:       exportVariableDataList(action.Credentials, placeholder),
+		Inputs:            exportVariableDataList(action.Inputs, placeholder),
+		Outputs:           exportVariableDataList(action.Outputs, placeholder),
+		Settings:          exportVariableDataList(action.Settings, placeholder),
+	}
+
+	if action.UserState != nil && action.UserState.State != nil {
+		options.UserState = &UserState{
+			State: copyString(action.UserState.State),
+		}
+	}
+
+	if action.Bastion != nil {
+		bastion := *action.Bastion
+		options.Bastion = &bastion
+	}
+
+	if action.Source != nil {
+		options.Source = &ExternalSource{
+			SourceType: copyString(action.Source.SourceType),
+		}
+		if action.Source.Git != nil {
+			git := *action.Source.Git
+			if git.GitToken != nil && *git.GitToken != "" {
+				git.GitToken = core.StringPtr(placeholder)
+			}
+			options.Source.Git = &git
+		}
+	}
+
+	return options
+}
+
+// workspaceSpec hides the fields of CreateWorkspaceOptions that do not belong in a spec file. The shadowing fields are
+// never set, so they are omitted on write and discarded on read.
+type workspaceSpec struct {
+	*CreateWorkspaceOptions
+	XGithubToken *string           `json:"X-Github-token,omitempty"`
+	Headers      map[string]string `json:"Headers,omitempty"`
+}
+
+// actionSpec hides the fields of CreateActionOptions that do not belong in a spec file.
+type actionSpec struct {
+	*CreateActionOptions
+	XGithubToken *string           `json:"X-Github-token,omitempty"`
+	Headers      map[string]string `json:"Headers,omitempty"`
+}
+
+// WriteWorkspaceSpec writes the create options of a workspace to w as an indented JSON spec file. Request headers and
+// the GitHub token are never written.
+func WriteWorkspaceSpec(w io.Writer, options *CreateWorkspaceOptions) error {
+	if options == nil {
+		return fmt.Errorf("options cannot be nil")
+	}
+	return writeSpec(w, workspaceSpec{CreateWorkspaceOptions: options})
+}
+
+// ReadWorkspaceSpec reads a spec file written by WriteWorkspaceSpec.
+func ReadWorkspaceSpec(r io.Reader) (options *CreateWorkspaceOptions, err error) {
+	options = new(CreateWorkspaceOptions)
+	err = json.NewDecoder(r).Decode(&workspaceSpec{CreateWorkspaceOptions: options})
+	if err != nil {
+		options = nil
+	}
+	return
+}
+
+// WriteActionSpec writes the create options of an action to w as an indented JSON spec file. Request headers and the
+// GitHub token are never written.
+func WriteActionSpec(w io.Writer, options *CreateActionOptions) error {
+	if options == nil {
+		return fmt.Errorf("options cannot be nil")
+	}
+	return writeSpec(w, actionSpec{CreateActionOptions: options})
+}
+
+// ReadActionSpec reads a spec file written by WriteActionSpec.
+func ReadActionSpec(r io.Reader) (options *CreateActionOptions, err error) {
+	options = new(CreateActionOptions)
+	err = json.NewDecoder(r).Decode(&actionSpec{CreateActionOptions: options})
+	if err != nil {
+		options = nil
+	}
+	return
+}
+
+func writeSpec(w io.Writer, spec interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(spec)
+}
+
+func newTemplateSourceDataRequest(template TemplateSourceDataResponse, placeholder string) TemplateSourceDataRequest {
+	request := TemplateSourceDataRequest{
+		Folder:              copyString(template.Folder),
+		Type:                copyString(template.Type),
+		UninstallScriptName: copyString(template.UninstallScriptName),
+		Values:              copyString(template.Values),
+		ValuesMetadata:      template.ValuesMetadata,
+	}
+
+	for _, env := range template.EnvValues {
+		value := copyString(env.Value)
+		if env.Secure != nil && *env.Secure {
+			value = core.StringPtr(placeholder)
+		}
+		envValue := map[string]interface{}{}
+		if env.Name != nil {
+			envValue["name"] = *env.Name
+		}
+		if value != nil {
+			envValue["value"] = *value
+		}
+		if env.Secure != nil {
+			envValue["secure"] = *env.Secure
+		}
+		if env.Hidden != nil {
+			envValue["hidden"] = *env.Hidden
+		}
+		request.EnvValues = append(request.EnvValues, envValue)
+	}
+
+	for _, variable := range template.Variablestore {
+		value := copyString(variable.Value)
+		if variable.Secure != nil && *variable.Secure {
+			value = core.StringPtr(placeholder)
+		}
+		request.Variablestore = append(request.Variablestore, WorkspaceVariableRequest{
+			Description: copyString(variable.Description),
+			Name:        copyString(variable.Name),
+			Secure:      copyBool(variable.Secure),
+			Type:        copyString(variable.Type),
+			Value:       value,
+		})
+	}
+
+	return request
+}
+
+func exportVariableData(variable *VariableData, placeholder string) *VariableData {
+	if variable == nil {
+		return nil
+	}
+	exported := &VariableData{
+		Name:  copyString(variable.Name),
+		Value: copyString(variable.Value),
+		Link:  copyString(variable.Link),
+	}
+	if variable.Metadata != nil {
+		metadata := *variable.Metadata
+		metadata.Aliases = copyStrings(variable.Metadata.Aliases)
+		metadata.Options = copyStrings(variable.Metadata.Options)
+		exported.Metadata = &metadata
+		if metadata.Secure != nil && *metadata.Secure {
+			exported.Value = core.StringPtr(placeholder)
+			if metadata.DefaultValue != nil {
+				metadata.DefaultValue = core.StringPtr(placeholder)
+			}
+		}
+	}
+	return exported
+}
+
+func exportVariableDataList(variables []VariableData, placeholder string) (exported []VariableData) {
+	for i := range variables {
+		exported = append(exported, *exportVariableData(&variables[i], placeholder))
+	}
+	return
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	return core.StringPtr(*s)
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	return core.BoolPtr(*b)
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
@@ -0,0 +1,189 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Workspace and action export`, func() {
+	var testServer *httptest.Server
+	Describe(`ExportWorkspace(exportWorkspaceOptions *ExportWorkspaceOptions)`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.Method).To(Equal("GET"))
+					res.Header().Set("Content-type", "application/json")
+					switch req.URL.EscapedPath() {
+					case "/v1/workspaces/testString":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"id": "testString", "crn": "Crn", "name": "Name", "location": "us-south", "resource_group": "Default", "status": "ACTIVE", "created_at": "2019-01-01T12:00:00", "tags": ["env:dev"], "type": ["terraform_v0.12"], "template_repo": {"url": "URL", "full_url": "FullURL", "branch": "master"}, "workspace_status": {"locked": true, "locked_by": "LockedBy"}, "template_data": [{"id": "ID", "folder": ".", "type": "terraform_v0.12"}]}`)
+					case "/v1/workspaces/testString/templates/values":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"template_data": [{"id": "ID", "folder": ".", "type": "terraform_v0.12", "values_url": "ValuesURL", "env_values": [{"name": "TF_LOG", "value": "DEBUG"}, {"name": "API_KEY", "value": "xyz", "secure": true}], "variablestore": [{"name": "region", "value": "us-south", "type": "string"}, {"name": "api_key", "value": "secret", "secure": true}]}]}`)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke ExportWorkspace successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+				Expect(schematicsService).ToNot(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.ExportWorkspace(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				exportWorkspaceOptionsModel := schematicsService.NewExportWorkspaceOptions("testString")
+				result, response, operationErr = schematicsService.ExportWorkspace(exportWorkspaceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result).ToNot(BeNil())
+
+				Expect(*result.Name).To(Equal("Name"))
+				Expect(*result.Location).To(Equal("us-south"))
+				Expect(result.Tags).To(Equal([]string{"env:dev"}))
+				Expect(result.WorkspaceStatus).To(BeNil())
+				Expect(*result.TemplateRepo.URL).To(Equal("URL"))
+				Expect(result.TemplateData).To(HaveLen(1))
+				Expect(result.TemplateData[0].Variablestore).To(HaveLen(2))
+				Expect(*result.TemplateData[0].Variablestore[0].Value).To(Equal("us-south"))
+				Expect(*result.TemplateData[0].Variablestore[1].Value).To(Equal(schematicsv1.DefaultSecurePlaceholder))
+				Expect(result.TemplateData[0].EnvValues[1]).To(HaveKeyWithValue("value", schematicsv1.DefaultSecurePlaceholder))
+			})
+			It(`Invoke ExportWorkspace with a custom placeholder`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				exportWorkspaceOptionsModel := schematicsService.NewExportWorkspaceOptions("testString")
+				exportWorkspaceOptionsModel.SetSecurePlaceholder("TODO")
+				result, _, operationErr := schematicsService.ExportWorkspace(exportWorkspaceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(*result.TemplateData[0].Variablestore[1].Value).To(Equal("TODO"))
+			})
+			It(`Invoke ExportWorkspace with error: Operation request error`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				result, response, operationErr := schematicsService.ExportWorkspace(schematicsService.NewExportWorkspaceOptions("missing"))
+				Expect(operationErr).ToNot(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(response.StatusCode).To(Equal(404))
+				Expect(result).To(BeNil())
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+	Describe(`ExportAction(exportActionOptions *ExportActionOptions)`, func() {
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.URL.EscapedPath()).To(Equal("/v2/actions/testString"))
+					Expect(req.Method).To(Equal("GET"))
+					res.Header().Set("Content-type", "application/json")
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "testString", "crn": "Crn", "account": "Account", "name": "Name", "location": "us_south", "source_type": "GitHub", "source": {"source_type": "git", "git": {"git_repo_url": "https://github.com/org/repo", "git_token": "token"}}, "command_parameter": "site.yml", "user_state": {"state": "live", "set_by": "SetBy"}, "state": {"status_code": "normal"}, "playbook_names": ["site.yml"], "sys_lock": {"sys_locked": false}, "credentials": [{"name": "ssh_key", "value": "key", "metadata": {"secure": true}}], "inputs": [{"name": "count", "value": "3"}]}`)
+				}))
+			})
+			It(`Invoke ExportAction successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				result, response, operationErr := schematicsService.ExportAction(schematicsService.NewExportActionOptions("testString"))
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(*result.Name).To(Equal("Name"))
+				Expect(result.State).To(BeNil())
+				Expect(result.SysLock).To(BeNil())
+				Expect(*result.UserState.State).To(Equal("live"))
+				Expect(result.UserState.SetBy).To(BeNil())
+				Expect(*result.Source.Git.GitToken).To(Equal(schematicsv1.DefaultSecurePlaceholder))
+				Expect(*result.Credentials[0].Value).To(Equal(schematicsv1.DefaultSecurePlaceholder))
+				Expect(*result.Inputs[0].Value).To(Equal("3"))
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+	Describe(`Spec files`, func() {
+		It(`Round trip a workspace spec`, func() {
+			options := &schematicsv1.CreateWorkspaceOptions{
+				Name:         core.StringPtr("Name"),
+				Tags:         []string{"env:dev"},
+				XGithubToken: core.StringPtr("token"),
+				Headers:      map[string]string{"x-custom-header": "x-custom-value"},
+			}
+			var buf bytes.Buffer
+			Expect(schematicsv1.WriteWorkspaceSpec(&buf, options)).To(Succeed())
+			Expect(buf.String()).ToNot(ContainSubstring("token"))
+			Expect(buf.String()).ToNot(ContainSubstring("Headers"))
+
+			read, err := schematicsv1.ReadWorkspaceSpec(&buf)
+			Expect(err).To(BeNil())
+			Expect(*read.Name).To(Equal("Name"))
+			Expect(read.Tags).To(Equal([]string{"env:dev"}))
+			Expect(read.XGithubToken).To(BeNil())
+		})
+		It(`Round trip an action spec`, func() {
+			options := &schematicsv1.CreateActionOptions{
+				Name:             core.StringPtr("Name"),
+				CommandParameter: core.StringPtr("site.yml"),
+			}
+			var buf bytes.Buffer
+			Expect(schematicsv1.WriteActionSpec(&buf, options)).To(Succeed())
+
+			read, err := schematicsv1.ReadActionSpec(&buf)
+			Expect(err).To(BeNil())
+			Expect(*read.CommandParameter).To(Equal("site.yml"))
+		})
+		It(`Reject invalid spec files`, func() {
+			read, err := schematicsv1.ReadWorkspaceSpec(bytes.NewBufferString(`} this is not valid json {`))
+			Expect(err).ToNot(BeNil())
+			Expect(read).To(BeNil())
+			Expect(schematicsv1.WriteWorkspaceSpec(&bytes.Buffer{}, nil)).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,676 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+	"time"
+)
+
+// CheckTerraformCommandsAgainstState checks that the state commands of a sequence apply to the resources of a state.
+// The commands are replayed in order on the resource addresses of the state: an imported address must not be managed
+// yet, the source of 'state mv' and the addresses of 'state rm', 'taint' and 'untaint' must be managed, and the
+// destination of 'state mv' must not be. Addresses are compared in the canonical form of ParseTerraformAddress. A
+// resource address without instance key refers to every instance of the resource and a module address to every
+// resource of the module. All problems found are reported in a single error.
+func CheckTerraformCommandsAgainstState(state *TerraformState, commands []TerraformCommand) error {
+	addresses := map[string]*TerraformAddress{}
+	for _, address := range state.Addresses() {
+		if parsed, err := ParseTerraformAddress(address); err == nil {
+			addresses[parsed.String()] = parsed
+		}
+	}
+
+	var problems []string
+	for i, command := range commands {
+		if command.Command == nil {
+			continue
+		}
+		name := commandName(command, i)
+		var params []*TerraformAddress
+		if command.CommandParams != nil {
+			for _, param := range splitTerraformCommandParams(*command.CommandParams) {
+				if strings.HasPrefix(param, "-") {
+					continue
+				}
+				if *command.Command == TerraformCommand_Command_Import && len(params) == 1 {
+					// The second parameter of an import is the ID of the cloud resource.
+					break
+				}
+				address, err := parseConcreteTerraformAddress(param)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s", name, err.Error()))
+					params = nil
+					break
+				}
+				params = append(params, address)
+			}
+		}
+
+		switch *command.Command {
+		case TerraformCommand_Command_Import:
+			if len(params) > 0 && len(matchingStateAddresses(addresses, params[0])) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s is already managed", name, params[0]))
+			} else if len(params) > 0 {
+				addresses[params[0].String()] = params[0]
+			}
+		case TerraformCommand_Command_StateMv:
+			if len(params) != 2 {
+				continue
+			}
+			source, destination := params[0], params[1]
+			moved := matchingStateAddresses(addresses, source)
+			if len(moved) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s is not in the state", name, source))
+				continue
+			}
+			if len(matchingStateAddresses(addresses, destination)) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s is already in the state", name, destination))
+				continue
+			}
+			for _, address := range moved {
+				delete(addresses, address)
+				renamed, err := ParseTerraformAddress(destination.String() + strings.TrimPrefix(address, source.String()))
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s", name, err.Error()))
+					continue
+				}
+				addresses[renamed.String()] = renamed
+			}
+		case TerraformCommand_Command_StateRm:
+			for _, param := range params {
+				removed := matchingStateAddresses(addresses, param)
+				if len(removed) == 0 {
+					problems = append(problems, fmt.Sprintf("%s: %s is not in the state", name, param))
+				}
+				for _, address := range removed {
+					delete(addresses, address)
+				}
+			}
+		case TerraformCommand_Command_Taint, TerraformCommand_Command_Untaint:
+			if len(params) > 0 && len(matchingStateAddresses(addresses, params[0])) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s is not in the state", name, params[0]))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("terraform commands do not match the state: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// matchingStateAddresses returns the addresses that reference contains: the address itself, its instances or the
+// members of the module.
+func matchingStateAddresses(addresses map[string]*TerraformAddress, reference *TerraformAddress) (matches []string) {
+	for address, parsed := range addresses {
+		if reference.Contains(parsed) {
+			matches = append(matches, address)
+		}
+	}
+	return
+}
+
+// RunStateCommandsOptions : The RunStateCommands options.
+type RunStateCommandsOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// List of commands.
+	Commands []TerraformCommand `json:"commands" validate:"required,min=1"`
+
+	// The ID of the template whose state the commands are checked against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// Command name.
+	OperationName *string `json:"operation_name,omitempty"`
+
+	// Command description.
+	Description *string `json:"description,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRunStateCommandsOptions : Instantiate RunStateCommandsOptions
+func (*SchematicsV1) NewRunStateCommandsOptions(wID string, refreshToken string, commands []TerraformCommand) *RunStateCommandsOptions {
+	return &RunStateCommandsOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Commands:     commands,
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *RunStateCommandsOptions) SetWID(wID string) *RunStateCommandsOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *RunStateCommandsOptions) SetRefreshToken(refreshToken string) *RunStateCommandsOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetCommands : Allow user to set Commands
+func (options *RunStateCommandsOptions) SetCommands(commands []TerraformCommand) *RunStateCommandsOptions {
+	options.Commands = commands
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *RunStateCommandsOptions) SetTID(tID string) *RunStateCommandsOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetOperationName : Allow user to set OperationName
+func (options *RunStateCommandsOptions) SetOperationName(operationName string) *RunStateCommandsOptions {
+	options.OperationName = core.StringPtr(operationName)
+	return options
+}
+
+// SetDescription : Allow user to set Description
+func (options *RunStateCommandsOptions) SetDescription(description string) *RunStateCommandsOptions {
+	options.Description = core.StringPtr(description)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *RunStateCommandsOptions) SetPollInterval(pollInterval time.Duration) *RunStateCommandsOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *RunStateCommandsOptions) SetTimeout(timeout time.Duration) *RunStateCommandsOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RunStateCommandsOptions) SetHeaders(param map[string]string) *RunStateCommandsOptions {
+	options.Headers = param
+	return options
+}
+
+// RunStateCommands : Run state commands checked against the workspace state
+// Validate the commands, check them against the decoded Terraform state of the workspace template with
+// CheckTerraformCommandsAgainstState and run them with RunTerraformCommands, which waits for the activity and reports
+// the status of each command.
+func (schematics *SchematicsV1) RunStateCommands(runStateCommandsOptions *RunStateCommandsOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(runStateCommandsOptions, "runStateCommandsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(runStateCommandsOptions, "runStateCommandsOptions")
+	if err != nil {
+		return
+	}
+	commands, err := ValidateTerraformCommands(runStateCommandsOptions.Commands)
+	if err != nil {
+		return
+	}
+
+	state, response, err := schematics.getWorkspaceTerraformState(runStateCommandsOptions.WID, runStateCommandsOptions.TID, runStateCommandsOptions.Headers)
+	if err != nil {
+		return
+	}
+	err = CheckTerraformCommandsAgainstState(state, commands)
+	if err != nil {
+		return
+	}
+
+	return schematics.RunTerraformCommands(&RunTerraformCommandsOptions{
+		WID:           runStateCommandsOptions.WID,
+		RefreshToken:  runStateCommandsOptions.RefreshToken,
+		Commands:      commands,
+		OperationName: runStateCommandsOptions.OperationName,
+		Description:   runStateCommandsOptions.Description,
+		PollInterval:  runStateCommandsOptions.PollInterval,
+		Timeout:       runStateCommandsOptions.Timeout,
+		Headers:       runStateCommandsOptions.Headers,
+	})
+}
+
+// ImportResourceOptions : The ImportResource options.
+type ImportResourceOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The Terraform address to import the resource at.
+	Address *string `json:"address" validate:"required,ne="`
+
+	// The ID of the cloud resource.
+	ResourceID *string `json:"resource_id" validate:"required,ne="`
+
+	// The ID of the template whose state the command is checked against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewImportResourceOptions : Instantiate ImportResourceOptions
+func (*SchematicsV1) NewImportResourceOptions(wID string, refreshToken string, address string, resourceID string) *ImportResourceOptions {
+	return &ImportResourceOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Address:      core.StringPtr(address),
+		ResourceID:   core.StringPtr(resourceID),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *ImportResourceOptions) SetWID(wID string) *ImportResourceOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *ImportResourceOptions) SetRefreshToken(refreshToken string) *ImportResourceOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetAddress : Allow user to set Address
+func (options *ImportResourceOptions) SetAddress(address string) *ImportResourceOptions {
+	options.Address = core.StringPtr(address)
+	return options
+}
+
+// SetResourceID : Allow user to set ResourceID
+func (options *ImportResourceOptions) SetResourceID(resourceID string) *ImportResourceOptions {
+	options.ResourceID = core.StringPtr(resourceID)
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *ImportResourceOptions) SetTID(tID string) *ImportResourceOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *ImportResourceOptions) SetPollInterval(pollInterval time.Duration) *ImportResourceOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *ImportResourceOptions) SetTimeout(timeout time.Duration) *ImportResourceOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ImportResourceOptions) SetHeaders(param map[string]string) *ImportResourceOptions {
+	options.Headers = param
+	return options
+}
+
+// ImportResource : Import an existing cloud resource into the workspace state
+// Import the cloud resource with ID ResourceID at Address, after checking that Address is not managed yet.
+func (schematics *SchematicsV1) ImportResource(importResourceOptions *ImportResourceOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(importResourceOptions, "importResourceOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(importResourceOptions, "importResourceOptions")
+	if err != nil {
+		return
+	}
+
+	return schematics.RunStateCommands(&RunStateCommandsOptions{
+		WID:          importResourceOptions.WID,
+		RefreshToken: importResourceOptions.RefreshToken,
+		Commands:     new(TerraformCommandsBuilder).Import("import", *importResourceOptions.Address, *importResourceOptions.ResourceID).commands,
+		TID:          importResourceOptions.TID,
+		PollInterval: importResourceOptions.PollInterval,
+		Timeout:      importResourceOptions.Timeout,
+		Headers:      importResourceOptions.Headers,
+	})
+}
+
+// MoveResourceOptions : The MoveResource options.
+type MoveResourceOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The address of the resource or module to move.
+	Source *string `json:"source" validate:"required,ne="`
+
+	// The address to move the resource or module to.
+	Destination *string `json:"destination" validate:"required,ne="`
+
+	// The ID of the template whose state the command is checked against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewMoveResourceOptions : Instantiate MoveResourceOptions
+func (*SchematicsV1) NewMoveResourceOptions(wID string, refreshToken string, source string, destination string) *MoveResourceOptions {
+	return &MoveResourceOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Source:       core.StringPtr(source),
+		Destination:  core.StringPtr(destination),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *MoveResourceOptions) SetWID(wID string) *MoveResourceOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *MoveResourceOptions) SetRefreshToken(refreshToken string) *MoveResourceOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetSource : Allow user to set Source
+func (options *MoveResourceOptions) SetSource(source string) *MoveResourceOptions {
+	options.Source = core.StringPtr(source)
+	return options
+}
+
+// SetDestination : Allow user to set Destination
+func (options *MoveResourceOptions) SetDestination(destination string) *MoveResourceOptions {
+	options.Destination = core.StringPtr(destination)
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *MoveResourceOptions) SetTID(tID string) *MoveResourceOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *MoveResourceOptions) SetPollInterval(pollInterval time.Duration) *MoveResourceOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *MoveResourceOptions) SetTimeout(timeout time.Duration) *MoveResourceOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *MoveResourceOptions) SetHeaders(param map[string]string) *MoveResourceOptions {
+	options.Headers = param
+	return options
+}
+
+// MoveResource : Move a resource or module in the workspace state
+// Move Source to Destination, after checking that Source is managed and Destination is not.
+func (schematics *SchematicsV1) MoveResource(moveResourceOptions *MoveResourceOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(moveResourceOptions, "moveResourceOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(moveResourceOptions, "moveResourceOptions")
+	if err != nil {
+		return
+	}
+
+	return schematics.RunStateCommands(&RunStateCommandsOptions{
+		WID:          moveResourceOptions.WID,
+		RefreshToken: moveResourceOptions.RefreshToken,
+		Commands:     new(TerraformCommandsBuilder).StateMv("move", *moveResourceOptions.Source, *moveResourceOptions.Destination).commands,
+		TID:          moveResourceOptions.TID,
+		PollInterval: moveResourceOptions.PollInterval,
+		Timeout:      moveResourceOptions.Timeout,
+		Headers:      moveResourceOptions.Headers,
+	})
+}
+
+// RemoveFromStateOptions : The RemoveFromState options.
+type RemoveFromStateOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The addresses of the resources or modules to remove.
+	Addresses []string `json:"addresses" validate:"required,min=1"`
+
+	// The ID of the template whose state the command is checked against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRemoveFromStateOptions : Instantiate RemoveFromStateOptions
+func (*SchematicsV1) NewRemoveFromStateOptions(wID string, refreshToken string, addresses []string) *RemoveFromStateOptions {
+	return &RemoveFromStateOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Addresses:    addresses,
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *RemoveFromStateOptions) SetWID(wID string) *RemoveFromStateOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *RemoveFromStateOptions) SetRefreshToken(refreshToken string) *RemoveFromStateOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetAddresses : Allow user to set Addresses
+func (options *RemoveFromStateOptions) SetAddresses(addresses []string) *RemoveFromStateOptions {
+	options.Addresses = addresses
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *RemoveFromStateOptions) SetTID(tID string) *RemoveFromStateOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *RemoveFromStateOptions) SetPollInterval(pollInterval time.Duration) *RemoveFromStateOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *RemoveFromStateOptions) SetTimeout(timeout time.Duration) *RemoveFromStateOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RemoveFromStateOptions) SetHeaders(param map[string]string) *RemoveFromStateOptions {
+	options.Headers = param
+	return options
+}
+
+// RemoveFromState : Remove resources from the workspace state
+// Remove Addresses from the state without destroying the cloud resources, after checking that they are managed.
+func (schematics *SchematicsV1) RemoveFromState(removeFromStateOptions *RemoveFromStateOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(removeFromStateOptions, "removeFromStateOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(removeFromStateOptions, "removeFromStateOptions")
+	if err != nil {
+		return
+	}
+
+	return schematics.RunStateCommands(&RunStateCommandsOptions{
+		WID:          removeFromStateOptions.WID,
+		RefreshToken: removeFromStateOptions.RefreshToken,
+		Commands:     new(TerraformCommandsBuilder).StateRm("remove", removeFromStateOptions.Addresses...).commands,
+		TID:          removeFromStateOptions.TID,
+		PollInterval: removeFromStateOptions.PollInterval,
+		Timeout:      removeFromStateOptions.Timeout,
+		Headers:      removeFromStateOptions.Headers,
+	})
+}
+
+// TaintOptions : The Taint options.
+type TaintOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The address of the resource to taint.
+	Address *string `json:"address" validate:"required,ne="`
+
+	// The ID of the template whose state the command is checked against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// The time waited between two status requests. Defaults to DefaultPollInterval.
+	PollInterval time.Duration `json:"-"`
+
+	// The maximum time spent waiting. Defaults to DefaultWaitTimeout.
+	Timeout time.Duration `json:"-"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewTaintOptions : Instantiate TaintOptions
+func (*SchematicsV1) NewTaintOptions(wID string, refreshToken string, address string) *TaintOptions {
+	return &TaintOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Address:      core.StringPtr(address),
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *TaintOptions) SetWID(wID string) *TaintOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *TaintOptions) SetRefreshToken(refreshToken string) *TaintOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetAddress : Allow user to set Address
+func (options *TaintOptions) SetAddress(address string) *TaintOptions {
+	options.Address = core.StringPtr(address)
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *TaintOptions) SetTID(tID string) *TaintOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetPollInterval : Allow user to set PollInterval
+func (options *TaintOptions) SetPollInterval(pollInterval time.Duration) *TaintOptions {
+	options.PollInterval = pollInterval
+	return options
+}
+
+// SetTimeout : Allow user to set Timeout
+func (options *TaintOptions) SetTimeout(timeout time.Duration) *TaintOptions {
+	options.Timeout = timeout
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *TaintOptions) SetHeaders(param map[string]string) *TaintOptions {
+	options.Headers = param
+	return options
+}
+
+// Taint : Mark a resource for replacement
+// Taint the resource at Address so that the next apply replaces it, after checking that it is managed.
+func (schematics *SchematicsV1) Taint(taintOptions *TaintOptions) (result *TerraformCommandsResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(taintOptions, "taintOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(taintOptions, "taintOptions")
+	if err != nil {
+		return
+	}
+
+	return schematics.RunStateCommands(&RunStateCommandsOptions{
+		WID:          taintOptions.WID,
+		RefreshToken: taintOptions.RefreshToken,
+		Commands:     new(TerraformCommandsBuilder).Taint("taint", *taintOptions.Address).commands,
+		TID:          taintOptions.TID,
+		PollInterval: taintOptions.PollInterval,
+		Timeout:      taintOptions.Timeout,
+		Headers:      taintOptions.Headers,
+	})
+}
@@ -0,0 +1,197 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe(`Workspace state surgery`, func() {
+	var testServer *httptest.Server
+	stateJSON := `{"version": 4, "resources": [
+		{"mode": "managed", "type": "ibm_is_vpc", "name": "vpc", "instances": [{}]},
+		{"module": "module.network", "mode": "managed", "type": "ibm_is_subnet", "name": "subnet", "instances": [{"index_key": 0}, {"index_key": 1}]}
+	]}`
+
+	Describe(`CheckTerraformCommandsAgainstState(state *TerraformState, commands []TerraformCommand)`, func() {
+		state, _ := schematicsv1.DecodeTerraformState([]byte(stateJSON))
+		It(`Accept commands that apply to the state`, func() {
+			commands, err := new(schematicsv1.TerraformCommandsBuilder).
+				StateMv("move", "module.network", "module.vpc").
+				Taint("taint", "module.vpc.ibm_is_subnet.subnet[1]").
+				Import("import", "module.network.ibm_is_subnet.subnet", "0717-abc").
+				StateRm("remove", "ibm_is_vpc.vpc").
+				Build()
+			Expect(err).To(BeNil())
+			Expect(schematicsv1.CheckTerraformCommandsAgainstState(state, commands)).To(Succeed())
+		})
+		It(`Compare addresses in canonical form`, func() {
+			state, _ := schematicsv1.DecodeTerraformState([]byte(`{"version": 4, "resources": [
+				{"module": "module.a[\"k\"]", "mode": "managed", "type": "ibm_is_vpc", "name": "x", "instances": [{"index_key": "k"}]}
+			]}`))
+			commands, err := new(schematicsv1.TerraformCommandsBuilder).
+				Taint("taint", `module.a['k'].ibm_is_vpc.x['k']`).
+				StateMv("move", `module.a["k"].ibm_is_vpc.x[ "k" ]`, `ibm_is_vpc.y`).
+				Untaint("untaint", `ibm_is_vpc.y`).
+				Build()
+			Expect(err).To(BeNil())
+			Expect(schematicsv1.CheckTerraformCommandsAgainstState(state, commands)).To(Succeed())
+
+			commands, err = new(schematicsv1.TerraformCommandsBuilder).
+				Taint("taint", `module.a['other'].ibm_is_vpc.x`).
+				Build()
+			Expect(err).To(BeNil())
+			err = schematicsv1.CheckTerraformCommandsAgainstState(state, commands)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`taint: module.a["other"].ibm_is_vpc.x is not in the state`))
+		})
+		It(`Reject commands that do not apply to the state`, func() {
+			commands, err := new(schematicsv1.TerraformCommandsBuilder).
+				Import("import", "ibm_is_vpc.vpc", "r006-vpc").
+				StateMv("move", "ibm_is_vpc.missing", "ibm_is_vpc.other").
+				StateMv("overwrite", "ibm_is_vpc.vpc", "module.network.ibm_is_subnet.subnet").
+				StateRm("remove", "module.network.ibm_is_subnet.subnet[0]").
+				Taint("taint", "module.network.ibm_is_subnet.subnet[0]").
+				Build()
+			Expect(err).To(BeNil())
+			err = schematicsv1.CheckTerraformCommandsAgainstState(state, commands)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("import: ibm_is_vpc.vpc is already managed"))
+			Expect(err.Error()).To(ContainSubstring("move: ibm_is_vpc.missing is not in the state"))
+			Expect(err.Error()).To(ContainSubstring("overwrite: module.network.ibm_is_subnet.subnet is already in the state"))
+			Expect(err.Error()).To(ContainSubstring("taint: module.network.ibm_is_subnet.subnet[0] is not in the state"))
+			Expect(err.Error()).ToNot(ContainSubstring("remove:"))
+		})
+	})
+	Describe(`State surgery helpers`, func() {
+		var submitted []schematicsv1.TerraformCommand
+		Context(`Using mock server endpoint`, func() {
+			BeforeEach(func() {
+				submitted = nil
+				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					res.Header().Set("Content-type", "application/json")
+					path := req.URL.EscapedPath()
+					switch {
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"id": "ws-1", "template_data": [{"id": "t-1"}]}`)
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1/runtime_data/t-1/state_store":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", stateJSON)
+					case req.Method == "PUT" && path == "/v1/workspaces/ws-1/commands":
+						body, _ := ioutil.ReadAll(req.Body)
+						var options schematicsv1.RunWorkspaceCommandsOptions
+						Expect(json.Unmarshal(body, &options)).To(Succeed())
+						submitted = options.Commands
+						res.WriteHeader(202)
+						fmt.Fprintf(res, "%s", `{"activityid": "cmd-1"}`)
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1/actions/cmd-1":
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", `{"action_id": "cmd-1", "status": "COMPLETED", "templates": [{"template_id": "t-1"}]}`)
+					case req.Method == "GET" && path == "/v1/workspaces/ws-1/runtime_data/t-1/log_store/actions/cmd-1":
+						log, _ := json.Marshal("Running terraform import ibm_is_vpc.imported r006-abc\nImport successful!")
+						res.WriteHeader(200)
+						fmt.Fprintf(res, "%s", log)
+					default:
+						res.WriteHeader(404)
+					}
+				}))
+			})
+			It(`Invoke ImportResource successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				// Invoke operation with nil options model (negative test)
+				result, response, operationErr := schematicsService.ImportResource(nil)
+				Expect(operationErr).NotTo(BeNil())
+				Expect(response).To(BeNil())
+				Expect(result).To(BeNil())
+
+				importResourceOptionsModel := schematicsService.NewImportResourceOptions("ws-1", "testString", "ibm_is_vpc.imported", "r006-abc").
+					SetPollInterval(time.Millisecond)
+				result, response, operationErr = schematicsService.ImportResource(importResourceOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(*result.Status).To(Equal("COMPLETED"))
+				Expect(result.Commands).To(HaveLen(1))
+				Expect(*result.Commands[0].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_Completed))
+				Expect(submitted).To(HaveLen(1))
+				Expect(*submitted[0].Command).To(Equal("import"))
+				Expect(*submitted[0].CommandParams).To(Equal("ibm_is_vpc.imported r006-abc"))
+			})
+			It(`Invoke MoveResource, RemoveFromState and Taint with addresses missing from the state`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				_, _, operationErr := schematicsService.MoveResource(schematicsService.NewMoveResourceOptions("ws-1", "testString", "ibm_is_vpc.missing", "ibm_is_vpc.vpc2").SetTID("t-1"))
+				Expect(operationErr).ToNot(BeNil())
+				Expect(operationErr.Error()).To(ContainSubstring("ibm_is_vpc.missing is not in the state"))
+
+				_, _, operationErr = schematicsService.RemoveFromState(schematicsService.NewRemoveFromStateOptions("ws-1", "testString", []string{"ibm_is_vpc.missing"}))
+				Expect(operationErr).ToNot(BeNil())
+
+				_, _, operationErr = schematicsService.Taint(schematicsService.NewTaintOptions("ws-1", "testString", "ibm_is_vpc.missing"))
+				Expect(operationErr).ToNot(BeNil())
+
+				// Nothing was submitted
+				Expect(submitted).To(BeNil())
+			})
+			It(`Invoke RunStateCommands successfully`, func() {
+				schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+					URL:           testServer.URL,
+					Authenticator: &core.NoAuthAuthenticator{},
+				})
+				Expect(serviceErr).To(BeNil())
+
+				commands, err := schematicsService.NewTerraformCommandsBuilder().
+					StateMv("move", "module.network", "module.vpc").
+					Taint("taint", "module.vpc.ibm_is_subnet.subnet[0]").DependsOn("move").
+					Build()
+				Expect(err).To(BeNil())
+				runStateCommandsOptionsModel := schematicsService.NewRunStateCommandsOptions("ws-1", "testString", commands).
+					SetTID("t-1").
+					SetPollInterval(time.Millisecond)
+				result, response, operationErr := schematicsService.RunStateCommands(runStateCommandsOptionsModel)
+				Expect(operationErr).To(BeNil())
+				Expect(response).ToNot(BeNil())
+				Expect(result.Commands).To(HaveLen(2))
+				Expect(*result.Commands[0].Status).To(Equal(schematicsv1.TerraformCommandResult_Status_NotRun))
+				Expect(submitted).To(HaveLen(2))
+			})
+			AfterEach(func() {
+				testServer.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,255 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1
+
+import (
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"strings"
+)
+
+// ResolveWorkspaceTargetsOptions : The ResolveWorkspaceTargets options.
+type ResolveWorkspaceTargetsOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The target addresses or address patterns.
+	Targets []string `json:"targets" validate:"required,min=1"`
+
+	// The ID of the template whose state the targets are resolved against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// True to also require the targets without wildcard to select a resource of the state.
+	RequireExisting *bool `json:"require_existing,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewResolveWorkspaceTargetsOptions : Instantiate ResolveWorkspaceTargetsOptions
+func (*SchematicsV1) NewResolveWorkspaceTargetsOptions(wID string, targets []string) *ResolveWorkspaceTargetsOptions {
+	return &ResolveWorkspaceTargetsOptions{
+		WID:     core.StringPtr(wID),
+		Targets: targets,
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *ResolveWorkspaceTargetsOptions) SetWID(wID string) *ResolveWorkspaceTargetsOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetTargets : Allow user to set Targets
+func (options *ResolveWorkspaceTargetsOptions) SetTargets(targets []string) *ResolveWorkspaceTargetsOptions {
+	options.Targets = targets
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *ResolveWorkspaceTargetsOptions) SetTID(tID string) *ResolveWorkspaceTargetsOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetRequireExisting : Allow user to set RequireExisting
+func (options *ResolveWorkspaceTargetsOptions) SetRequireExisting(requireExisting bool) *ResolveWorkspaceTargetsOptions {
+	options.RequireExisting = core.BoolPtr(requireExisting)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ResolveWorkspaceTargetsOptions) SetHeaders(param map[string]string) *ResolveWorkspaceTargetsOptions {
+	options.Headers = param
+	return options
+}
+
+// ResolveWorkspaceTargets : Resolve target addresses against the workspace state
+// Parse the targets and resolve them with ExpandTerraformAddresses against the decoded Terraform state of the
+// workspace template, or with ExpandExistingTerraformAddresses when RequireExisting is set. An error is returned when a
+// target is invalid or a target that must select a resource does not.
+func (schematics *SchematicsV1) ResolveWorkspaceTargets(resolveWorkspaceTargetsOptions *ResolveWorkspaceTargetsOptions) (result []string, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(resolveWorkspaceTargetsOptions, "resolveWorkspaceTargetsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(resolveWorkspaceTargetsOptions, "resolveWorkspaceTargetsOptions")
+	if err != nil {
+		return
+	}
+
+	state, response, err := schematics.getWorkspaceTerraformState(resolveWorkspaceTargetsOptions.WID, resolveWorkspaceTargetsOptions.TID, resolveWorkspaceTargetsOptions.Headers)
+	if err != nil {
+		return
+	}
+	if resolveWorkspaceTargetsOptions.RequireExisting != nil && *resolveWorkspaceTargetsOptions.RequireExisting {
+		result, err = ExpandExistingTerraformAddresses(resolveWorkspaceTargetsOptions.Targets, state.Addresses())
+	} else {
+		result, err = ExpandTerraformAddresses(resolveWorkspaceTargetsOptions.Targets, state.Addresses())
+	}
+	return
+}
+
+// TargetedWorkspaceCommandOptions : The ApplyWorkspaceTargets and DestroyWorkspaceTargets options.
+type TargetedWorkspaceCommandOptions struct {
+	// The ID of the workspace.
+	WID *string `json:"w_id" validate:"required,ne="`
+
+	// The IAM refresh token associated with the IBM Cloud account.
+	RefreshToken *string `json:"refresh_token" validate:"required"`
+
+	// The target addresses or address patterns. At least one target is required so that a mistake can not turn a
+	// targeted command into a command on the whole workspace.
+	Targets []string `json:"targets" validate:"required,min=1"`
+
+	// Variable values in the form NAME=VALUE.
+	TfVars []string `json:"tf_vars,omitempty"`
+
+	// The ID of the template whose state the targets are resolved against. Defaults to the first template of the
+	// workspace.
+	TID *string `json:"t_id,omitempty"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewTargetedWorkspaceCommandOptions : Instantiate TargetedWorkspaceCommandOptions
+func (*SchematicsV1) NewTargetedWorkspaceCommandOptions(wID string, refreshToken string, targets []string) *TargetedWorkspaceCommandOptions {
+	return &TargetedWorkspaceCommandOptions{
+		WID:          core.StringPtr(wID),
+		RefreshToken: core.StringPtr(refreshToken),
+		Targets:      targets,
+	}
+}
+
+// SetWID : Allow user to set WID
+func (options *TargetedWorkspaceCommandOptions) SetWID(wID string) *TargetedWorkspaceCommandOptions {
+	options.WID = core.StringPtr(wID)
+	return options
+}
+
+// SetRefreshToken : Allow user to set RefreshToken
+func (options *TargetedWorkspaceCommandOptions) SetRefreshToken(refreshToken string) *TargetedWorkspaceCommandOptions {
+	options.RefreshToken = core.StringPtr(refreshToken)
+	return options
+}
+
+// SetTargets : Allow user to set Targets
+func (options *TargetedWorkspaceCommandOptions) SetTargets(targets []string) *TargetedWorkspaceCommandOptions {
+	options.Targets = targets
+	return options
+}
+
+// SetTfVars : Allow user to set TfVars
+func (options *TargetedWorkspaceCommandOptions) SetTfVars(tfVars []string) *TargetedWorkspaceCommandOptions {
+	options.TfVars = tfVars
+	return options
+}
+
+// SetTID : Allow user to set TID
+func (options *TargetedWorkspaceCommandOptions) SetTID(tID string) *TargetedWorkspaceCommandOptions {
+	options.TID = core.StringPtr(tID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *TargetedWorkspaceCommandOptions) SetHeaders(param map[string]string) *TargetedWorkspaceCommandOptions {
+	options.Headers = param
+	return options
+}
+
+// ApplyWorkspaceTargets : Run a targeted workspace 'apply' activity
+// Resolve the targets with ResolveWorkspaceTargets and run ApplyWorkspaceCommand on the resolved addresses. Targets
+// without wildcard may name resources that the apply creates. Nothing is submitted when a target or variable is
+// invalid.
+func (schematics *SchematicsV1) ApplyWorkspaceTargets(targetedWorkspaceCommandOptions *TargetedWorkspaceCommandOptions) (result *WorkspaceActivityApplyResult, response *core.DetailedResponse, err error) {
+	actionOptions, response, err := schematics.resolveTargetedActionOptions(targetedWorkspaceCommandOptions, false)
+	if err != nil {
+		return
+	}
+	return schematics.ApplyWorkspaceCommand(&ApplyWorkspaceCommandOptions{
+		WID:           targetedWorkspaceCommandOptions.WID,
+		RefreshToken:  targetedWorkspaceCommandOptions.RefreshToken,
+		ActionOptions: actionOptions,
+		Headers:       targetedWorkspaceCommandOptions.Headers,
+	})
+}
+
+// DestroyWorkspaceTargets : Run a targeted workspace 'destroy' activity
+// Resolve the targets with ResolveWorkspaceTargets and run DestroyWorkspaceCommand on the resolved addresses. Every
+// target must select a resource of the state. Nothing is submitted when a target or variable is invalid.
+func (schematics *SchematicsV1) DestroyWorkspaceTargets(targetedWorkspaceCommandOptions *TargetedWorkspaceCommandOptions) (result *WorkspaceActivityDestroyResult, response *core.DetailedResponse, err error) {
+	actionOptions, response, err := schematics.resolveTargetedActionOptions(targetedWorkspaceCommandOptions, true)
+	if err != nil {
+		return
+	}
+	return schematics.DestroyWorkspaceCommand(&DestroyWorkspaceCommandOptions{
+		WID:           targetedWorkspaceCommandOptions.WID,
+		RefreshToken:  targetedWorkspaceCommandOptions.RefreshToken,
+		ActionOptions: actionOptions,
+		Headers:       targetedWorkspaceCommandOptions.Headers,
+	})
+}
+
+func (schematics *SchematicsV1) resolveTargetedActionOptions(targetedWorkspaceCommandOptions *TargetedWorkspaceCommandOptions, requireExisting bool) (result *WorkspaceActivityOptionsTemplate, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(targetedWorkspaceCommandOptions, "targetedWorkspaceCommandOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(targetedWorkspaceCommandOptions, "targetedWorkspaceCommandOptions")
+	if err != nil {
+		return
+	}
+	err = ValidateTerraformVars(targetedWorkspaceCommandOptions.TfVars)
+	if err != nil {
+		return
+	}
+
+	targets, response, err := schematics.ResolveWorkspaceTargets(&ResolveWorkspaceTargetsOptions{
+		WID:             targetedWorkspaceCommandOptions.WID,
+		Targets:         targetedWorkspaceCommandOptions.Targets,
+		TID:             targetedWorkspaceCommandOptions.TID,
+		RequireExisting: core.BoolPtr(requireExisting),
+		Headers:         targetedWorkspaceCommandOptions.Headers,
+	})
+	if err != nil {
+		return
+	}
+	result = &WorkspaceActivityOptionsTemplate{
+		Target: targets,
+		TfVars: targetedWorkspaceCommandOptions.TfVars,
+	}
+	return
+}
+
+// ValidateTerraformVars checks that every variable value has the form NAME=VALUE with a valid variable name.
+func ValidateTerraformVars(tfVars []string) error {
+	var problems []string
+	for _, tfVar := range tfVars {
+		separator := strings.Index(tfVar, "=")
+		if separator < 0 {
+			problems = append(problems, fmt.Sprintf("%q is not of the form NAME=VALUE", tfVar))
+		} else if !terraformIdentifierRegexp.MatchString(tfVar[:separator]) {
+			problems = append(problems, fmt.Sprintf("invalid variable name %q", tfVar[:separator]))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid tf_vars: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schematicsv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/go-sdk-core/v4/core"
+	"github.com/Praveengostu/schematics-go-sdk/schematicsv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe(`Targeted workspace commands`, func() {
+	var testServer *httptest.Server
+	var submitted *schematicsv1.WorkspaceActivityOptionsTemplate
+	stateJSON := `{"version": 4, "resources": [
+		{"mode": "managed", "type": "ibm_is_vpc", "name": "vpc", "instances": [{}]},
+		{"module": "module.network", "mode": "managed", "type": "ibm_is_subnet", "name": "subnet", "instances": [{"index_key": 0}, {"index_key": 1}]}
+	]}`
+
+	Context(`Using mock server endpoint`, func() {
+		BeforeEach(func() {
+			submitted = nil
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				path := req.URL.EscapedPath()
+				switch {
+				case req.Method == "GET" && path == "/v1/workspaces/ws-1":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", `{"id": "ws-1", "template_data": [{"id": "t-1"}]}`)
+				case req.Method == "GET" && path == "/v1/workspaces/ws-1/runtime_data/t-1/state_store":
+					res.WriteHeader(200)
+					fmt.Fprintf(res, "%s", stateJSON)
+				case req.Method == "PUT" && (path == "/v1/workspaces/ws-1/apply" || path == "/v1/workspaces/ws-1/destroy"):
+					Expect(req.Header.Get("refresh_token")).To(Equal("testString"))
+					body, _ := ioutil.ReadAll(req.Body)
+					var options schematicsv1.ApplyWorkspaceCommandOptions
+					Expect(json.Unmarshal(body, &options)).To(Succeed())
+					submitted = options.ActionOptions
+					res.WriteHeader(202)
+					fmt.Fprintf(res, "%s", `{"activityid": "act-1"}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke ResolveWorkspaceTargets successfully`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			// Invoke operation with nil options model (negative test)
+			result, response, operationErr := schematicsService.ResolveWorkspaceTargets(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(result).To(BeNil())
+
+			result, response, operationErr = schematicsService.ResolveWorkspaceTargets(schematicsService.NewResolveWorkspaceTargetsOptions("ws-1", []string{"module.network.*"}).SetTID("t-1"))
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(result).To(Equal([]string{"module.network.ibm_is_subnet.subnet[0]", "module.network.ibm_is_subnet.subnet[1]"}))
+		})
+		It(`Invoke ApplyWorkspaceTargets and DestroyWorkspaceTargets successfully`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			applyResult, response, operationErr := schematicsService.ApplyWorkspaceTargets(nil)
+			Expect(operationErr).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(applyResult).To(BeNil())
+
+			targetedWorkspaceCommandOptionsModel := schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", []string{"ibm_is_vpc.*"}).
+				SetTfVars([]string{"region=us-south"})
+			applyResult, response, operationErr = schematicsService.ApplyWorkspaceTargets(targetedWorkspaceCommandOptionsModel)
+			Expect(operationErr).To(BeNil())
+			Expect(response).ToNot(BeNil())
+			Expect(*applyResult.Activityid).To(Equal("act-1"))
+			Expect(submitted.Target).To(Equal([]string{"ibm_is_vpc.vpc"}))
+			Expect(submitted.TfVars).To(Equal([]string{"region=us-south"}))
+
+			destroyResult, _, operationErr := schematicsService.DestroyWorkspaceTargets(targetedWorkspaceCommandOptionsModel.SetTargets([]string{"module.network.ibm_is_subnet.subnet[1]"}))
+			Expect(operationErr).To(BeNil())
+			Expect(*destroyResult.Activityid).To(Equal("act-1"))
+			Expect(submitted.Target).To(Equal([]string{"module.network.ibm_is_subnet.subnet[1]"}))
+		})
+		It(`Invoke ApplyWorkspaceTargets on a resource that is not in the state yet`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			targets := []string{"module.network.ibm_is_subnet.subnet[2]", "ibm_is_public_gateway.gateway"}
+			result, _, operationErr := schematicsService.ApplyWorkspaceTargets(schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", targets))
+			Expect(operationErr).To(BeNil())
+			Expect(*result.Activityid).To(Equal("act-1"))
+			Expect(submitted.Target).To(Equal([]string{"ibm_is_public_gateway.gateway", "module.network.ibm_is_subnet.subnet[2]"}))
+
+			// Patterns still have to select resources of the state.
+			submitted = nil
+			result, _, operationErr = schematicsService.ApplyWorkspaceTargets(schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", []string{"ibm_is_public_gateway.*"}))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(result).To(BeNil())
+			Expect(submitted).To(BeNil())
+		})
+		It(`Do not submit a destroy with a mistyped target`, func() {
+			schematicsService, serviceErr := schematicsv1.NewSchematicsV1(&schematicsv1.SchematicsV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			result, _, operationErr := schematicsService.DestroyWorkspaceTargets(schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", []string{"module.netwrk.ibm_is_subnet.subnet"}))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(result).To(BeNil())
+
+			result, _, operationErr = schematicsService.DestroyWorkspaceTargets(schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", []string{}))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(result).To(BeNil())
+
+			result, _, operationErr = schematicsService.DestroyWorkspaceTargets(schematicsService.NewTargetedWorkspaceCommandOptions("ws-1", "testString", []string{"ibm_is_vpc.vpc"}).SetTfVars([]string{"bad"}))
+			Expect(operationErr).ToNot(BeNil())
+			Expect(result).To(BeNil())
+			Expect(submitted).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})